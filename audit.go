@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"mysqlbinlogo/src"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditTables []string
+	auditSince  time.Duration
+)
+
+// audit 서브커맨드 (테이블을 누가, 언제, 어떤 행을 바꿨는지)
+func newAuditCmd() *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report who changed a table: transactions, accounts and primary keys of every change",
+		Example: `  mysqlbinlogo audit --table shop.orders --since 4h
+  mysqlbinlogo audit --table shop.orders --table shop.order_items -s "2024-01-15 10:00:00" -e "2024-01-15 11:00:00"`,
+		Args: cobra.NoArgs,
+		Run:  runAudit,
+	}
+	auditCmd.Flags().StringArrayVar(&auditTables, "table", nil, "Table to audit (db.table, repeatable)")
+	auditCmd.Flags().DurationVar(&auditSince, "since", 0, "Audit the last duration up to now instead of --start-time/--end-time (e.g. 4h)")
+	return auditCmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) {
+	requireConnectionFlags(cmd)
+
+	cfg := buildConfig()
+	if auditSince > 0 {
+		if startTime != "" || endTime != "" {
+			logrus.Info("--since는 --start-time, --end-time과 함께 사용할 수 없습니다")
+			exit(1)
+		}
+		cfg.EndTime = time.Now().UTC()
+		cfg.StartTime = cfg.EndTime.Add(-auditSince)
+	} else {
+		cfg.StartTime, cfg.EndTime = parseTimeRange()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	auditor := &src.Auditor{Config: cfg, Tables: auditTables}
+	if err := auditor.Run(ctx); err != nil {
+		logrus.Infof("audit 중 오류 발생: %v\n", err)
+		exit(1)
+	}
+}
@@ -1,9 +1,19 @@
 package config
 
 import (
+	"crypto/tls"
+	"io"
+	"log/slog"
 	"time"
 )
 
+// 상세 출력 수준 (-v, -vv, -vvv)
+const (
+	VerboseFiles  = 1 // 파일 선별 결과와 파일별 처리 현황 (진행률바 대신 출력)
+	VerboseProbe  = 2 // 파일마다 시작 시간 확인, 재시도, 파일 읽기 종료 사유
+	VerboseEvents = 3 // 이벤트마다 추적
+)
+
 // MySQL 연결 및 분석 설정
 type Config struct {
 	Host       string
@@ -13,8 +23,119 @@ type Config struct {
 	StartTime  time.Time
 	EndTime    time.Time
 	OutputFile string
-	Verbose    bool
+	SplitBy    string // 결과 파일 분할 기준 (file, database, 비어 있으면 OutputFile 하나)
+	Verbose    int    // 상세 출력 수준 (0: 진행률바, VerboseFiles, VerboseProbe, VerboseEvents)
 	Workers    int
+	Backend    string // 추출 백엔드 (native, canal)
+
+	SplitNameSep string // 분할 파일 이름에서 --output과 기준 값 사이의 구분자 (기본 ".")
+
+	AppendOutput    bool // 결과 파일이 이미 있으면 끝에 이어 씀
+	OverwriteOutput bool // 결과 파일이 이미 있으면 덮어씀 (둘 다 아니면 오류)
+
+	Compress string // 결과 파일 압축 (gzip, zstd, 비어 있으면 압축하지 않음)
+
+	OutputRotateSize   int64 // 결과를 이 바이트 수마다 번호가 붙은 새 파일로 (0이면 사용 안 함)
+	OutputRotateEvents int   // 결과를 이 이벤트 수마다 번호가 붙은 새 파일로 (0이면 사용 안 함)
+
+	BinlogFiles []string // 분석할 binary log 파일 (이름, 번호 또는 범위, 지정하면 시간으로 파일을 찾지 않음)
+
+	ProgressFormat string        // 진행률 출력 형식 (bar, json)
+	FileTimeBuffer time.Duration // 대상 파일 선별 시 검색 범위를 앞뒤로 확장할 시간
+	ProbeRetries   int           // 파일 시간 범위 확인 실패 시 재시도 횟수
+	ProbeBackoff   time.Duration // 재시도 간격
+	PastEndEvents  int           // 종료 시간 이후 이벤트가 이만큼 연속되면 파일 처리 종료
+	ReplicationLag bool          // 구간의 트랜잭션별 복제 지연 차트 출력 (MySQL 8.0.1 이상)
+	AccountSummary bool          // 계정(user@host)별 문장 수 요약 출력
+	WarnRows       int           // 변경 행 수가 이보다 많은 row 이벤트를 경고 (0이면 사용 안 함)
+	HotRowsTop     int           // 가장 자주 변경된 행(PK)을 이만큼 요약 출력 (0이면 사용 안 함)
+	ConflictWindow time.Duration // 같은 행을 서로 다른 트랜잭션이 이 간격 안에 잇달아 갱신하면 보고 (0이면 사용 안 함)
+
+	MaxEventsPerFile int // 파일마다 읽을 구간 안 이벤트 수 상한 (넘으면 경고 후 파일 처리 종료, 0이면 제한 없음)
+	ProbeEvents      int // 파일 시작 시간을 찾을 때 파일 앞부분에서 읽을 이벤트 수 상한
+
+	Deadline time.Duration // 접속부터 추출까지의 시간 제한 (지나면 그때까지 읽은 결과를 출력, 0이면 제한 없음)
+
+	Incremental bool   // 지난 실행이 끝난 위치부터 현재까지 분석하고 끝난 위치를 다시 기록
+	StateFile   string // 증분 분석 위치를 기록하는 JSON 파일
+
+	Hook string // 출력 전에 이벤트마다 JSON 한 줄을 보내 바꾸거나 버릴 외부 명령 (셸로 실행, 비어 있으면 사용 안 함)
+
+	RetentionAdvice bool // 대상 파일의 쓰기량과 로테이션 주기로 binlog 보존 기간과 예상 저장 공간 권장
+	SchemaDrift     bool // 이벤트가 참조하는 테이블, 컬럼을 현재 스키마와 비교하여 달라진 것을 보고
+
+	Timeline         string        // 구간별 문장/트랜잭션 수 시계열 파일 (.json이면 JSON, 그 밖에는 CSV)
+	TimelineInterval time.Duration // 시계열 구간 폭
+
+	CapacityReport string // 테이블별 시간당 이벤트, 행, 바이트 수 파일 (.json이면 JSON, 그 밖에는 CSV)
+	CapacitySort   string // 테이블 정렬 기준 (bytes, rows, events, table)
+
+	ConfirmOver int64 // 대상 파일 크기 합(예상 출력 크기)이 이 바이트 수보다 크면 추출 전에 확인 (0이면 사용 안 함)
+	AssumeYes   bool  // 확인 없이 진행 (--yes)
+
+	PushgatewayURL     string // 실행 종료 시 지표를 보낼 Prometheus Pushgateway 주소 (비어 있으면 사용 안 함)
+	PushgatewayCluster string // 지표의 cluster 레이블 (비어 있으면 host:port)
+	SummaryJSON        string // 실행 종료 시 옵션, 파일별 처리 결과, 이벤트 수, 오류를 기록할 JSON 파일
+
+	HistoryDB string // 실행 요약과 유형/테이블별 이벤트 수, 문장 fingerprint를 쌓는 SQLite 파일 (diff)
+
+	SSLMode string      // DISABLED, REQUIRED, VERIFY_CA, VERIFY_IDENTITY (비어 있으면 인증서 옵션으로 결정)
+	SSLCA   string      // CA 인증서 파일
+	SSLCert string      // 클라이언트 인증서 파일
+	SSLKey  string      // 클라이언트 키 파일
+	TLS     *tls.Config // 연결 시 SSL 옵션으로부터 생성
+
+	AuthPlugin      string // 계정의 인증 플러그인 (비어 있으면 서버와 협상)
+	AllowCleartext  bool   // mysql_clear_password 허용
+	ServerPublicKey string // SHA-2 계열 인증용 서버 RSA 공개키 파일 (없으면 서버에서 받아옴)
+
+	CompressProtocol bool // MySQL 프로토콜 압축 (메타데이터 연결과 binlog 덤프 연결)
+
+	ConnectTimeout time.Duration // TCP 연결 타임아웃
+	ReadTimeout    time.Duration // 읽기 타임아웃 (0이면 사용 안 함)
+	KeepAlive      time.Duration // TCP keepalive 주기 (0이면 사용 안 함)
+
+	SetRowsQuery       bool   // binlog_rows_query_log_events가 꺼져 있으면 SET GLOBAL로 활성화 시도
+	Replayable         bool   // mysql 클라이언트로 바로 실행할 수 있는 형태로 출력
+	EmitSessionContext bool   // 재실행 시 쿼리 앞에 원본 세션의 SET 구문 (sql_mode, 문자셋, 타임존)
+	Format             string // 출력 형식 (text, debezium)
+	Follow             bool   // 현재 위치부터 새 이벤트를 실시간으로 추적
+
+	CSVColumns []string // --format csv로 출력할 컬럼과 순서 (비어 있으면 기본 컬럼)
+
+	OutputTemplate string // 이벤트마다 실행할 Go text/template 파일 (--output-template, 텍스트 형식 대신)
+
+	ExcludeTableRegex string        // 제외할 테이블 정규식 (schema.table 형태에 매칭)
+	Where             []string      // row 값 조건 (db.table.col = value)
+	MinExecTime       time.Duration // 실행 시간(exec_time)이 이보다 짧은 이벤트 제외 (0이면 사용 안 함)
+	OnlyErrors        bool          // 오류 코드가 기록된 쿼리 이벤트만 출력
+
+	FilterExpr string // 이벤트 조건식 (event.Database == "shop" && rows > 100, 비어 있으면 사용 안 함)
+
+	Database      string // 이 데이터베이스의 이벤트만 출력 (mysqlbinlog --database)
+	StartPosition uint32 // 첫 대상 파일에서 이 위치보다 앞의 이벤트 제외 (mysqlbinlog --start-position)
+	StopPosition  uint32 // 마지막 대상 파일에서 이 위치부터의 이벤트 제외 (mysqlbinlog --stop-position)
+
+	Sink      string // 이벤트 전송 대상 (bigquery, postgres, pubsub, rabbitmq, cloudwatch, sqlite, 비어 있으면 파일/표준 출력)
+	BQProject string // BigQuery 프로젝트 (비어 있으면 인증 정보에서 결정)
+	BQDataset string // BigQuery 데이터셋
+	BQTable   string // BigQuery 테이블
+	PGDSN     string // PostgreSQL 접속 문자열 (postgres://...)
+	PGTable   string // PostgreSQL 감사 테이블 (schema.table 가능)
+
+	PubSubProject string // Pub/Sub 프로젝트 (비어 있으면 인증 정보에서 결정)
+	PubSubTopic   string // Pub/Sub 토픽 ID 또는 projects/<p>/topics/<t>
+
+	AMQPURL          string // RabbitMQ 접속 URL (amqp://...)
+	AMQPExchange     string // 발행할 exchange (없으면 durable로 생성)
+	AMQPExchangeType string // exchange 종류 (topic, direct, fanout, headers)
+	AMQPPersistent   bool   // 메시지를 디스크에 저장 (delivery mode 2)
+
+	CWLogGroup  string // CloudWatch Logs 로그 그룹 (미리 만들어 두어야 함)
+	CWLogStream string // 로그 스트림 (없으면 생성, 비어 있으면 mysqlbinlogo-<host>)
+	CWRegion    string // AWS 리전 (비어 있으면 AWS SDK 기본 설정: AWS_REGION, AWS_DEFAULT_REGION, 공유 설정 파일)
+
+	SQLiteFile string // 이벤트를 저장할 SQLite 파일 (--output-sqlite, 없으면 생성)
 }
 
 // Binary log 파일 정보
@@ -27,60 +148,58 @@ type BinlogFile struct {
 
 // SQL 이벤트 정보
 type SQLEvent struct {
-	Timestamp time.Time
-	EventType string
-	Database  string
-	SQL       string
-	ServerId  uint32
-	Position  uint32
-	Filename  string // 이벤트가 발견된 바이너리 로그 파일명
+	Timestamp time.Time `json:"timestamp"` // 트랜잭션 커밋 시각 (MySQL 8.0.1 이상, 마이크로초), 없으면 이벤트 헤더 시각 (초)
+	EventType string    `json:"event_type"`
+	Database  string    `json:"database"`
+	SQL       string    `json:"sql"`
+	ServerId  uint32    `json:"server_id"`
+	Position  uint32    `json:"position"` // 이벤트 끝 위치 (end_log_pos)
+	Filename  string    `json:"filename"` // 이벤트가 발견된 바이너리 로그 파일명
+
+	StartPosition uint32 `json:"start_position"` // 이벤트 시작 위치 (mysqlbinlog의 "# at", --start-position에 사용)
+
+	OriginalSQL string `json:"original_sql,omitempty"` // Rows_query 이벤트로 기록된 원본 SQL (row 이벤트에만 해당)
+
+	EventSize uint32 `json:"event_size"`           // 이벤트 크기 (bytes)
+	ExecTime  uint32 `json:"exec_time"`            // Query 이벤트의 실행 시간 (초, row 이벤트는 트랜잭션 BEGIN의 값)
+	ErrorCode uint16 `json:"error_code,omitempty"` // Query 이벤트에 기록된 오류 코드 (일부만 실행된 문장)
+
+	Session  *SessionContext `json:"session,omitempty"`   // Query 이벤트의 세션 상태 (status vars)
+	RowCount int             `json:"row_count,omitempty"` // 변경된 행 수 (row 이벤트에만 해당)
+
+	CapturedAt *time.Time `json:"captured_at,omitempty"` // 실시간 추적 모드에서 이벤트를 수신한 시각 (그 밖에는 nil)
+
+	// 이벤트가 속한 트랜잭션 (GTID, 익명 GTID면 트랜잭션 시작 위치 filename:position)
+	Transaction string `json:"transaction,omitempty"`
+
+	// row 이벤트 전용 정보
+	Table   string          `json:"table,omitempty"` // 대상 테이블명
+	Rows    [][]interface{} `json:"-"`               // row 이미지 (UPDATE는 before/after 쌍)
+	Columns []string        `json:"-"`               // 컬럼 이름 (알 수 없으면 nil, col_N으로 출력)
+
+	// binlog_row_image=MINIMAL/NOBLOB로 기록되지 않은 컬럼 위치 (Rows와 같은 순서, 모두 기록되었으면 nil)
+	SkippedColumns [][]int `json:"-"`
+
+	// 컬럼 이름을 table map 메타데이터에서 읽음 (binlog_row_metadata=FULL, DDL 히스토리로 바꾸지 않음)
+	ColumnsFromBinlog bool `json:"-"`
 }
 
-// NullLogger implements loggers.Advanced interface to discard all logs
-type NullLogger struct{}
-
-// 기본 로그 메소드들
-func (l *NullLogger) Debug(args ...interface{}) {}
-func (l *NullLogger) Info(args ...interface{})  {}
-func (l *NullLogger) Warn(args ...interface{})  {}
-func (l *NullLogger) Error(args ...interface{}) {}
-func (l *NullLogger) Fatal(args ...interface{}) {}
-
-// 포맷 로그 메소드들
-func (l *NullLogger) Debugf(format string, args ...interface{}) {}
-func (l *NullLogger) Infof(format string, args ...interface{})  {}
-func (l *NullLogger) Warnf(format string, args ...interface{})  {}
-func (l *NullLogger) Errorf(format string, args ...interface{}) {}
-func (l *NullLogger) Fatalf(format string, args ...interface{}) {}
-
-// 줄바꿈 로그 메소드들
-func (l *NullLogger) Debugln(args ...interface{}) {}
-func (l *NullLogger) Infoln(args ...interface{})  {}
-func (l *NullLogger) Warnln(args ...interface{})  {}
-func (l *NullLogger) Errorln(args ...interface{}) {}
-func (l *NullLogger) Fatalln(args ...interface{}) {}
-
-// Panic 메소드들 (에러 발생 방지)
-func (l *NullLogger) Panic(args ...interface{})                 {}
-func (l *NullLogger) Panicf(format string, args ...interface{}) {}
-func (l *NullLogger) Panicln(args ...interface{})               {}
-
-// Print 메소드들
-func (l *NullLogger) Print(args ...interface{})                 {}
-func (l *NullLogger) Printf(format string, args ...interface{}) {}
-func (l *NullLogger) Println(args ...interface{})               {}
-
-// 추가 메소드들 (더 완전한 억제)
-func (l *NullLogger) Log(args ...interface{})                 {}
-func (l *NullLogger) Logf(format string, args ...interface{}) {}
-func (l *NullLogger) Logln(args ...interface{})               {}
-func (l *NullLogger) Write(p []byte) (n int, err error)       { return len(p), nil }
-func (l *NullLogger) Close() error                            { return nil }
-
-// go-mysql 라이브러리 특화 메소드들
-func (l *NullLogger) SetLevel(level interface{})                           {}
-func (l *NullLogger) GetLevel() interface{}                                { return "fatal" }
-func (l *NullLogger) IsLevelEnabled(level interface{}) bool                { return false }
-func (l *NullLogger) WithField(key string, value interface{}) interface{}  { return l }
-func (l *NullLogger) WithFields(fields map[string]interface{}) interface{} { return l }
-func (l *NullLogger) WithError(err error) interface{}                      { return l }
+// Query 이벤트 status vars에 기록된 원본 세션 상태 (기록되지 않은 값은 zero)
+type SessionContext struct {
+	SQLMode             *uint64 `json:"sql_mode,omitempty"` // sql_mode 비트 값
+	CharacterSetClient  uint16  `json:"character_set_client,omitempty"`
+	CollationConnection uint16  `json:"collation_connection,omitempty"`
+	CollationServer     uint16  `json:"collation_server,omitempty"`
+	CollationDatabase   uint16  `json:"collation_database,omitempty"`
+	TimeZone            string  `json:"time_zone,omitempty"`
+	Catalog             string  `json:"catalog,omitempty"` // 항상 std (SET 대상 아님)
+
+	// Q_INVOKER로 기록된 실행 계정 (SET 대상 아님)
+	InvokerUser string `json:"invoker_user,omitempty"`
+	InvokerHost string `json:"invoker_host,omitempty"`
+}
+
+// go-mysql 라이브러리 로그를 모두 버리는 로거
+func NewNullLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLEventCapturedAtJSON(t *testing.T) {
+	capturedAt := time.Date(2024, 1, 15, 10, 0, 1, 0, time.UTC)
+	tests := []struct {
+		name       string
+		capturedAt *time.Time
+		want       string // 없어야 하면 빈 문자열
+	}{
+		{"not captured", nil, ""},
+		{"captured", &capturedAt, `"captured_at":"2024-01-15T10:00:01Z"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := SQLEvent{
+				Timestamp:  time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+				EventType:  "INSERT",
+				CapturedAt: tt.capturedAt,
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if strings.Contains(string(data), "captured_at") {
+					t.Errorf("json.Marshal() = %s, want no captured_at", data)
+				}
+				return
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
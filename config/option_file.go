@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 옵션 파일에서 읽는 그룹 (뒤에 오는 그룹이 우선)
+var optionFileGroups = []string{"client", "mysqlbinlog", "mysqlbinlogo"}
+
+// MySQL 옵션 파일(.my.cnf) 읽기
+// [client], [mysqlbinlog], [mysqlbinlogo] 그룹의 값을 반환 (키의 '_'는 '-'로 통일)
+func LoadOptionFile(path string) (map[string]string, error) {
+	options := make(map[string]string)
+	groups := make(map[string]map[string]string)
+	if err := readOptionFile(path, groups, 0); err != nil {
+		return nil, err
+	}
+
+	for _, group := range optionFileGroups {
+		for key, value := range groups[group] {
+			options[key] = value
+		}
+	}
+	return options, nil
+}
+
+// 옵션 파일을 그룹별로 읽음 (!include, !includedir 지원)
+func readOptionFile(path string, groups map[string]map[string]string, depth int) error {
+	if depth > 10 {
+		return fmt.Errorf("옵션 파일 include가 너무 깊습니다: %s", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("옵션 파일 열기 실패: %v", err)
+	}
+	defer file.Close()
+
+	group := ""
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+
+		case strings.HasPrefix(line, "!include "):
+			included := resolveIncludePath(path, strings.TrimSpace(line[len("!include "):]))
+			if err := readOptionFile(included, groups, depth+1); err != nil {
+				return err
+			}
+
+		case strings.HasPrefix(line, "!includedir "):
+			dir := resolveIncludePath(path, strings.TrimSpace(line[len("!includedir "):]))
+			files, _ := filepath.Glob(filepath.Join(dir, "*.cnf"))
+			sort.Strings(files)
+			for _, included := range files {
+				if err := readOptionFile(included, groups, depth+1); err != nil {
+					return err
+				}
+			}
+
+		case line[0] == '[':
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return fmt.Errorf("%s:%d: 그룹 이름 형식 오류", path, lineNo)
+			}
+			group = strings.ToLower(strings.TrimSpace(line[1:end]))
+
+		default:
+			if group == "" {
+				return fmt.Errorf("%s:%d: 그룹 밖의 옵션", path, lineNo)
+			}
+			key, value := parseOptionLine(line)
+			if groups[group] == nil {
+				groups[group] = make(map[string]string)
+			}
+			groups[group][key] = value
+		}
+	}
+
+	return scanner.Err()
+}
+
+// include 경로는 포함하는 파일 기준 상대 경로로 해석
+func resolveIncludePath(base, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(base), path)
+}
+
+// "key = value" 한 줄 파싱 (값이 없으면 빈 문자열, 따옴표와 행 끝 주석 처리)
+func parseOptionLine(line string) (string, string) {
+	key, value, found := strings.Cut(line, "=")
+	key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "_", "-")
+	if !found {
+		return key, ""
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return key, unescapeOptionValue(value[1 : end+1])
+		}
+	}
+
+	// 따옴표 없는 값의 # 주석 제거
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return key, unescapeOptionValue(value)
+}
+
+// 옵션 값의 이스케이프 시퀀스 처리 (\n, \t, \\ 등)
+func unescapeOptionValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'b':
+			sb.WriteByte('\b')
+		case 's':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(value[i])
+		}
+	}
+	return sb.String()
+}
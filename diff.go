@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"mysqlbinlogo/src"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffBeforeStart string
+	diffBeforeEnd   string
+	diffAfterStart  string
+	diffAfterEnd    string
+	historyLimit    int
+)
+
+// diff 서브커맨드 (배포 전후처럼 두 실행 또는 두 구간의 결과 비교)
+func newDiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff [BEFORE_RUN AFTER_RUN]",
+		Short: "Compare two recorded runs or two time windows: events per table and type, new statement fingerprints",
+		Example: `  mysqlbinlogo diff --history-db runs.db 12 15
+  mysqlbinlogo diff --before-start "2024-01-15 09:00:00" --before-end "2024-01-15 10:00:00" \
+    --after-start "2024-01-15 10:30:00" --after-end "2024-01-15 11:30:00"`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return errors.New(src.T("실행 번호는 두 개를 지정해야 합니다 (이전, 이후)"))
+			}
+			return nil
+		},
+		Run: runDiff,
+	}
+	diffCmd.Flags().StringVar(&diffBeforeStart, "before-start", "", "Start of the window before the change (same formats as --start-time)")
+	diffCmd.Flags().StringVar(&diffBeforeEnd, "before-end", "", "End of the window before the change")
+	diffCmd.Flags().StringVar(&diffAfterStart, "after-start", "", "Start of the window after the change")
+	diffCmd.Flags().StringVar(&diffAfterEnd, "after-end", "", "End of the window after the change")
+	return diffCmd
+}
+
+// history 서브커맨드 (--history-db에 기록된 최근 실행 목록)
+func newHistoryCmd() *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:     "history",
+		Short:   "List the runs recorded in --history-db",
+		Example: `  mysqlbinlogo history --history-db runs.db --limit 50`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if historyDB == "" {
+				logrus.Info(src.T("--history-db를 지정해야 합니다"))
+				exit(1)
+			}
+			if err := src.ListRuns(os.Stdout, historyDB, historyLimit); err != nil {
+				logrus.Infof(src.T("실행 이력 조회 중 오류 발생: %v\n"), err)
+				exit(1)
+			}
+		},
+	}
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of most recent runs to list")
+	return historyCmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	differ := &src.RunDiff{Config: buildConfig()}
+
+	var err error
+	if len(args) == 2 {
+		// 기록된 실행 번호로 비교
+		var ids [2]int64
+		for i, arg := range args {
+			id, parseErr := strconv.ParseInt(arg, 10, 64)
+			if parseErr != nil {
+				logrus.Infof(src.T("실행 번호가 올바르지 않습니다: %s"), arg)
+				exit(1)
+			}
+			ids[i] = id
+		}
+		err = differ.CompareRuns(ids[0], ids[1])
+	} else {
+		// 두 구간을 분석하여 비교
+		requireConnectionFlags(cmd)
+		before := parseDiffWindow("--before-start", diffBeforeStart, "--before-end", diffBeforeEnd)
+		after := parseDiffWindow("--after-start", diffAfterStart, "--after-end", diffAfterEnd)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err = differ.CompareWindows(ctx, before, after)
+	}
+	if err != nil {
+		logrus.Infof(src.T("diff 중 오류 발생: %v\n"), err)
+		exit(1)
+	}
+}
+
+// 비교 구간 하나 검증 (오류 시 종료)
+func parseDiffWindow(startFlag, startValue, endFlag, endValue string) src.TimeWindow {
+	if startValue == "" || endValue == "" {
+		logrus.Info(src.T("실행 번호 두 개 또는 --before-start, --before-end, --after-start, --after-end를 지정해야 합니다"))
+		exit(1)
+	}
+	var window src.TimeWindow
+	var err error
+	if window.Start, err = src.ParseTime(startValue); err != nil {
+		logrus.Infof(src.T("%s 형식이 올바르지 않습니다: %v\n"), startFlag, err)
+		exit(1)
+	}
+	if window.End, err = src.ParseTime(endValue); err != nil {
+		logrus.Infof(src.T("%s 형식이 올바르지 않습니다: %v\n"), endFlag, err)
+		exit(1)
+	}
+	if window.Start.After(window.End) {
+		logrus.Infof(src.T("%s가 %s보다 늦을 수 없습니다"), startFlag, endFlag)
+		exit(1)
+	}
+	return window
+}
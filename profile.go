@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	httppprof "net/http/pprof"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	cpuProfileFile *os.File
+	stopProfile    sync.Once
+)
+
+// 프로파일링 시작 (--pprof, --cpuprofile, 설정을 적용한 뒤 명령 실행 전에 호출)
+func startProfiling() error {
+	if pprofAddr != "" {
+		listener, err := net.Listen("tcp", pprofAddr)
+		if err != nil {
+			return fmt.Errorf("--pprof 주소를 열 수 없습니다: %v", err)
+		}
+		// 서버 모드의 웹 UI와 섞이지 않도록 별도 mux 사용
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", httppprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+		go http.Serve(listener, mux)
+		logrus.Infof("pprof: http://%s/debug/pprof/", listener.Addr())
+	}
+
+	if cpuProfile != "" {
+		file, err := os.Create(cpuProfile)
+		if err != nil {
+			return fmt.Errorf("CPU 프로파일 파일 생성 실패: %v", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return fmt.Errorf("CPU 프로파일 시작 실패: %v", err)
+		}
+		cpuProfileFile = file
+	}
+	return nil
+}
+
+// CPU 프로파일을 마치고 힙 프로파일 기록 (--cpuprofile, --memprofile, 종료 직전에 한 번만)
+func stopProfiling() {
+	stopProfile.Do(func() {
+		if cpuProfileFile != nil {
+			pprof.StopCPUProfile()
+			cpuProfileFile.Close()
+		}
+		if memProfile != "" {
+			file, err := os.Create(memProfile)
+			if err != nil {
+				logrus.Warnf("메모리 프로파일 파일 생성 실패: %v", err)
+				return
+			}
+			defer file.Close()
+			runtime.GC() // 최신 할당 상태 반영
+			if err := pprof.WriteHeapProfile(file); err != nil {
+				logrus.Warnf("메모리 프로파일 기록 실패: %v", err)
+			}
+		}
+	})
+}
+
+// 프로파일을 마무리하고 종료 (os.Exit는 defer를 실행하지 않음)
+func exit(code int) {
+	stopProfiling()
+	os.Exit(code)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mysqlbinlogo/src"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	targetDSN     string
+	replayBatch   int
+	replayDryRun  bool
+	replayOnError string
+
+	checkConflicts bool
+	conflictReport string
+)
+
+// replay 서브커맨드 (추출한 SQL을 대상 데이터베이스에 실행)
+func newReplayCmd() *cobra.Command {
+	replayCmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Apply the extracted SQL for a time range to a target database",
+		Args:  cobra.NoArgs,
+		Run:   runReplay,
+	}
+	replayCmd.Flags().StringVar(&targetDSN, "target-dsn", "", "Target database DSN (user:pass@tcp(host:3306)/)")
+	replayCmd.Flags().IntVar(&replayBatch, "batch-transactions", 1, "Number of original transactions merged into one commit")
+	replayCmd.Flags().BoolVar(&replayDryRun, "dry-run", false, "Print the statements that would be executed instead of running them")
+	replayCmd.Flags().StringVar(&replayOnError, "on-error", src.ReplayOnErrorStop, "On a failed event: stop (roll back the open transaction and exit) or skip (log it and continue)")
+	replayCmd.Flags().BoolVar(&checkConflicts, "check-conflicts", false, "Compare each target row with the event's before-image and skip conflicting events instead of applying them")
+	replayCmd.Flags().StringVar(&conflictReport, "conflict-report", "replay-conflicts.jsonl", "File for the conflict report (JSON lines) with --check-conflicts")
+	return replayCmd
+}
+
+// 분석 후 대상에 실행 (Ctrl+C 또는 SIGTERM 시 진행 중인 트랜잭션 롤백)
+func runReplay(cmd *cobra.Command, args []string) {
+	requireConnectionFlags(cmd)
+	startTimeUTC, endTimeUTC := parseTimeRange()
+
+	cfg := buildConfig()
+	cfg.StartTime = startTimeUTC
+	cfg.EndTime = endTimeUTC
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	replayer := &src.Replayer{
+		Config:            cfg,
+		TargetDSN:         targetDSN,
+		BatchTransactions: replayBatch,
+		DryRun:            replayDryRun,
+		OnError:           replayOnError,
+
+		CheckConflicts: checkConflicts,
+		ConflictReport: conflictReport,
+	}
+	if err := replayer.Run(ctx); err != nil {
+		logrus.Infof("replay 중 오류 발생: %v\n", err)
+		exit(1)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mysqlbinlogo/src"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listenAddr        string
+	jobDir            string
+	maxConcurrentJobs int
+	auditLogFile      string
+	insecureNoAuth    bool
+)
+
+// serve 서브커맨드 (HTTP 서버 모드)
+func newServeCmd() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run as an HTTP service (health checks and an analysis job API)",
+		Args:  cobra.NoArgs,
+		Run:   runServe,
+	}
+	serveCmd.Flags().StringVar(&listenAddr, "listen", ":8080", "HTTP listen address")
+	serveCmd.Flags().StringVar(&jobDir, "job-dir", "mysqlbinlogo-jobs", "Directory for analysis job state and results")
+	serveCmd.Flags().IntVar(&maxConcurrentJobs, "max-concurrent-jobs", 2, "Number of analysis jobs run at the same time (others wait in the queue)")
+	serveCmd.Flags().StringVar(&auditLogFile, "audit-log", "", "Append the API audit log (JSON lines) to this file instead of the server log")
+	serveCmd.Flags().BoolVar(&insecureNoAuth, "insecure-no-auth", false, "Allow every request without an API token when no tokens are configured (otherwise serve refuses to start)")
+	return serveCmd
+}
+
+// 서버 모드 실행 (Ctrl+C 또는 SIGTERM으로 종료)
+func runServe(cmd *cobra.Command, args []string) {
+	// 설정 파일에 접속 대상이 있으면 기본 대상 없이도 실행 가능 (요청마다 ?target=으로 선택)
+	if len(configTargets) == 0 {
+		requireConnectionFlags(cmd)
+	}
+
+	targets, err := buildTargetConfigs()
+	if err != nil {
+		logrus.Infof("접속 대상 설정 오류: %v\n", err)
+		exit(1)
+	}
+
+	// 감사 로그는 이어서 기록
+	var auditLog io.Writer
+	if auditLogFile != "" {
+		file, err := os.OpenFile(auditLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			logrus.Infof("감사 로그 파일 열기 실패: %v\n", err)
+			exit(1)
+		}
+		defer file.Close()
+		auditLog = file
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &src.Server{
+		Config:            buildConfig(),
+		Targets:           targets,
+		Addr:              listenAddr,
+		JobDir:            jobDir,
+		MaxConcurrentJobs: maxConcurrentJobs,
+		Tokens:            configTokens,
+		AuditLog:          auditLog,
+		InsecureNoAuth:    insecureNoAuth,
+	}
+	if err := server.Run(ctx); err != nil {
+		logrus.Infof("서버 실행 중 오류 발생: %v\n", err)
+		exit(1)
+	}
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"mysqlbinlogo/config"
+	"mysqlbinlogo/src"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// 환경 변수 접두사 (MYSQLBINLOGO_HOST, MYSQLBINLOGO_SSL_MODE 등)
+const envPrefix = "MYSQLBINLOGO"
+
+// 설정 값 출처
+const (
+	sourceFlag       = "flag"
+	sourceEnv        = "env"
+	sourceConfig     = "config"
+	sourceTarget     = "target:"
+	sourceOptionFile = "option-file"
+	sourceDefault    = "default"
+)
+
+var (
+	configFile string
+
+	// 플래그별 최종 값의 출처 (config show 출력용)
+	settingSources = map[string]string{}
+
+	// 설정 파일의 이름 있는 접속 대상 (targets.<name>)
+	configTargets map[string]map[string]interface{}
+
+	// 설정 파일의 서버 모드 API 토큰 (tokens.<name>)
+	configTokens []src.APIToken
+)
+
+// 접속 대상별로 지정할 수 있는 옵션 (연결 관련 플래그)
+var targetKeys = map[string]bool{
+	"host":              true,
+	"port":              true,
+	"user":              true,
+	"password":          true,
+	"ssl-mode":          true,
+	"ssl-ca":            true,
+	"ssl-cert":          true,
+	"ssl-key":           true,
+	"auth-plugin":       true,
+	"allow-cleartext":   true,
+	"server-public-key": true,
+	"compress-protocol": true,
+	"connect-timeout":   true,
+	"read-timeout":      true,
+	"tcp-keepalive":     true,
+}
+
+// 설정 파일, 환경 변수, 옵션 파일을 플래그에 반영
+// 우선순위: 플래그 > 환경 변수 > 접속 대상(--target) > 설정 파일 > 옵션 파일(--defaults-file) > 기본값
+func loadSettings(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	flags.Visit(func(f *pflag.Flag) {
+		settingSources[f.Name] = sourceFlag
+	})
+
+	v := viper.New()
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	if err := readConfigFile(v); err != nil {
+		return err
+	}
+	if err := v.BindPFlags(flags); err != nil {
+		return err
+	}
+
+	targets, err := readTargets(v)
+	if err != nil {
+		return err
+	}
+	configTargets = targets
+
+	tokens, err := readTokens(v)
+	if err != nil {
+		return err
+	}
+	configTokens = tokens
+
+	// --target으로 선택한 접속 대상은 설정 파일의 최상위 값보다 우선
+	targetName := strings.ToLower(v.GetString("target"))
+	target, ok := targets[targetName]
+	if targetName != "" && !ok {
+		return fmt.Errorf("설정 파일에 접속 대상이 없습니다: %s", targetName)
+	}
+
+	var applyErr error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if applyErr != nil || f.Changed || f.Name == "help" {
+			return
+		}
+
+		var value interface{}
+		var source string
+		if _, ok := os.LookupEnv(envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))); ok {
+			value, source = v.Get(f.Name), sourceEnv
+		} else if targetValue, ok := target[f.Name]; ok {
+			value, source = targetValue, sourceTarget+targetName
+		} else if v.IsSet(f.Name) {
+			value, source = v.Get(f.Name), sourceConfig
+		} else {
+			return
+		}
+
+		for _, value := range settingValues(value) {
+			if err := flags.Set(f.Name, value); err != nil {
+				applyErr = fmt.Errorf("%s 값 오류 (%s): %v", f.Name, source, err)
+				return
+			}
+		}
+		settingSources[f.Name] = source
+	})
+	if applyErr != nil {
+		return applyErr
+	}
+
+	if defaultsFile != "" {
+		if err := applyDefaultsFile(cmd, defaultsFile); err != nil {
+			return fmt.Errorf("옵션 파일 처리 실패: %v", err)
+		}
+	}
+	if messages != "" {
+		if err := src.LoadMessages(messages); err != nil {
+			return err
+		}
+	}
+	return src.SetLanguage(lang)
+}
+
+// --config로 지정한 파일, 없으면 기본 위치의 mysqlbinlogo.{yaml,toml,json} 읽기
+func readConfigFile(v *viper.Viper) error {
+	if configFile == "" {
+		configFile = os.Getenv(envPrefix + "_CONFIG")
+	}
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("설정 파일 읽기 실패: %v", err)
+		}
+		return nil
+	}
+
+	v.SetConfigName("mysqlbinlogo")
+	v.AddConfigPath(".")
+	if home, err := os.UserHomeDir(); err == nil {
+		v.AddConfigPath(filepath.Join(home, ".config", "mysqlbinlogo"))
+	}
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return fmt.Errorf("설정 파일 읽기 실패: %v", err)
+		}
+	}
+	return nil
+}
+
+// 설정 파일의 targets 섹션 읽기
+//
+//	targets:
+//	  prod-writer:
+//	    host: writer.example.com
+//	    user: admin
+func readTargets(v *viper.Viper) (map[string]map[string]interface{}, error) {
+	targets := make(map[string]map[string]interface{})
+	for name, value := range v.GetStringMap("targets") {
+		settings, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("접속 대상 %s 형식 오류", name)
+		}
+		for key := range settings {
+			if !targetKeys[key] {
+				return nil, fmt.Errorf("접속 대상 %s: 지원하지 않는 옵션 %s", name, key)
+			}
+		}
+		targets[name] = settings
+	}
+	return targets, nil
+}
+
+// 설정 파일의 tokens 섹션 읽기 (서버 모드 API 토큰)
+//
+//	tokens:
+//	  alice:
+//	    token: 3f9c...
+//	    role: operator
+func readTokens(v *viper.Viper) ([]src.APIToken, error) {
+	var tokens []src.APIToken
+	for name, value := range v.GetStringMap("tokens") {
+		settings, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("API 토큰 %s 형식 오류", name)
+		}
+		for key := range settings {
+			if key != "token" && key != "role" {
+				return nil, fmt.Errorf("API 토큰 %s: 지원하지 않는 옵션 %s", name, key)
+			}
+		}
+		tokens = append(tokens, src.APIToken{
+			Name:  name,
+			Token: fmt.Sprint(settings["token"]),
+			Role:  strings.ToLower(fmt.Sprint(settings["role"])),
+		})
+	}
+	if err := src.ValidateTokens(tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// 기본 설정에 접속 대상의 연결 옵션을 덮어쓴 설정 (서버 모드의 요청별 대상 선택용)
+func targetConfig(base config.Config, settings map[string]interface{}) (config.Config, error) {
+	cfg := base
+	fs := pflag.NewFlagSet("target", pflag.ContinueOnError)
+	fs.StringVar(&cfg.Host, "host", cfg.Host, "")
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "")
+	fs.StringVar(&cfg.User, "user", cfg.User, "")
+	fs.StringVar(&cfg.Password, "password", cfg.Password, "")
+	fs.StringVar(&cfg.SSLMode, "ssl-mode", cfg.SSLMode, "")
+	fs.StringVar(&cfg.SSLCA, "ssl-ca", cfg.SSLCA, "")
+	fs.StringVar(&cfg.SSLCert, "ssl-cert", cfg.SSLCert, "")
+	fs.StringVar(&cfg.SSLKey, "ssl-key", cfg.SSLKey, "")
+	fs.StringVar(&cfg.AuthPlugin, "auth-plugin", cfg.AuthPlugin, "")
+	fs.BoolVar(&cfg.AllowCleartext, "allow-cleartext", cfg.AllowCleartext, "")
+	fs.StringVar(&cfg.ServerPublicKey, "server-public-key", cfg.ServerPublicKey, "")
+	fs.BoolVar(&cfg.CompressProtocol, "compress-protocol", cfg.CompressProtocol, "")
+	fs.DurationVar(&cfg.ConnectTimeout, "connect-timeout", cfg.ConnectTimeout, "")
+	fs.DurationVar(&cfg.ReadTimeout, "read-timeout", cfg.ReadTimeout, "")
+	fs.DurationVar(&cfg.KeepAlive, "tcp-keepalive", cfg.KeepAlive, "")
+
+	for key, value := range settings {
+		for _, value := range settingValues(value) {
+			if err := fs.Set(key, value); err != nil {
+				return cfg, fmt.Errorf("%s 값 오류: %v", key, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// 모든 접속 대상의 설정 생성
+func buildTargetConfigs() (map[string]config.Config, error) {
+	targets := make(map[string]config.Config, len(configTargets))
+	for name, settings := range configTargets {
+		cfg, err := targetConfig(buildConfig(), settings)
+		if err != nil {
+			return nil, fmt.Errorf("접속 대상 %s: %v", name, err)
+		}
+		targets[name] = cfg
+	}
+	return targets, nil
+}
+
+// viper 값을 플래그 문자열로 변환 (목록은 항목별로 Set)
+func settingValues(value interface{}) []string {
+	switch values := value.(type) {
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, item := range values {
+			result = append(result, fmt.Sprint(item))
+		}
+		return result
+	case []string:
+		return values
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
+// --verbose 값 (-v를 반복한 횟수, 설정 파일과 환경 변수에서는 수준 숫자 또는 이전 형식의 true/false)
+type verbosityValue int
+
+func (v *verbosityValue) Set(s string) error {
+	if s == "+1" {
+		*v++
+		return nil
+	}
+	if enabled, err := strconv.ParseBool(s); err == nil {
+		*v = 0
+		if enabled {
+			*v = config.VerboseFiles
+		}
+		return nil
+	}
+	level, err := strconv.Atoi(s)
+	if err != nil || level < 0 {
+		return fmt.Errorf("상세 출력 수준은 0 이상의 숫자 또는 true/false여야 합니다: %s", s)
+	}
+	*v = verbosityValue(level)
+	return nil
+}
+
+func (v *verbosityValue) String() string {
+	return strconv.Itoa(int(*v))
+}
+
+// pflag가 -vvv 형태의 반복을 허용하도록 count 형식으로 표시
+func (v *verbosityValue) Type() string {
+	return "count"
+}
+
+// --confirm-over, --output-rotate-size 값 (바이트 수, 1GB처럼 단위를 붙일 수 있음)
+type byteSizeValue int64
+
+func (v *byteSizeValue) Set(s string) error {
+	size, err := src.ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*v = byteSizeValue(size)
+	return nil
+}
+
+func (v *byteSizeValue) String() string {
+	return strconv.FormatInt(int64(*v), 10)
+}
+
+func (v *byteSizeValue) Type() string {
+	return "size"
+}
+
+// config 서브커맨드
+func newConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration and where each value comes from",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			printSettings(cmd.OutOrStdout(), cmd.Flags())
+		},
+	})
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "messages",
+		Short: "Print every message of the current language as a --messages file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return src.WriteMessages(cmd.OutOrStdout())
+		},
+	})
+	return configCmd
+}
+
+// 최종 설정을 YAML 형태로 출력 (그대로 --config 파일로 사용 가능, 비밀번호는 가림)
+func printSettings(out io.Writer, flags *pflag.FlagSet) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" || f.Name == "config" {
+			return
+		}
+
+		source := settingSources[f.Name]
+		if source == "" {
+			source = sourceDefault
+		}
+		fmt.Fprintf(w, "%s:\t%s\t# %s\n", f.Name, formatSetting(f), source)
+	})
+	w.Flush()
+}
+
+// config show에서 값을 가리는 옵션 (비밀번호가 포함될 수 있음)
+var secretSettings = map[string]bool{
+	"password": true,
+	"pg-dsn":   true,
+	"amqp-url": true,
+}
+
+// 플래그 값을 YAML 값으로 표현
+func formatSetting(f *pflag.Flag) string {
+	if secretSettings[f.Name] && f.Value.String() != "" {
+		return strconv.Quote("****")
+	}
+
+	switch f.Value.Type() {
+	case "string":
+		return strconv.Quote(f.Value.String())
+	case "stringArray", "stringSlice":
+		var quoted []string
+		if values, ok := f.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				quoted = append(quoted, strconv.Quote(value))
+			}
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	default:
+		return f.Value.String()
+	}
+}
@@ -0,0 +1,122 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// 계정 요약에 출력하는 최대 계정 수
+const accountSummaryLimit = 20
+
+// SQL 주석 (/*! */, /*+ */처럼 실행되는 주석은 제외)
+var sqlComment = regexp.MustCompile(`/\*([^!+][\s\S]*?)\*/`)
+
+// 주석 안의 계정 정보 (user=app_rw host=10.0.0.5, user:'app_rw', app_rw@10.0.0.5)
+var (
+	commentUser    = regexp.MustCompile(`(?i)\b(?:db_user|user)\s*[=:]\s*'?([^'\s,*]+)`)
+	commentHost    = regexp.MustCompile(`(?i)\b(?:client_host|host)\s*[=:]\s*'?([^'\s,*]+)`)
+	commentAccount = regexp.MustCompile("'?([A-Za-z0-9_.$-]+)'?@'?([A-Za-z0-9_.:%-]+)'?")
+)
+
+// 계정별 문장 집계
+type accountActivity struct {
+	account    string // user@host
+	statements int
+	rows       int
+	first      time.Time
+	last       time.Time
+}
+
+// 이벤트를 실행한 계정 (Q_INVOKER, 없으면 원본 SQL 주석, 알 수 없으면 빈 문자열)
+func eventAccount(event *config.SQLEvent) string {
+	if session := event.Session; session != nil && session.InvokerUser != "" {
+		return session.InvokerUser + "@" + session.InvokerHost
+	}
+
+	// row 이벤트는 Rows_query의 원본 SQL, 쿼리 이벤트는 SQL 자체에 애플리케이션이 남긴 주석
+	query := event.OriginalSQL
+	if event.EventType == "QUERY" {
+		query = event.SQL
+	}
+	for _, match := range sqlComment.FindAllStringSubmatch(query, -1) {
+		comment := match[1]
+		user := commentUser.FindStringSubmatch(comment)
+		if user != nil {
+			account := user[1] + "@"
+			if host := commentHost.FindStringSubmatch(comment); host != nil {
+				account += host[1]
+			} else {
+				account += "%"
+			}
+			return account
+		}
+		if account := commentAccount.FindStringSubmatch(comment); account != nil {
+			return account[1] + "@" + account[2]
+		}
+	}
+	return ""
+}
+
+// 계정별 문장 수, 변경 행 수, 처음/마지막 시각 출력 (--account-summary)
+func writeAccountSummary(w io.Writer, events []config.SQLEvent) {
+	activities := make(map[string]*accountActivity)
+	known := 0
+	for i := range events {
+		event := &events[i]
+		account := eventAccount(event)
+		if account == "" {
+			continue
+		}
+		known++
+
+		activity := activities[account]
+		if activity == nil {
+			activity = &accountActivity{account: account, first: event.Timestamp}
+			activities[account] = activity
+		}
+		activity.statements++
+		activity.rows += event.RowCount
+		if event.Timestamp.Before(activity.first) {
+			activity.first = event.Timestamp
+		}
+		if event.Timestamp.After(activity.last) {
+			activity.last = event.Timestamp
+		}
+	}
+
+	if known == 0 {
+		fmt.Fprintln(w, T(">> 계정별 활동: 계정 정보가 있는 이벤트가 없습니다 (Q_INVOKER 또는 SQL 주석 필요)"))
+		return
+	}
+
+	sorted := make([]*accountActivity, 0, len(activities))
+	for _, activity := range activities {
+		sorted = append(sorted, activity)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].statements != sorted[j].statements {
+			return sorted[i].statements > sorted[j].statements
+		}
+		return sorted[i].account < sorted[j].account
+	})
+
+	fmt.Fprintf(w, T(">> 계정별 활동 (계정 정보가 있는 이벤트 %d개 / 전체 %d개):\n"), known, len(events))
+	width := 0
+	for _, activity := range sorted[:min(len(sorted), accountSummaryLimit)] {
+		width = max(width, len(activity.account))
+	}
+	for _, activity := range sorted[:min(len(sorted), accountSummaryLimit)] {
+		fmt.Fprintf(w, T("   %s  문장 %d개, 행 %d개, %s ~ %s\n"),
+			activity.account+strings.Repeat(" ", width-len(activity.account)), activity.statements, activity.rows,
+			activity.first.UTC().Format("2006-01-02 15:04:05"), activity.last.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if len(sorted) > accountSummaryLimit {
+		fmt.Fprintf(w, T("   외 %d개 계정\n"), len(sorted)-accountSummaryLimit)
+	}
+}
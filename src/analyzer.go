@@ -1,10 +1,14 @@
 package src
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,7 +18,6 @@ import (
 
 	"mysqlbinlogo/config"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/schollz/progressbar/v3"
 )
 
@@ -22,27 +25,160 @@ import (
 type BinlogAnalyzer struct {
 	Config config.Config
 	conn   *sql.DB
+	schema *SchemaSnapshot
+	filter *EventFilter
+	gtids  *gtidCoverage   // 분석 구간에서 실행된 GTID
+	lags   *replicationLag // 분석 구간의 복제 지연 (--replication-lag가 없으면 nil)
+
+	retention *retentionAdvice // binlog 보존 기간 권장 (--retention-advice가 없으면 nil)
+
+	warnings []string  // 결과가 불완전할 수 있는 이유 (결과 헤더에도 기록)
+	earliest time.Time // 요청 구간보다 늦게 시작하는 가장 오래된 binary log의 첫 이벤트 시각 (구간이 모두 남아 있으면 zero)
+
+	rowsQueryLogging string // binlog_rows_query_log_events 상태 (ON/OFF, 확인 실패 시 빈 문자열)
+
+	unresolved []*unresolvedTable // 컬럼 이름을 알 수 없어 col_N으로 출력한 테이블
+
+	run *runStats // 실행 통계 (--pushgateway-url)
+
+	cutFiles []string // --deadline으로 끝까지 읽지 못한 파일
+
+	incomplete bool // 처리에 실패했거나 끝까지 읽지 못한 파일이 있음 (--incremental 위치를 기록하지 않음)
+
+	resume *incrementalState // 지난 실행이 끝난 위치 (--incremental, 처음 실행이면 nil)
+	next   *incrementalState // 이번 실행이 끝까지 읽으면 기록할 위치
+
+	progress *progressReporter // 진행 기록 대상 (서버 모드 작업에서 지정, 없으면 --progress-format에 따름)
+	messages io.Writer         // 진행 상황/요약 메시지 출력 대상 (없으면 stderr)
+	results  io.Writer         // 결과 출력 대상 (여러 클러스터 분석의 섹션, 없으면 --output 또는 stdout)
+
+	onResults     func([]config.SQLEvent) error // 결과 이벤트를 함께 받을 곳 (서버 모드 작업의 이벤트 목록)
+	discardOutput bool                          // 결과를 출력하지 않고 onResults로만 전달 (replay)
+}
+
+// Analyze Binary log 분석 실행 (ctx 취소 시 중단)
+// 요청한 시작 시간이 남아 있는 binary log보다 이르면 결과를 출력한 뒤 ErrRangeNotCovered를 반환
+// --deadline이 지나면 그때까지 읽은 결과를 출력한 뒤 ErrDeadlineExceeded를 반환
+// --summary-json, --history-db, --pushgateway-url이 있으면 성공, 실패와 관계없이 실행 요약을 기록하고 지표를 전송
+func (ba *BinlogAnalyzer) Analyze(ctx context.Context) error {
+	if err := validatePushgateway(ba.Config); err != nil {
+		return err
+	}
+	if err := validateRunSummary(ba.Config); err != nil {
+		return err
+	}
+	if err := validateRunHistory(ba.Config); err != nil {
+		return err
+	}
+	ba.run = newRunStats()
+
+	err := ba.analyze(ctx)
+	if err == nil {
+		err = ba.coverageError()
+	}
+	if err == nil || IsIncomplete(err) {
+		err = errors.Join(err, ba.deadlineError())
+	}
+	if stateErr := ba.saveIncrementalState(err); stateErr != nil && err == nil {
+		err = stateErr
+	}
+	if summaryErr := ba.writeRunSummary(err); summaryErr != nil && err == nil {
+		err = summaryErr
+	}
+	if historyErr := ba.recordHistory(err); historyErr != nil && err == nil {
+		err = historyErr
+	}
+	ba.pushMetrics(err)
+	return err
 }
 
-// Analyze Binary log 분석 실행
-func (ba *BinlogAnalyzer) Analyze() error {
-	if ba.Config.Verbose {
+func (ba *BinlogAnalyzer) analyze(ctx context.Context) error {
+	if err := validateBackend(ba.Config.Backend); err != nil {
+		return err
+	}
+	if err := validateProgressFormat(ba.Config.ProgressFormat); err != nil {
+		return err
+	}
+	if err := validateSink(ba.Config); err != nil {
+		return err
+	}
+	if err := validateFormat(ba.Config); err != nil {
+		return err
+	}
+	if err := validateStream(ba.Config); err != nil {
+		return err
+	}
+	if err := validateSplit(ba.Config); err != nil {
+		return err
+	}
+	if err := validateOutputFile(ba.Config); err != nil {
+		return err
+	}
+	if err := validateTimeline(ba.Config); err != nil {
+		return err
+	}
+	if err := validateCapacityReport(ba.Config); err != nil {
+		return err
+	}
+	if ba.Config.RetentionAdvice && len(ba.Config.BinlogFiles) > 0 {
+		return errors.New(T("--retention-advice는 파일을 시간으로 찾을 때만 사용할 수 있습니다 (--binlog-files 제외)"))
+	}
+	if err := validateIncremental(ba.Config); err != nil {
+		return err
+	}
+	if err := ba.loadIncrementalState(); err != nil {
+		return err
+	}
+
+	filter, err := NewEventFilter(ba.Config)
+	if err != nil {
+		return err
+	}
+	ba.filter = filter
+
+	if ba.Config.Verbose >= config.VerboseFiles {
 		// verbose 모드에서는 로딩바 대신 상세 로그 출력
-		fmt.Printf("분석 시작: %s ~ %s\n",
-			ba.Config.StartTime.Format("2006-01-02 15:04:05"),
-			ba.Config.EndTime.Format("2006-01-02 15:04:05"))
-		fmt.Printf("MySQL 서버에 연결 중... %s:%d\n", ba.Config.Host, ba.Config.Port)
+		fmt.Fprintf(ba.messageOutput(), T("분석 시작: %s ~ %s\n"),
+			ba.Config.StartTime.Format("2006-01-02 15:04:05.999999"),
+			ba.Config.EndTime.Format("2006-01-02 15:04:05.999999"))
+		fmt.Fprintf(ba.messageOutput(), T("MySQL 서버에 연결 중... %s:%d\n"), ba.Config.Host, ba.Config.Port)
+	}
+
+	// --deadline은 접속부터 추출까지에 적용하고, 시간이 지나면 그때까지 읽은 결과를 출력
+	work := ctx
+	if ba.Config.Deadline > 0 {
+		var cancel context.CancelFunc
+		work, cancel = context.WithTimeout(ctx, ba.Config.Deadline)
+		defer cancel()
+	}
+
+	// --progress-format json이면 로딩바 대신 stderr로 진행 기록 출력
+	progress := ba.progress
+	if progress == nil && ba.Config.ProgressFormat == ProgressFormatJSON {
+		progress = newProgressReporter(os.Stderr)
+		defer progress.Close()
+	}
+	// 진행률 바를 그릴 수 없으면 (파일, 파이프, CI 로그) 단계와 파일 처리 상황을 한 줄씩 기록
+	if progress == nil && ba.Config.Verbose == 0 && ba.messages == nil && !detectTerminal(ba.messageOutput()).terminal {
+		progress = newPlainProgressReporter(ba.messageOutput())
+		defer progress.Close()
 	}
 
 	// verbose 모드가 아닐 때만 로딩바 사용
 	var bar *progressbar.ProgressBar
 	var totalProgressSteps int
-	if !ba.Config.Verbose {
+	if ba.Config.Verbose == 0 {
+		// 출력을 파일이나 파이프로 받으면 \r로 덮어쓰는 막대가 그대로 쌓이므로 터미널에서만 표시 (그 밖에는 위의 한 줄 기록)
+		terminal := detectTerminal(ba.messageOutput())
+
 		// 더 부드러운 진행률을 위해 더 많은 단계로 설정 (200단계)
 		bar = progressbar.NewOptions(200,
-			progressbar.OptionSetDescription("분석 진행률"),
-			progressbar.OptionSetWidth(50),
+			progressbar.OptionSetWriter(ba.messageOutput()),
+			progressbar.OptionSetDescription(T("분석 진행률")),
+			progressbar.OptionSetWidth(terminal.progressBarWidth()),
 			progressbar.OptionEnableColorCodes(false),
+			progressbar.OptionUseANSICodes(terminal.ansi),
+			progressbar.OptionSetVisibility(progress == nil && terminal.terminal),
 			progressbar.OptionSetTheme(progressbar.Theme{
 				Saucer:        "█",
 				SaucerHead:    "█",
@@ -55,91 +191,160 @@ func (ba *BinlogAnalyzer) Analyze() error {
 	}
 
 	// 1. MySQL 연결 (10%)
-	if !ba.Config.Verbose {
+	progress.Stage(progressStageConnect)
+	if ba.Config.Verbose == 0 {
 		for i := 0; i < 6; i++ {
 			bar.Add(1)
-			bar.Describe("MySQL 연결 중...")
+			bar.Describe(T("MySQL 연결 중..."))
 		}
 	}
 
 	if err := ba.connect(); err != nil {
-		return fmt.Errorf("MySQL 연결 실패: %v", err)
+		return fmt.Errorf(T("MySQL 연결 실패: %v"), err)
 	}
 	defer ba.conn.Close()
 
-	if !ba.Config.Verbose {
+	if ba.Config.Verbose == 0 {
 		for i := 0; i < 4; i++ {
 			bar.Add(1)
-			bar.Describe("MySQL 연결 완료")
+			bar.Describe(T("MySQL 연결 완료"))
 		}
 	} else {
-		fmt.Println("MySQL 연결 완료")
+		fmt.Fprintln(ba.messageOutput(), T("MySQL 연결 완료"))
 	}
 
+	ba.checkRowsQueryLogging()
+
+	// 분석 대상 테이블의 스키마 스냅샷 (테이블이 처음 발견될 때 조회)
+	ba.schema = NewSchemaSnapshot(ba.conn)
+
 	// 2. Binary log 파일 목록 가져오기 및 대상 파일 검색 (20%)
-	if !ba.Config.Verbose {
+	progress.Stage(progressStageFind)
+	if ba.Config.Verbose == 0 {
 		for i := 0; i < 10; i++ {
 			bar.Add(1)
-			bar.Describe("바이너리 로그 파일 검색 중...")
+			bar.Describe(T("바이너리 로그 파일 검색 중..."))
 		}
 	} else {
-		fmt.Println("바이너리 로그 파일 검색 중...")
+		fmt.Fprintln(ba.messageOutput(), T("바이너리 로그 파일 검색 중..."))
 	}
 
 	binlogFiles, err := ba.getBinlogFiles()
 	if err != nil {
-		return fmt.Errorf("binary log 파일 목록 가져오기 실패: %v", err)
+		return fmt.Errorf(T("binary log 파일 목록 가져오기 실패: %v"), err)
 	}
 
-	if ba.Config.Verbose {
-		fmt.Printf("총 %d개의 binary log 파일을 찾았습니다.\n", len(binlogFiles))
+	if ba.Config.Verbose >= config.VerboseFiles {
+		fmt.Fprintf(ba.messageOutput(), T("총 %d개의 binary log 파일을 찾았습니다.\n"), len(binlogFiles))
+	}
+	if err := ba.beginIncremental(binlogFiles); err != nil {
+		return err
 	}
 
-	// 시간대에 맞는 파일 찾기
-	timeFinder := NewBinlogTimeFinder(ba.conn, ba.Config)
+	var targetFiles []config.BinlogFile
+	if ba.resume != nil {
+		// 지난 실행이 끝난 파일부터 이어서 분석 (그 파일 안에서는 기록한 위치 앞의 이벤트 제외)
+		targetFiles, err = ba.resumeFiles(binlogFiles)
+		if err != nil {
+			return err
+		}
+	} else if len(ba.Config.BinlogFiles) > 0 {
+		// 파일을 직접 지정하면 시작 시간 확인 없이 그대로 분석
+		targetFiles, err = ba.selectBinlogFiles(binlogFiles, ba.Config.BinlogFiles)
+		if err != nil {
+			return err
+		}
+	} else {
+		// 시간대에 맞는 파일 찾기
+		timeFinder := NewBinlogTimeFinder(ba.conn, ba.Config)
+		ba.checkOldestBinlog(work, timeFinder, binlogFiles)
 
-	if ba.Config.Verbose {
-		fmt.Printf("파일 검색 설정 - Workers: %d\n", ba.Config.Workers)
-	}
+		if ba.Config.Verbose >= config.VerboseFiles {
+			fmt.Fprintf(ba.messageOutput(), T("파일 검색 설정 - Workers: %d\n"), ba.Config.Workers)
+		}
 
-	targetFiles, err := timeFinder.FindTargetFilesParallel(binlogFiles)
-	if err != nil {
-		return fmt.Errorf("대상 파일 찾기 실패: %v", err)
+		targetFiles, err = timeFinder.FindTargetFilesParallel(work, binlogFiles)
+		if deadlineExceeded(ctx, work) {
+			return fmt.Errorf(T("대상 파일 찾기 실패: --deadline %s 안에 끝나지 않았습니다"), ba.Config.Deadline)
+		}
+		if err != nil {
+			return fmt.Errorf(T("대상 파일 찾기 실패: %w"), err)
+		}
 	}
 
-	if !ba.Config.Verbose {
+	if ba.Config.Verbose == 0 {
 		for i := 0; i < 10; i++ {
 			bar.Add(1)
-			bar.Describe("파일 검색 완료")
+			bar.Describe(T("파일 검색 완료"))
 		}
 	} else {
-		fmt.Println("파일 검색 완료")
+		fmt.Fprintln(ba.messageOutput(), T("파일 검색 완료"))
 	}
 
 	if len(targetFiles) == 0 {
-		if !ba.Config.Verbose {
+		if ba.Config.Verbose == 0 {
 			bar.Finish()
 		}
-		fmt.Printf("\n\n지정된 시간대(%s ~ %s)에 해당하는 binary log 파일을 찾을 수 없습니다\n",
-			ba.Config.StartTime.Format("2006-01-02 15:04:05"),
-			ba.Config.EndTime.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(ba.messageOutput(), T("\n\n지정된 시간대(%s ~ %s)에 해당하는 binary log 파일을 찾을 수 없습니다\n"),
+			ba.Config.StartTime.Format("2006-01-02 15:04:05.999999"),
+			ba.Config.EndTime.Format("2006-01-02 15:04:05.999999"))
 		return nil
 	}
 
-	if ba.Config.Verbose {
-		fmt.Printf("분석 대상 파일: %d개 (처리 순서)\n", len(targetFiles))
+	if len(ba.Config.BinlogFiles) == 0 && ba.resume == nil {
+		ba.checkContinuity(binlogFiles, targetFiles)
+	}
+	if err := ba.filter.SetFileRange(targetFiles[0].Name, targetFiles[len(targetFiles)-1].Name); err != nil {
+		return err
+	}
+	ba.run.setFiles(targetFiles)
+	ba.retention = ba.adviseRetention(binlogFiles, targetFiles)
+
+	if ba.Config.Verbose >= config.VerboseFiles {
+		fmt.Fprintf(ba.messageOutput(), T("분석 대상 파일: %d개 (처리 순서)\n"), len(targetFiles))
 		for i, file := range targetFiles {
-			fmt.Printf("  %d. %s (크기: %d bytes)\n", i+1, file.Name, file.Size)
+			fmt.Fprintf(ba.messageOutput(), T("  %d. %s (크기: %d bytes)\n"), i+1, file.Name, file.Size)
 		}
 	}
 
 	// 3. SQL 이벤트 추출 (80%)
-	sqlExtractor := NewSQLExtractor(ba.Config)
+	var targetBytes int64
+	for _, file := range targetFiles {
+		targetBytes += file.Size
+	}
+	if err := ba.confirmOutputSize(len(targetFiles), targetBytes); err != nil {
+		return err
+	}
+	progress.Files(len(targetFiles), targetBytes)
+	progress.Stage(progressStageExtract)
+
+	ba.gtids = newGTIDCoverage()
+	if ba.Config.ReplicationLag {
+		ba.lags = newReplicationLag(ba.Config.StartTime, ba.Config.EndTime)
+	}
+
+	sqlExtractor := ba.newExtractor()
 	defer sqlExtractor.Close()
 
+	// --format ndjson이면 파일마다 끝나는 대로 출력하고 결과를 모으지 않음
+	var stream *eventStream
+	if ba.streaming() {
+		if stream, err = ba.newEventStream(ctx, targetFiles); err != nil {
+			return err
+		}
+		defer stream.close()
+	}
+
 	var allEvents []config.SQLEvent
+	collect := func(file config.BinlogFile, events []config.SQLEvent) error {
+		if stream != nil {
+			return stream.add(file.Name, events)
+		}
+		allEvents = append(allEvents, events...)
+		return nil
+	}
 
-	if !ba.Config.Verbose {
+	if ba.Config.Verbose == 0 {
 		// 더 부드러운 진행률을 위해 더 많은 단계로 나눔
 		progressPerFile := totalProgressSteps / len(targetFiles) // 각 파일당 진행률 단계
 		if progressPerFile < 2 {
@@ -147,8 +352,8 @@ func (ba *BinlogAnalyzer) Analyze() error {
 		}
 
 		// 병렬 처리를 위한 채널과 고루틴 사용
-		eventChan := make(chan []config.SQLEvent, len(targetFiles))
-		errorChan := make(chan error, len(targetFiles))
+		eventChan := make(chan extractResult, len(targetFiles))
+		errorChan := make(chan extractResult, len(targetFiles))
 
 		// 워커 수 결정 (파일 수와 설정된 워커 수 중 작은 값)
 		workerCount := ba.Config.Workers
@@ -170,15 +375,27 @@ func (ba *BinlogAnalyzer) Analyze() error {
 				defer wg.Done()
 				// 각 워커가 작업 채널에서 파일을 가져와서 처리
 				for file := range fileChan {
+					// --deadline이 지났으면 남은 파일은 읽지 않음
+					if work.Err() != nil {
+						progress.FileDone(file.Size, 0)
+						errorChan <- extractResult{file: file, err: work.Err(), skipped: true}
+						continue
+					}
+
 					// 각 워커별로 독립적인 SQL 추출기 생성
-					workerExtractor := NewSQLExtractor(ba.Config)
-					events, err := workerExtractor.ExtractFromSingleFile(file)
+					workerExtractor := ba.newExtractor()
+					events, err := workerExtractor.ExtractFromSingleFile(work, file)
 					workerExtractor.Close() // 즉시 종료
 
-					if err != nil {
-						errorChan <- err
+					var partial *partialReadError
+					if errors.As(err, &partial) {
+						// 끝까지 읽지 못한 파일은 읽은 만큼 결과에 포함하고 경고
+						eventChan <- extractResult{file: file, events: events, err: err}
+					} else if err != nil {
+						progress.FileDone(file.Size, 0)
+						errorChan <- extractResult{file: file, events: events, err: err}
 					} else {
-						eventChan <- events
+						eventChan <- extractResult{file: file, events: events}
 					}
 				}
 			}(i)
@@ -203,28 +420,50 @@ func (ba *BinlogAnalyzer) Analyze() error {
 		processedFiles := 0
 		for processedFiles < len(targetFiles) {
 			select {
-			case events := <-eventChan:
-				allEvents = append(allEvents, events...)
+			case <-ctx.Done():
+				return fmt.Errorf(T("분석 중단: %w"), ctx.Err())
+			case result := <-eventChan:
+				if result.err != nil {
+					ba.warnPartialRead(result.err)
+				}
+				events := ba.filter.Filter(result.events)
+				if err := collect(result.file, events); err != nil {
+					return err
+				}
 				processedFiles++
+				ba.run.fileDone(result.file.Name, len(events), nil)
+				progress.FileDone(result.file.Size, len(events))
 
 				// 더 부드러운 진행률 업데이트
 				for j := 0; j < progressPerFile; j++ {
 					bar.Add(1)
 					// 진행률 메시지도 더 부드럽게 업데이트
 					if j == 0 {
-						bar.Describe(fmt.Sprintf("파일 완료: %d/%d (%d개 이벤트)", processedFiles, len(targetFiles), len(events)))
+						bar.Describe(fmt.Sprintf(T("파일 완료: %d/%d (%d개 이벤트)"), processedFiles, len(targetFiles), len(events)))
 					} else {
-						bar.Describe(fmt.Sprintf("처리 중... (%d개 이벤트)", len(events)))
+						bar.Describe(fmt.Sprintf(T("처리 중... (%d개 이벤트)"), len(events)))
 					}
 					// 약간의 지연으로 더 부드러운 느낌
 					time.Sleep(5 * time.Millisecond)
 				}
-			case <-errorChan:
+			case result := <-errorChan:
 				processedFiles++
-				// 에러는 조용히 무시하고 진행률만 업데이트
+				if deadlineExceeded(ctx, work) && errors.Is(result.err, context.DeadlineExceeded) {
+					if err := collect(result.file, ba.deadlineCut(result.file, result.events, !result.skipped)); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := collect(result.file, nil); err != nil {
+					return err
+				}
+				ba.run.fileDone(result.file.Name, 0, result.err)
+				// 실패한 파일은 건너뛰고 진행하되 결과가 불완전함을 알림
+				ba.incomplete = true
+				ba.warn("파일 %s 처리 실패, 결과에서 빠짐: %v", result.file.Name, result.err)
 				for j := 0; j < progressPerFile; j++ {
 					bar.Add(1)
-					bar.Describe(fmt.Sprintf("파일 실패: %d/%d", processedFiles, len(targetFiles)))
+					bar.Describe(fmt.Sprintf(T("파일 실패: %d/%d"), processedFiles, len(targetFiles)))
 					time.Sleep(5 * time.Millisecond)
 				}
 			}
@@ -232,32 +471,87 @@ func (ba *BinlogAnalyzer) Analyze() error {
 	} else {
 		// verbose 모드에서는 로딩바 없이 직접 처리
 		for i, file := range targetFiles {
-			fmt.Printf("파일 처리 중: %s (%d/%d)\n", file.Name, i+1, len(targetFiles))
+			if deadlineExceeded(ctx, work) {
+				ba.deadlineCut(file, nil, false)
+				progress.FileDone(file.Size, 0)
+				if err := collect(file, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			fmt.Fprintf(ba.messageOutput(), T("파일 처리 중: %s (%d/%d)\n"), file.Name, i+1, len(targetFiles))
+
+			events, err := sqlExtractor.ExtractFromSingleFile(work, file)
+			if ctx.Err() != nil {
+				return fmt.Errorf(T("분석 중단: %w"), ctx.Err())
+			}
+			if deadlineExceeded(ctx, work) && errors.Is(err, context.DeadlineExceeded) {
+				progress.FileDone(file.Size, 0)
+				if err := collect(file, ba.deadlineCut(file, events, true)); err != nil {
+					return err
+				}
+				continue
+			}
 
-			events, err := sqlExtractor.ExtractFromSingleFile(file)
+			if ba.warnPartialRead(err) {
+				err = nil
+			}
 
 			if err != nil {
-				fmt.Printf("파일 %s 처리 실패: %v (계속 진행)\n", file.Name, err)
+				if err := collect(file, nil); err != nil {
+					return err
+				}
+				fmt.Fprintf(ba.messageOutput(), T("파일 %s 처리 실패: %v (계속 진행)\n"), file.Name, err)
+				ba.incomplete = true
+				ba.warn("파일 %s 처리 실패, 결과에서 빠짐: %v", file.Name, err)
+				ba.run.fileDone(file.Name, 0, err)
+				progress.FileDone(file.Size, 0)
 			} else {
-				allEvents = append(allEvents, events...)
+				events = ba.filter.Filter(events)
+				if err := collect(file, events); err != nil {
+					return err
+				}
+				ba.run.fileDone(file.Name, len(events), nil)
+				progress.FileDone(file.Size, len(events))
 				eventCount := 0
 				if events != nil {
 					eventCount = len(events)
 				}
-				fmt.Printf("파일 완료: %s (%d개 이벤트)\n", file.Name, eventCount)
+				fmt.Fprintf(ba.messageOutput(), T("파일 완료: %s (%d개 이벤트)\n"), file.Name, eventCount)
 			}
 		}
 	}
 
+	if len(ba.cutFiles) > 0 {
+		slices.Sort(ba.cutFiles) // 병렬 처리에서는 끝난 순서로 모이므로 파일 순서로 정렬
+		ba.warn("--deadline %s 초과: 파일 %d개를 끝까지 읽지 못해 그 뒤의 이벤트가 결과에서 빠짐 (%s부터)", ba.Config.Deadline, len(ba.cutFiles), ba.cutFiles[0])
+	}
+
+	progress.Stage(progressStageFinalize)
+
+	if stream != nil {
+		if ba.Config.Verbose == 0 {
+			bar.Finish()
+		} else {
+			fmt.Fprintln(ba.messageOutput(), T("분석 완료"))
+		}
+		return stream.finish()
+	}
+
 	if len(allEvents) == 0 {
-		if !ba.Config.Verbose {
+		if ba.Config.Verbose == 0 {
 			bar.Finish()
 		}
-		fmt.Println("\n\n지정된 조건에 맞는 SQL 이벤트를 찾을 수 없습니다.")
-		return nil
+		fmt.Fprintln(ba.messageOutput(), T("\n\n지정된 조건에 맞는 SQL 이벤트를 찾을 수 없습니다."))
+		ba.lags.write(ba.messageOutput())
+		ba.retention.write(ba.messageOutput())
+		if err := ba.writeCapacityReport(ba.messageOutput(), nil); err != nil {
+			return err
+		}
+		return ba.writeTimeline(nil)
 	}
 
-	if !ba.Config.Verbose {
+	if ba.Config.Verbose == 0 {
 		// 남은 진행률 채우기 (200%까지) - 지연 없음
 		currentProgress := 30 + (totalProgressSteps / len(targetFiles) * len(targetFiles)) // MySQL 연결(10) + 파일 검색(20) + 파일 처리(150)
 		remaining := 200 - currentProgress
@@ -265,57 +559,155 @@ func (ba *BinlogAnalyzer) Analyze() error {
 			for i := 0; i < remaining; i++ {
 				bar.Add(1)
 				if i < remaining/2 {
-					bar.Describe(fmt.Sprintf("결과 정리 중... (총 %d개 이벤트)", len(allEvents)))
+					bar.Describe(fmt.Sprintf(T("결과 정리 중... (총 %d개 이벤트)"), len(allEvents)))
 				} else {
-					bar.Describe("분석 완료")
+					bar.Describe(T("분석 완료"))
 				}
 			}
-			fmt.Printf("\n")
+			fmt.Fprintf(ba.messageOutput(), "\n")
 		}
 	} else {
-		fmt.Printf("결과 정리 중... (총 %d개 이벤트)\n", len(allEvents))
+		fmt.Fprintf(ba.messageOutput(), T("결과 정리 중... (총 %d개 이벤트)\n"), len(allEvents))
 	}
 
 	uniqueEvents, duplicateCount := ba.removeDuplicateEvents(allEvents)
 
-	if ba.Config.Verbose {
-		fmt.Printf("중복 제거 전: %d개 이벤트, 중복 제거 후: %d개 이벤트\n", len(allEvents), len(uniqueEvents))
+	if ba.Config.Verbose >= config.VerboseFiles {
+		fmt.Fprintf(ba.messageOutput(), T("중복 제거 전: %d개 이벤트, 중복 제거 후: %d개 이벤트\n"), len(allEvents), len(uniqueEvents))
 	}
 
+	// 시간순 정렬 후 구간 내 DDL을 반영하여 row 이벤트 컬럼 구성 보정
+	ba.sortEvents(uniqueEvents)
+	history := NewSchemaHistory(ba.schema)
+	if updated := history.Apply(uniqueEvents, NewSQLExtractor(ba.Config, ba.schema)); updated > 0 && ba.Config.Verbose >= config.VerboseFiles {
+		fmt.Fprintf(ba.messageOutput(), T("DDL 히스토리 반영: %d개 row 이벤트의 컬럼 구성 보정\n"), updated)
+	}
+	if ba.Config.Hook != "" {
+		if uniqueEvents, err = ba.applyHook(ctx, uniqueEvents); err != nil {
+			return err
+		}
+	}
+	ba.collectUnresolvedTables(uniqueEvents)
+	ba.run.extracted = len(allEvents)
+	ba.run.duplicates = duplicateCount
+	ba.run.countEvents(uniqueEvents)
+
 	// 진행률바 완료
-	if !ba.Config.Verbose {
+	if ba.Config.Verbose == 0 {
 		bar.Finish()
 	} else {
-		fmt.Println("분석 완료")
+		fmt.Fprintln(ba.messageOutput(), T("분석 완료"))
 	}
 
 	// 결과 출력 (진행률바 완료 후, 개행 추가)
-	fmt.Println() // 개행 추가
-	err = ba.outputResults(uniqueEvents)
+	messages := ba.messageOutput()
+	fmt.Fprintln(messages) // 개행 추가
+	if ba.onResults != nil {
+		if err := ba.onResults(uniqueEvents); err != nil {
+			return fmt.Errorf(T("결과 출력 실패: %v"), err)
+		}
+	}
+	switch {
+	case ba.discardOutput:
+		err = nil
+	case ba.Config.Sink != "":
+		err = ba.writeToSink(ctx, uniqueEvents)
+	default:
+		err = ba.outputResults(uniqueEvents)
+	}
 	if err != nil {
-		return fmt.Errorf("결과 출력 실패: %v", err)
+		return fmt.Errorf(T("결과 출력 실패: %v"), err)
+	}
+	if err := ba.writeTimeline(uniqueEvents); err != nil {
+		return err
 	}
 
-	fmt.Printf("\n>> 총 %d개의 고유한 SQL 이벤트를 발견했습니다.\n", len(uniqueEvents))
+	fmt.Fprintf(messages, T("\n>> 총 %d개의 고유한 SQL 이벤트를 발견했습니다.\n"), len(uniqueEvents))
 	if duplicateCount > 0 {
-		fmt.Printf(">> 중복 제거: %d개 → %d개 (총 %d개 중복 이벤트 제거)\n", len(allEvents), len(uniqueEvents), duplicateCount)
+		fmt.Fprintf(messages, T(">> 중복 제거: %d개 → %d개 (총 %d개 중복 이벤트 제거)\n"), len(allEvents), len(uniqueEvents), duplicateCount)
 	} else {
-		fmt.Printf(">> 중복 제거: %d개 → %d개 (중복 없음)\n", len(allEvents), len(uniqueEvents))
+		fmt.Fprintf(messages, T(">> 중복 제거: %d개 → %d개 (중복 없음)\n"), len(allEvents), len(uniqueEvents))
+	}
+	if gtids := ba.gtids.String(); gtids != "" {
+		fmt.Fprintf(messages, T(">> 구간의 GTID 집합: %s\n"), gtids)
+	}
+	ba.lags.write(messages)
+	ba.retention.write(messages)
+	if err := ba.writeCapacityReport(messages, uniqueEvents); err != nil {
+		return err
+	}
+	if ba.Config.AccountSummary {
+		writeAccountSummary(messages, uniqueEvents)
+	}
+	writeRowWarnings(messages, uniqueEvents, ba.Config.WarnRows)
+	renderer := NewSQLExtractor(ba.Config, ba.schema)
+	writeHotRows(messages, uniqueEvents, renderer, ba.Config.HotRowsTop)
+	writeUpdateConflicts(messages, uniqueEvents, renderer, ba.Config.ConflictWindow)
+	ba.writeUnresolvedSummary(messages)
+	if ba.Config.SchemaDrift {
+		writeSchemaDrift(messages, ba.checkSchemaDrift(uniqueEvents))
 	}
 
 	return nil
 }
 
-// MySQL 서버에 연결
-func (ba *BinlogAnalyzer) connect() error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/", ba.Config.User, ba.Config.Password, ba.Config.Host, ba.Config.Port)
-	var err error
-	ba.conn, err = sql.Open("mysql", dsn)
-	if err != nil {
-		return err
+// 파일 하나의 추출 결과
+type extractResult struct {
+	file    config.BinlogFile
+	events  []config.SQLEvent // 오류가 나도 그때까지 읽은 이벤트
+	err     error
+	skipped bool // --deadline이 지나 읽지 않음
+}
+
+// 진행 상황/요약 메시지 출력 대상 (stdout에는 분석 결과만 내보내어 파이프로 바로 처리할 수 있도록 stderr 사용)
+func (ba *BinlogAnalyzer) messageOutput() io.Writer {
+	if ba.messages != nil {
+		return ba.messages
+	}
+	return os.Stderr
+}
+
+// binlog_rows_query_log_events 활성화 여부 확인 (--set-rows-query 시 활성화 시도)
+func (ba *BinlogAnalyzer) checkRowsQueryLogging() {
+	var value string
+	if err := ba.conn.QueryRow("SELECT @@GLOBAL.binlog_rows_query_log_events").Scan(&value); err != nil {
+		if ba.Config.Verbose >= config.VerboseFiles {
+			fmt.Fprintf(ba.messageOutput(), T("binlog_rows_query_log_events 확인 실패: %v\n"), err)
+		}
+		return
+	}
+	ba.rowsQueryLogging = normalizeSwitchValue(value)
+
+	if ba.rowsQueryLogging == "ON" {
+		if ba.Config.Verbose >= config.VerboseFiles {
+			fmt.Fprintln(ba.messageOutput(), T("binlog_rows_query_log_events: ON (row 이벤트의 원본 SQL 포함)"))
+		}
+		return
+	}
+
+	if !ba.Config.SetRowsQuery {
+		if ba.Config.Verbose >= config.VerboseFiles {
+			fmt.Fprintln(ba.messageOutput(), T("binlog_rows_query_log_events: OFF (row 이벤트는 재구성된 pseudo-SQL로만 출력됩니다. --set-rows-query로 활성화 가능)"))
+		}
+		return
 	}
 
-	return ba.conn.Ping()
+	// 이미 기록된 이벤트에는 영향이 없고, 이후 새로 연결되는 세션부터 적용됨
+	if _, err := ba.conn.Exec("SET GLOBAL binlog_rows_query_log_events = ON"); err != nil {
+		logrus.Warnf(T("binlog_rows_query_log_events 활성화 실패 (SUPER 또는 SYSTEM_VARIABLES_ADMIN 권한 필요, Aurora는 파라미터 그룹에서 설정): %v"), err)
+		return
+	}
+	logrus.Info(T("binlog_rows_query_log_events를 ON으로 변경했습니다 (이후 기록되는 이벤트부터 원본 SQL이 포함됩니다)"))
+}
+
+// 시스템 변수의 ON/OFF 값 정규화 (1/0 형태 포함)
+func normalizeSwitchValue(value string) string {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "1", "ON", "TRUE":
+		return "ON"
+	default:
+		return "OFF"
+	}
 }
 
 // Binary log 파일 목록 가져오기
@@ -359,7 +751,7 @@ func (ba *BinlogAnalyzer) getBinlogFiles() ([]config.BinlogFile, error) {
 				return nil, err
 			}
 		} else {
-			return nil, fmt.Errorf("예상치 못한 SHOW BINARY LOGS 결과 컬럼 수: %d", len(columns))
+			return nil, fmt.Errorf(T("예상치 못한 SHOW BINARY LOGS 결과 컬럼 수: %d"), len(columns))
 		}
 
 		files = append(files, config.BinlogFile{
@@ -371,64 +763,38 @@ func (ba *BinlogAnalyzer) getBinlogFiles() ([]config.BinlogFile, error) {
 	return files, nil
 }
 
-// 결과 출력
-func (ba *BinlogAnalyzer) outputResults(events []config.SQLEvent) error {
-	var output *os.File
-	var err error
+// 식별자를 백틱으로 감싸기
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
 
-	if ba.Config.OutputFile != "" {
-		output, err = os.Create(ba.Config.OutputFile)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %v", err)
+// 이벤트 시간순 정렬 (같은 시각이면 binlog 순서, 실행할 때마다 같은 순서가 되도록)
+func (ba *BinlogAnalyzer) sortEvents(events []config.SQLEvent) {
+	slices.SortFunc(events, func(a, b config.SQLEvent) int {
+		if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+			return c
 		}
-		defer output.Close()
-	} else {
-		output = os.Stdout
-	}
-
-	sort.Slice(events, func(i, j int) bool {
-		return events[i].Timestamp.Before(events[j].Timestamp)
+		return ba.compareBinlogOrder(&a, &b)
 	})
+}
 
-	green := "\033[32m"
-	reset := "\033[0m"
-
-	fmt.Printf("%s", green)
-	fmt.Fprintf(output, "# Binary Log Analysis Results\n")
-	fmt.Fprintf(output, "# Time Range: %s ~ %s\n",
-		ba.Config.StartTime.Format("2006-01-02 15:04:05"),
-		ba.Config.EndTime.Format("2006-01-02 15:04:05"))
-	fmt.Fprintf(output, "# Total Events: %d\n\n", len(events))
-
-	for _, event := range events {
-		fmt.Fprintf(output, "# at %d\n", event.Position)
-		fmt.Fprintf(output, "#%s server id %d  end_log_pos %d\n",
-			event.Timestamp.Format("060102 15:04:05"), event.ServerId, event.Position)
-		fmt.Fprintf(output, "# Binary Log File: %s\n", event.Filename)
-
-		if event.Database != "" {
-			fmt.Fprintf(output, "use %s;\n", event.Database)
-		}
-
-		fmt.Fprintf(output, "%s;\n\n", event.SQL)
-	}
-	fmt.Printf("%s", reset)
-
-	logrus.Infof("Analysis complete: %d SQL events", len(events))
-	if ba.Config.OutputFile != "" {
-		logrus.Infof("Results saved to %s", ba.Config.OutputFile)
-	}
-
-	return nil
+// binlog 순서 비교 (파일 번호, 파일 이름, 위치)
+func (ba *BinlogAnalyzer) compareBinlogOrder(a, b *config.SQLEvent) int {
+	return cmp.Or(
+		cmp.Compare(ba.extractFileNumber(a.Filename), ba.extractFileNumber(b.Filename)),
+		strings.Compare(a.Filename, b.Filename),
+		cmp.Compare(a.Position, b.Position),
+	)
 }
 
-// 중복 이벤트 제거 (end_log_pos + timestamp 기준, 원본 파일 우선)
+// 중복 이벤트 제거 (end_log_pos + timestamp + server_id + GTID 기준, GTID가 없으면 GTID 대신 파일명, 원본 파일 우선)
+// 여러 writer가 번갈아 기록한 클러스터에서는 위치와 시각이 같아도 server_id나 GTID가 다른 별개의 이벤트일 수 있음
 func (ba *BinlogAnalyzer) removeDuplicateEvents(events []config.SQLEvent) ([]config.SQLEvent, int) {
 	// 이벤트를 파일명별로 그룹화하여 원본 파일 우선순위 결정
-	eventGroups := make(map[string][]config.SQLEvent) // key: position_timestamp
+	eventGroups := make(map[string][]config.SQLEvent) // key: position_timestamp_serverid_gtid
 
 	for _, event := range events {
-		key := fmt.Sprintf("%d_%s", event.Position, event.Timestamp)
+		key := duplicateKey(&event)
 		eventGroups[key] = append(eventGroups[key], event)
 	}
 
@@ -445,9 +811,8 @@ func (ba *BinlogAnalyzer) removeDuplicateEvents(events []config.SQLEvent) ([]con
 			uniqueEvents = append(uniqueEvents, originalEvent)
 			duplicateCount += len(group) - 1
 
-			if ba.Config.Verbose {
-				logrus.Debugf("중복 이벤트 제거: pos=%d, time=%s, 원본=%s, 제거=%d개\n",
-					originalEvent.Position, originalEvent.Timestamp, originalEvent.Filename, len(group)-1)
+			if ba.Config.Verbose >= config.VerboseFiles {
+				ba.reportMerge(originalEvent, group)
 			}
 		}
 	}
@@ -455,6 +820,47 @@ func (ba *BinlogAnalyzer) removeDuplicateEvents(events []config.SQLEvent) ([]con
 	return uniqueEvents, duplicateCount
 }
 
+// 같은 이벤트인지 판단하는 키 (position_timestamp_serverid_gtid)
+// GTID가 없는 익명 트랜잭션은 다른 파일의 같은 위치, 시각 이벤트와 구분할 수 없어 파일명을 대신 사용
+func duplicateKey(event *config.SQLEvent) string {
+	transaction := eventGTID(event)
+	if transaction == "" {
+		transaction = "file=" + event.Filename
+	}
+	return fmt.Sprintf("%d_%s_%d_%s", event.Position, event.Timestamp, event.ServerId, transaction)
+}
+
+// 중복으로 합친 이벤트 출력 (남긴 이벤트와 제거한 이벤트의 파일)
+func (ba *BinlogAnalyzer) reportMerge(original config.SQLEvent, group []config.SQLEvent) {
+	removed := make([]string, 0, len(group)-1)
+	skipped := false
+	for _, event := range group {
+		if !skipped && event.Filename == original.Filename {
+			// 남긴 이벤트
+			skipped = true
+			continue
+		}
+		removed = append(removed, event.Filename)
+	}
+
+	transaction := eventGTID(&original)
+	if transaction == "" {
+		transaction = "-"
+	}
+	fmt.Fprintf(ba.messageOutput(), T("중복 이벤트 병합: pos=%d, time=%s, server id=%d, GTID=%s, 원본=%s, 제거=%s\n"),
+		original.Position, original.Timestamp.Format("2006-01-02 15:04:05.999999"), original.ServerId,
+		transaction, original.Filename, strings.Join(removed, ", "))
+}
+
+// 이벤트가 속한 트랜잭션의 GTID (익명 트랜잭션이면 빈 문자열)
+func eventGTID(event *config.SQLEvent) string {
+	if strings.HasPrefix(event.Transaction, event.Filename+":") {
+		// filename:position 형태의 트랜잭션 시작 위치
+		return ""
+	}
+	return event.Transaction
+}
+
 // 중복 이벤트들 중에서 원본 이벤트 선택
 func (ba *BinlogAnalyzer) selectOriginalEvent(events []config.SQLEvent) config.SQLEvent {
 	if len(events) == 0 {
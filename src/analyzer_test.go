@@ -0,0 +1,74 @@
+package src
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+func TestRemoveDuplicateEvents(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	event := func(filename, transaction string) config.SQLEvent {
+		return config.SQLEvent{Timestamp: at, ServerId: 1, Position: 1200, Filename: filename, Transaction: transaction, SQL: "INSERT"}
+	}
+	tests := []struct {
+		name       string
+		events     []config.SQLEvent
+		want       []string // 남은 이벤트의 파일명
+		duplicates int
+	}{
+		{
+			"anonymous transactions in two files stay separate",
+			[]config.SQLEvent{
+				event("mysql-bin.000001", "mysql-bin.000001:1000"),
+				event("mysql-bin.000002", "mysql-bin.000002:1000"),
+			},
+			[]string{"mysql-bin.000001", "mysql-bin.000002"},
+			0,
+		},
+		{
+			"anonymous transaction read twice from one file",
+			[]config.SQLEvent{
+				event("mysql-bin.000001", "mysql-bin.000001:1000"),
+				event("mysql-bin.000001", "mysql-bin.000001:1000"),
+			},
+			[]string{"mysql-bin.000001"},
+			1,
+		},
+		{
+			"same GTID in two files is merged",
+			[]config.SQLEvent{
+				event("mysql-bin.000001", "3e11fa47-71ca-11e1-9e33-c80aa9429562:23"),
+				event("mysql-bin.000002", "3e11fa47-71ca-11e1-9e33-c80aa9429562:23"),
+			},
+			[]string{"mysql-bin.000002"},
+			1,
+		},
+		{
+			"different GTIDs stay separate",
+			[]config.SQLEvent{
+				event("mysql-bin.000001", "3e11fa47-71ca-11e1-9e33-c80aa9429562:23"),
+				event("mysql-bin.000002", "3e11fa47-71ca-11e1-9e33-c80aa9429562:24"),
+			},
+			[]string{"mysql-bin.000001", "mysql-bin.000002"},
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ba := &BinlogAnalyzer{}
+			got, duplicates := ba.removeDuplicateEvents(tt.events)
+			var files []string
+			for _, e := range got {
+				files = append(files, e.Filename)
+			}
+			sort.Strings(files)
+			if !reflect.DeepEqual(files, tt.want) || duplicates != tt.duplicates {
+				t.Errorf("removeDuplicateEvents() = %v (%d duplicates), want %v (%d duplicates)", files, duplicates, tt.want, tt.duplicates)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// Auditor 지정한 테이블의 변경을 트랜잭션, 실행 계정, PK와 함께 보고 (audit 서브커맨드)
+type Auditor struct {
+	Config config.Config // 분석할 서버와 시간 범위, 필터
+	Tables []string      // 대상 테이블 (db.table)
+	Output io.Writer     // 보고서 출력 대상 (없으면 stdout)
+}
+
+// 보고서의 트랜잭션 하나
+type auditTransaction struct {
+	id      string
+	account string // 트랜잭션에서 처음 확인된 실행 계정
+	events  []*config.SQLEvent
+}
+
+func validateAudit(a *Auditor) error {
+	if len(a.Tables) == 0 {
+		return fmt.Errorf("--table을 지정해야 합니다 (db.table)")
+	}
+	for _, table := range a.Tables {
+		schema, name, ok := strings.Cut(table, ".")
+		if !ok || schema == "" || name == "" {
+			return fmt.Errorf("--table은 db.table 형식이어야 합니다: %s", table)
+		}
+	}
+	return nil
+}
+
+// Run 분석 후 대상 테이블의 변경 이력 출력
+func (a *Auditor) Run(ctx context.Context) error {
+	if err := validateAudit(a); err != nil {
+		return err
+	}
+	output := a.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	cfg := a.Config
+	cfg.Sink = ""
+	cfg.SQLiteFile = ""
+	cfg.Format = ""
+	cfg.CSVColumns = nil
+	cfg.OutputTemplate = ""
+	cfg.OutputFile = ""
+	cfg.Compress = ""
+	cfg.OutputRotateSize = 0
+	cfg.OutputRotateEvents = 0
+	cfg.SplitBy = ""
+	cfg.Timeline = ""
+	cfg.CapacityReport = ""
+
+	tables := make(map[string]bool, len(a.Tables))
+	for _, table := range a.Tables {
+		tables[strings.ToLower(table)] = true
+	}
+
+	var events []config.SQLEvent
+	analyzer := &BinlogAnalyzer{
+		Config:        cfg,
+		messages:      os.Stderr,
+		discardOutput: true,
+		onResults: func(results []config.SQLEvent) error {
+			for _, event := range results {
+				if auditTouches(&event, tables) {
+					events = append(events, event)
+				}
+			}
+			return nil
+		},
+	}
+	if err := analyzer.Analyze(ctx); err != nil {
+		return err
+	}
+
+	// 트랜잭션이 이어지도록 binlog 순서로 정렬
+	slices.SortFunc(events, func(a, b config.SQLEvent) int {
+		return analyzer.compareBinlogOrder(&a, &b)
+	})
+	// --incremental이면 구간이 분석 중에 정해짐
+	writeAuditReport(output, NewSQLExtractor(cfg, analyzer.schema), a.Tables, analyzer.Config, events)
+	return nil
+}
+
+// 이벤트가 대상 테이블을 변경하는지 (쿼리 이벤트는 참조하는 테이블 중 하나라도 대상이면 포함)
+func auditTouches(event *config.SQLEvent, tables map[string]bool) bool {
+	if event.EventType != "QUERY" {
+		return tables[strings.ToLower(event.Database+"."+event.Table)]
+	}
+	for _, table := range queryTables(event.Database, event.SQL) {
+		if tables[strings.ToLower(table)] {
+			return true
+		}
+	}
+	return false
+}
+
+// 연속된 같은 트랜잭션의 이벤트를 묶음
+func groupAuditTransactions(events []config.SQLEvent) []*auditTransaction {
+	var transactions []*auditTransaction
+	var current *auditTransaction
+	for i := range events {
+		event := &events[i]
+		id := event.Transaction
+		if id == "" {
+			id = fmt.Sprintf("%s:%d", event.Filename, event.StartPosition)
+		}
+		if current == nil || current.id != id {
+			current = &auditTransaction{id: id}
+			transactions = append(transactions, current)
+		}
+		current.events = append(current.events, event)
+		if current.account == "" {
+			current.account = eventAccount(event)
+		}
+	}
+	return transactions
+}
+
+func writeAuditReport(w io.Writer, renderer *SQLExtractor, tables []string, cfg config.Config, events []config.SQLEvent) {
+	transactions := groupAuditTransactions(events)
+	rows := 0
+	for i := range events {
+		rows += events[i].RowCount
+	}
+
+	fmt.Fprintf(w, T("# %s 변경 이력: %s ~ %s UTC\n"), strings.Join(tables, ", "),
+		cfg.StartTime.Format("2006-01-02 15:04:05.999999"), cfg.EndTime.Format("2006-01-02 15:04:05.999999"))
+	fmt.Fprintf(w, T("# 트랜잭션 %d개, 문장 %d개, 변경 행 %d개\n"), len(transactions), len(events), rows)
+	if len(transactions) == 0 {
+		return
+	}
+
+	for _, tx := range transactions {
+		first := tx.events[0]
+		account := tx.account
+		if account == "" {
+			account = T("알 수 없음")
+		}
+		fmt.Fprintf(w, T("\n[%s] 트랜잭션 %s  계정 %s  %s:%d\n"),
+			first.Timestamp.UTC().Format("2006-01-02 15:04:05.999999"), tx.id, account, first.Filename, first.StartPosition)
+
+		for _, event := range tx.events {
+			if event.EventType == "QUERY" {
+				fmt.Fprintf(w, "    %s\n", singleLine(event.SQL))
+				continue
+			}
+			fmt.Fprintf(w, T("    %s %s.%s %d행"), event.EventType, event.Database, event.Table, event.RowCount)
+			if pk := renderer.formatPrimaryKeys(event); pk != "" {
+				fmt.Fprintf(w, "  PK %s", pk)
+			}
+			fmt.Fprintln(w)
+			if event.OriginalSQL != "" {
+				fmt.Fprintf(w, T("      원본 SQL: %s\n"), singleLine(event.OriginalSQL))
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	writeAccountSummary(w, events)
+}
+
+// 여러 줄 SQL을 한 줄로 (보고서 정렬 유지)
+func singleLine(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
@@ -0,0 +1,203 @@
+package src
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// API 토큰 역할
+const (
+	RoleViewer   = "viewer"   // 작업/결과 조회, 실시간 스트림
+	RoleOperator = "operator" // viewer 권한 + 분석 작업 제출/취소/삭제
+)
+
+var roleLevels = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+}
+
+// APIToken 서버 모드 API 토큰
+type APIToken struct {
+	Name  string // 감사 로그에 기록되는 사용자 이름
+	Token string
+	Role  string // viewer, operator
+}
+
+// ValidateTokens 토큰 설정 검증 (빈 토큰, 중복 토큰, 알 수 없는 역할)
+func ValidateTokens(tokens []APIToken) error {
+	seen := make(map[string]string, len(tokens))
+	for _, t := range tokens {
+		if t.Token == "" {
+			return fmt.Errorf("API 토큰 %s: token 값이 비어 있습니다", t.Name)
+		}
+		if _, ok := roleLevels[t.Role]; !ok {
+			return fmt.Errorf("API 토큰 %s: 지원하지 않는 역할 %q (viewer, operator 중 선택)", t.Name, t.Role)
+		}
+		if other, ok := seen[t.Token]; ok {
+			return fmt.Errorf("API 토큰 %s, %s: 같은 token 값을 사용할 수 없습니다", other, t.Name)
+		}
+		seen[t.Token] = t.Name
+	}
+	return nil
+}
+
+// 요청의 토큰에 해당하는 API 토큰 (Authorization: Bearer 또는 ?access_token=)
+// 브라우저의 WebSocket과 다운로드 링크는 헤더를 지정할 수 없어 쿼리 파라미터도 허용
+func (s *Server) requestToken(r *http.Request) (APIToken, bool) {
+	value := r.URL.Query().Get("access_token")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, token, _ := strings.Cut(auth, " ")
+		if strings.EqualFold(scheme, "Bearer") {
+			value = strings.TrimSpace(token)
+		}
+	}
+	if value == "" {
+		return APIToken{}, false
+	}
+
+	// 길이와 내용이 드러나지 않도록 해시를 고정 시간 비교
+	sum := sha256.Sum256([]byte(value))
+	for _, t := range s.Tokens {
+		expected := sha256.Sum256([]byte(t.Token))
+		if subtle.ConstantTimeCompare(sum[:], expected[:]) == 1 {
+			return t, true
+		}
+	}
+	return APIToken{}, false
+}
+
+// role 이상의 권한이 있는 토큰만 handler를 호출하고, 모든 요청을 감사 로그에 기록
+// 토큰이 설정되지 않았으면 InsecureNoAuth일 때만 인증 없이 허용 (감사 로그에는 anonymous로 기록)
+func (s *Server) authorize(role string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record := &auditRecord{
+			Time:   time.Now().UTC(),
+			User:   "anonymous",
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Target: r.URL.Query().Get("target"),
+			Remote: r.RemoteAddr,
+		}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			record.Status = recorder.status
+			record.Duration = time.Since(record.Time).Round(time.Millisecond).String()
+			s.audit.write(record)
+		}()
+
+		if len(s.Tokens) > 0 || !s.InsecureNoAuth {
+			token, ok := s.requestToken(r)
+			if !ok {
+				recorder.Header().Set("WWW-Authenticate", `Bearer realm="mysqlbinlogo"`)
+				writeJSONError(recorder, http.StatusUnauthorized, fmt.Errorf("유효한 API 토큰이 필요합니다"))
+				return
+			}
+			record.User, record.Role = token.Name, token.Role
+			if roleLevels[token.Role] < roleLevels[role] {
+				writeJSONError(recorder, http.StatusForbidden, fmt.Errorf("%s 역할이 필요합니다 (현재: %s)", role, token.Role))
+				return
+			}
+		}
+
+		handler(recorder, r.WithContext(context.WithValue(r.Context(), auditKey{}, record)))
+	}
+}
+
+// 감사 로그 한 줄
+type auditRecord struct {
+	Time     time.Time        `json:"time"`
+	User     string           `json:"user"`
+	Role     string           `json:"role,omitempty"`
+	Method   string           `json:"method"`
+	Path     string           `json:"path"`
+	Target   string           `json:"target,omitempty"`
+	Job      string           `json:"job,omitempty"`     // 제출된 분석 작업 ID
+	Request  *AnalysisRequest `json:"request,omitempty"` // 제출된 분석 요청
+	Status   int              `json:"status"`
+	Duration string           `json:"duration"`
+	Remote   string           `json:"remote"`
+}
+
+type auditKey struct{}
+
+// 요청의 감사 로그 기록 (핸들러에서 작업 정보 추가용, 없으면 nil)
+func requestAudit(r *http.Request) *auditRecord {
+	record, _ := r.Context().Value(auditKey{}).(*auditRecord)
+	return record
+}
+
+// 감사 로그 출력 (파일이 없으면 서버 로그에 기록)
+type auditLogger struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func newAuditLogger(w io.Writer) *auditLogger {
+	if w == nil {
+		return &auditLogger{}
+	}
+	return &auditLogger{encoder: json.NewEncoder(w)}
+}
+
+func (l *auditLogger) write(record *auditRecord) {
+	if l.encoder == nil {
+		logrus.WithFields(logrus.Fields{
+			"user":   record.User,
+			"method": record.Method,
+			"path":   record.Path,
+			"target": record.Target,
+			"job":    record.Job,
+			"status": record.Status,
+			"remote": record.Remote,
+		}).Info("audit")
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.encoder.Encode(record); err != nil {
+		logrus.Warnf("감사 로그 기록 실패: %v", err)
+	}
+}
+
+// 응답 상태 코드 기록 (WebSocket 업그레이드를 위해 Hijack 지원)
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("연결 전환을 지원하지 않는 응답입니다")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,52 @@
+package src
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	tokens := []APIToken{
+		{Name: "alice", Token: "operator-token", Role: RoleOperator},
+		{Name: "dashboard", Token: "viewer-token", Role: RoleViewer},
+	}
+	tests := []struct {
+		name     string
+		tokens   []APIToken
+		insecure bool
+		role     string
+		token    string
+		want     int
+	}{
+		{"no tokens configured, no token", nil, false, RoleViewer, "", http.StatusUnauthorized},
+		{"no tokens configured, operator route", nil, false, RoleOperator, "", http.StatusUnauthorized},
+		{"no tokens configured, any token", nil, false, RoleViewer, "guess", http.StatusUnauthorized},
+		{"no tokens configured, insecure", nil, true, RoleOperator, "", http.StatusOK},
+		{"no token", tokens, false, RoleViewer, "", http.StatusUnauthorized},
+		{"unknown token", tokens, false, RoleViewer, "guess", http.StatusUnauthorized},
+		{"unknown token, insecure", tokens, true, RoleViewer, "guess", http.StatusUnauthorized},
+		{"viewer on viewer route", tokens, false, RoleViewer, "viewer-token", http.StatusOK},
+		{"viewer on operator route", tokens, false, RoleOperator, "viewer-token", http.StatusForbidden},
+		{"operator on operator route", tokens, false, RoleOperator, "operator-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{Tokens: tt.tokens, InsecureNoAuth: tt.insecure, audit: newAuditLogger(io.Discard)}
+			handler := s.authorize(tt.role, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodDelete, "/analyses/1", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+
+	"mysqlbinlogo/config"
+)
+
+// BigQuery 테이블의 행 (테이블이 없으면 이 구조로 스키마 생성)
+type bigQueryRow struct {
+	Timestamp   time.Time              `bigquery:"timestamp"`
+	EventType   string                 `bigquery:"event_type"`
+	Database    string                 `bigquery:"database"`
+	Table       bigquery.NullString    `bigquery:"table"`
+	SQL         string                 `bigquery:"sql"`
+	OriginalSQL bigquery.NullString    `bigquery:"original_sql"`
+	ServerID    int64                  `bigquery:"server_id"`
+	Filename    string                 `bigquery:"filename"`
+	Position    int64                  `bigquery:"position"`
+	EventSize   int64                  `bigquery:"event_size"`
+	RowCount    int64                  `bigquery:"row_count"`
+	CapturedAt  bigquery.NullTimestamp `bigquery:"captured_at"` // 실시간 추적 모드에서만 기록
+}
+
+// BigQuery 스트리밍 삽입 싱크 (--sink bigquery)
+type bigQuerySink struct {
+	client   *bigquery.Client
+	schema   bigquery.Schema
+	inserter *bigquery.Inserter
+}
+
+func newBigQuerySink(ctx context.Context, cfg config.Config) (*bigQuerySink, error) {
+	project := cfg.BQProject
+	if project == "" {
+		project = bigquery.DetectProjectID
+	}
+	client, err := bigquery.NewClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := bigquery.InferSchema(bigQueryRow{})
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	table, err := ensureBigQueryTable(ctx, client, cfg, schema)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &bigQuerySink{client: client, schema: schema, inserter: table.Inserter()}, nil
+}
+
+// 데이터셋과 테이블이 없으면 생성 (테이블은 이벤트 시각 기준 일 단위 파티션)
+func ensureBigQueryTable(ctx context.Context, client *bigquery.Client, cfg config.Config, schema bigquery.Schema) (*bigquery.Table, error) {
+	dataset := client.Dataset(cfg.BQDataset)
+	table := dataset.Table(cfg.BQTable)
+
+	if _, err := table.Metadata(ctx); err == nil {
+		return table, nil
+	} else if !isBigQueryStatus(err, http.StatusNotFound) {
+		return nil, fmt.Errorf("테이블 %s.%s 확인 실패: %v", cfg.BQDataset, cfg.BQTable, err)
+	}
+
+	if _, err := dataset.Metadata(ctx); isBigQueryStatus(err, http.StatusNotFound) {
+		// 동시에 실행된 다른 프로세스가 먼저 만든 경우(409)는 무시
+		if err := dataset.Create(ctx, &bigquery.DatasetMetadata{}); err != nil && !isBigQueryStatus(err, http.StatusConflict) {
+			return nil, fmt.Errorf("데이터셋 %s 생성 실패: %v", cfg.BQDataset, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("데이터셋 %s 확인 실패: %v", cfg.BQDataset, err)
+	}
+
+	metadata := &bigquery.TableMetadata{
+		Schema:           schema,
+		TimePartitioning: &bigquery.TimePartitioning{Field: "timestamp"},
+	}
+	if err := table.Create(ctx, metadata); err != nil && !isBigQueryStatus(err, http.StatusConflict) {
+		return nil, fmt.Errorf("테이블 %s.%s 생성 실패: %v", cfg.BQDataset, cfg.BQTable, err)
+	}
+	return table, nil
+}
+
+func isBigQueryStatus(err error, status int) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == status
+}
+
+func (s *bigQuerySink) Write(ctx context.Context, events []config.SQLEvent) error {
+	rows := make([]*bigquery.StructSaver, len(events))
+	for i := range events {
+		event := &events[i]
+		row := &bigQueryRow{
+			Timestamp:   event.Timestamp,
+			EventType:   event.EventType,
+			Database:    event.Database,
+			Table:       bigquery.NullString{StringVal: event.Table, Valid: event.Table != ""},
+			SQL:         event.SQL,
+			OriginalSQL: bigquery.NullString{StringVal: event.OriginalSQL, Valid: event.OriginalSQL != ""},
+			ServerID:    int64(event.ServerId),
+			Filename:    event.Filename,
+			Position:    int64(event.Position),
+			EventSize:   int64(event.EventSize),
+			RowCount:    int64(event.RowCount),
+		}
+		if event.CapturedAt != nil {
+			row.CapturedAt = bigquery.NullTimestamp{Timestamp: *event.CapturedAt, Valid: true}
+		}
+		// 재시도 시 중복 삽입을 줄이기 위해 이벤트 위치를 삽입 ID로 사용
+		rows[i] = &bigquery.StructSaver{Struct: row, Schema: s.schema, InsertID: eventKey(event)}
+	}
+	return s.inserter.Put(ctx, rows)
+}
+
+func (s *bigQuerySink) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,85 @@
+package src
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// --binlog-files 항목의 파일 번호 범위
+type binlogFileSpan struct {
+	from, to int
+}
+
+// --binlog-files로 지정한 파일 (SHOW BINARY LOGS 순서, 서버에 없는 파일을 지정하면 오류)
+// 항목은 파일 이름, 파일 번호, 또는 둘 중 하나로 쓴 범위 (mysql-bin.000120, 000120-000125)
+func (ba *BinlogAnalyzer) selectBinlogFiles(files []config.BinlogFile, specs []string) ([]config.BinlogFile, error) {
+	numbers := make(map[string]int, len(files))
+	for _, file := range files {
+		numbers[file.Name] = ba.extractFileNumber(file.Name)
+	}
+
+	selected := make(map[string]bool)
+	for _, spec := range specs {
+		span, err := parseBinlogFileSpan(strings.TrimSpace(spec), numbers)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, file := range files {
+			if number := numbers[file.Name]; number >= span.from && number <= span.to {
+				selected[file.Name] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("--binlog-files의 %s에 해당하는 binary log 파일이 서버에 없습니다 (SHOW BINARY LOGS)", spec)
+		}
+	}
+
+	var targets []config.BinlogFile
+	for _, file := range files {
+		if selected[file.Name] {
+			targets = append(targets, file)
+		}
+	}
+	return targets, nil
+}
+
+// 항목 하나를 파일 번호 범위로 변환 (파일 이름에도 -가 들어가므로 양쪽이 모두 해석되는 위치에서 나눔)
+func parseBinlogFileSpan(spec string, numbers map[string]int) (binlogFileSpan, error) {
+	if number, ok := binlogFileNumber(spec, numbers); ok {
+		return binlogFileSpan{number, number}, nil
+	}
+	for i := strings.Index(spec, "-"); i >= 0; {
+		from, okFrom := binlogFileNumber(spec[:i], numbers)
+		to, okTo := binlogFileNumber(spec[i+1:], numbers)
+		if okFrom && okTo {
+			if from > to {
+				return binlogFileSpan{}, fmt.Errorf("--binlog-files 범위의 시작이 끝보다 큽니다: %s", spec)
+			}
+			return binlogFileSpan{from, to}, nil
+		}
+
+		next := strings.Index(spec[i+1:], "-")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return binlogFileSpan{}, fmt.Errorf("--binlog-files 항목을 해석할 수 없습니다: %q (파일 이름, 번호 또는 범위, 예: mysql-bin.000120, 000120-000125)", spec)
+}
+
+// 파일 이름 또는 번호 (숫자만 쓰면 파일 번호)
+func binlogFileNumber(value string, numbers map[string]int) (int, bool) {
+	if number, ok := numbers[value]; ok {
+		return number, true
+	}
+	if number, err := strconv.Atoi(value); err == nil && number >= 0 {
+		return number, true
+	}
+	return 0, false
+}
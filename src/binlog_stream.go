@@ -0,0 +1,207 @@
+package src
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"mysqlbinlogo/config"
+
+	"github.com/go-mysql-org/go-mysql/client"
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// binary log 이벤트 스트림
+// 기본은 go-mysql BinlogSyncer를 사용하고, --compress-protocol이면 압축 연결로 직접 덤프 요청
+type binlogStream interface {
+	GetEvent(ctx context.Context) (*replication.BinlogEvent, error)
+	Close()
+}
+
+// 지정한 위치부터 binary log 스트리밍 시작
+func openBinlogStream(cfg config.Config, serverID uint32, pos mysql.Position) (binlogStream, error) {
+	if cfg.CompressProtocol {
+		stream, err := openCompressedStream(cfg, serverID, pos)
+		if err != nil {
+			return nil, err
+		}
+		return stream, nil
+	}
+
+	syncer := replication.NewBinlogSyncer(newSyncerConfig(cfg, serverID))
+	streamer, err := syncer.StartSync(pos)
+	if err != nil {
+		syncer.Close()
+		return nil, err
+	}
+	return &syncerStream{syncer: syncer, streamer: streamer}, nil
+}
+
+// BinlogSyncer 기반 스트림
+type syncerStream struct {
+	syncer   *replication.BinlogSyncer
+	streamer *replication.BinlogStreamer
+}
+
+func (s *syncerStream) GetEvent(ctx context.Context) (*replication.BinlogEvent, error) {
+	return s.streamer.GetEvent(ctx)
+}
+
+func (s *syncerStream) Close() {
+	s.syncer.Close()
+}
+
+// 압축 프로토콜 연결로 받는 스트림
+// BinlogSyncer는 연결 시 압축을 협상할 수 없으므로 COM_BINLOG_DUMP를 직접 보내고 이벤트를 파싱
+type compressedStream struct {
+	conn   *client.Conn
+	events chan *replication.BinlogEvent
+	failed chan struct{} // 읽기 오류 발생 시 닫힘 (err에 원인 보관)
+	err    error
+	done   chan struct{}
+}
+
+// 압축 연결을 열고 덤프 시작
+func openCompressedStream(cfg config.Config, serverID uint32, pos mysql.Position) (*compressedStream, error) {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = 10 * time.Second // BinlogSyncer와 동일
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	conn, err := client.ConnectWithDialer(ctx, "tcp", addr, cfg.User, cfg.Password, "", newDialer(cfg).DialContext,
+		func(c *client.Conn) error {
+			c.SetTLSConfig(cfg.TLS)
+			c.SetCapability(mysql.CLIENT_COMPRESS)
+			c.SetAttributes(map[string]string{"_client_role": "binary_log_listener"})
+			c.ReadTimeout = cfg.ReadTimeout
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if period := heartbeatPeriod(cfg); period > 0 {
+		if _, err := conn.Execute(fmt.Sprintf("SET @master_heartbeat_period=%d", period)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	// 체크섬을 이해하는 클라이언트임을 알림 (BinlogSyncer와 동일)
+	if r, err := conn.Execute("SHOW GLOBAL VARIABLES LIKE 'BINLOG_CHECKSUM'"); err == nil {
+		if value, _ := r.GetString(0, 1); value != "" {
+			if _, err := conn.Execute(`SET @master_binlog_checksum='NONE', @source_binlog_checksum='NONE'`); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if err := writeBinlogDumpCommand(conn, serverID, pos); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("COM_BINLOG_DUMP 실패: %v", err)
+	}
+
+	s := &compressedStream{
+		conn:   conn,
+		events: make(chan *replication.BinlogEvent, 1024),
+		failed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// COM_BINLOG_DUMP 전송
+func writeBinlogDumpCommand(conn *client.Conn, serverID uint32, pos mysql.Position) error {
+	conn.ResetSequence()
+
+	data := make([]byte, 4+1+4+2+4+len(pos.Name))
+	offset := 4
+	data[offset] = mysql.COM_BINLOG_DUMP
+	offset++
+	binary.LittleEndian.PutUint32(data[offset:], pos.Pos)
+	offset += 4
+	binary.LittleEndian.PutUint16(data[offset:], 0) // flags
+	offset += 2
+	binary.LittleEndian.PutUint32(data[offset:], serverID)
+	offset += 4
+	copy(data[offset:], pos.Name)
+
+	return conn.WritePacket(data)
+}
+
+// 패킷을 읽어 이벤트로 변환 (연결이 닫히거나 오류가 나면 종료)
+func (s *compressedStream) run() {
+	parser := replication.NewBinlogParser()
+	parser.SetFlavor("mysql")
+
+	for {
+		data, err := s.conn.ReadPacket()
+		if err == nil {
+			switch data[0] {
+			case mysql.OK_HEADER:
+				var ev *replication.BinlogEvent
+				if ev, err = parser.Parse(data[1:]); err == nil {
+					select {
+					case s.events <- ev:
+						continue
+					case <-s.done:
+						return
+					}
+				}
+			case mysql.ERR_HEADER:
+				err = s.conn.HandleErrorPacket(data)
+			case mysql.EOF_HEADER:
+				err = fmt.Errorf("binary log 스트림 종료")
+			default:
+				err = fmt.Errorf("알 수 없는 패킷: %x", data[0])
+			}
+		}
+
+		s.err = err
+		close(s.failed)
+		return
+	}
+}
+
+func (s *compressedStream) GetEvent(ctx context.Context) (*replication.BinlogEvent, error) {
+	// 오류 전에 받은 이벤트를 먼저 반환
+	select {
+	case ev := <-s.events:
+		return ev, nil
+	default:
+	}
+
+	select {
+	case ev := <-s.events:
+		return ev, nil
+	case <-s.failed:
+		select {
+		case ev := <-s.events:
+			return ev, nil
+		default:
+		}
+		return nil, s.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *compressedStream) Close() {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	close(s.done)
+	s.conn.Close()
+}
@@ -0,0 +1,345 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mysqlbinlogo/config"
+
+	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	perrors "github.com/pingcap/errors"
+)
+
+// 추출 백엔드 이름
+const (
+	BackendNative = "native" // 자체 row 이벤트 해석 (기본값)
+	BackendCanal  = "canal"  // go-mysql canal의 테이블 메타데이터/타입 해석 사용
+)
+
+// 파일 단위 이벤트 추출기 (백엔드별 구현)
+type eventExtractor interface {
+	ExtractFromSingleFile(ctx context.Context, file config.BinlogFile) ([]config.SQLEvent, error)
+	Close()
+}
+
+// 설정된 백엔드의 추출기 생성
+func (ba *BinlogAnalyzer) newExtractor() eventExtractor {
+	cfg := ba.Config
+	if ba.resume != nil {
+		// 기록한 위치부터 이어 읽으므로 시작 시간으로 거르지 않음 (헤더 시각이 지난 구간 끝보다 이른 이벤트 포함)
+		cfg.StartTime = time.Time{}
+	}
+	if cfg.Backend == BackendCanal {
+		extractor := NewCanalExtractor(cfg, ba.schema)
+		extractor.renderer.gtids = ba.gtids
+		extractor.renderer.lags = ba.lags
+		extractor.renderer.resume = ba.resume
+		return extractor
+	}
+	extractor := NewSQLExtractor(cfg, ba.schema)
+	extractor.gtids = ba.gtids
+	extractor.lags = ba.lags
+	extractor.resume = ba.resume
+	return extractor
+}
+
+// 백엔드 이름 검증
+func validateBackend(backend string) error {
+	switch backend {
+	case "", BackendNative, BackendCanal:
+		return nil
+	}
+	return fmt.Errorf("지원하지 않는 --backend 값: %s (%s 또는 %s)", backend, BackendNative, BackendCanal)
+}
+
+// 파일 처리를 정상 종료시키기 위해 핸들러가 반환하는 에러
+var errCanalFileDone = errors.New("canal: file done")
+
+// go-mysql canal 기반 SQL 이벤트 추출기
+// 컬럼 이름/부호 없는 정수 변환은 canal이 조회한 테이블 메타데이터를 사용하고,
+// DDL이 나오면 canal이 테이블 캐시를 갱신함 (SQL 출력 형식은 native 백엔드와 동일)
+type CanalExtractor struct {
+	config   config.Config
+	renderer *SQLExtractor // pseudo-SQL 생성용
+}
+
+// 새 canal 추출기 생성
+func NewCanalExtractor(cfg config.Config, schema *SchemaSnapshot) *CanalExtractor {
+	return &CanalExtractor{
+		config:   cfg,
+		renderer: NewSQLExtractor(cfg, schema),
+	}
+}
+
+// 추출기 종료
+func (ce *CanalExtractor) Close() {
+	ce.renderer.Close()
+}
+
+// ExtractFromSingleFile 단일 파일에서 SQL 이벤트 추출 (파일마다 새로운 canal 사용, ctx 취소 시 오류 반환)
+func (ce *CanalExtractor) ExtractFromSingleFile(ctx context.Context, file config.BinlogFile) ([]config.SQLEvent, error) {
+	// 목록을 가져올 때의 끝 위치까지 이미 읽었으면 (--incremental로 이어 읽는 파일) 새 이벤트가 없음
+	position := ce.renderer.readFrom(file.Name)
+	if reachedEnd(file, position) {
+		return nil, nil
+	}
+
+	cfg := &canal.Config{
+		Addr:             fmt.Sprintf("%s:%d", ce.config.Host, ce.config.Port),
+		User:             ce.config.User,
+		Password:         ce.config.Password,
+		Charset:          mysql.DEFAULT_CHARSET,
+		ServerID:         100,
+		Flavor:           "mysql",
+		DisableRetrySync: true,
+		TLSConfig:        ce.config.TLS,
+		ReadTimeout:      ce.config.ReadTimeout,
+		HeartbeatPeriod:  heartbeatPeriod(ce.config),
+		Dialer:           newDialer(ce.config).DialContext,
+		Logger:           config.NewNullLogger(),
+	}
+	// Dump.ExecutionPath를 비워 mysqldump 단계를 건너뜀
+
+	c, err := canal.NewCanal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("canal 생성 실패: %v", err)
+	}
+
+	handler := &canalEventHandler{
+		extractor: ce,
+		filename:  file.Name,
+		size:      file.Size,
+		position:  position,
+	}
+	c.SetEventHandler(handler)
+
+	// 타임아웃 설정 (native 백엔드와 동일)
+	timer := time.AfterFunc(60*time.Second, c.Close)
+	stopCancel := context.AfterFunc(ctx, c.Close)
+
+	err = c.RunFrom(mysql.Position{Name: file.Name, Pos: position})
+	stopCancel()
+	timedOut := !timer.Stop()
+	if !timedOut {
+		c.Close()
+	}
+	if ctx.Err() != nil {
+		return handler.events, ctx.Err()
+	}
+	if err != nil && perrors.Cause(err) != errCanalFileDone {
+		if len(handler.events) == 0 {
+			return nil, fmt.Errorf("파일 %s canal 처리 실패: %v", file.Name, err)
+		}
+		// 일부 이벤트를 읽은 뒤의 연결 종료는 native 백엔드처럼 읽은 만큼 반환하고 이후 이벤트가 빠졌음을 알림
+		return handler.events, &partialReadError{file: file.Name, position: handler.position, err: err}
+	}
+	if err == nil && timedOut {
+		// 60초 제한으로 canal이 닫혀 파일 끝까지 읽지 못함
+		return handler.events, &partialReadError{file: file.Name, position: handler.position, err: errFileTimeout}
+	}
+
+	return handler.events, nil
+}
+
+// canal 이벤트를 SQLEvent로 모으는 핸들러
+type canalEventHandler struct {
+	canal.DummyEventHandler
+
+	extractor   *CanalExtractor
+	filename    string
+	size        int64  // 목록을 가져올 때의 파일 크기 (SHOW BINARY LOGS)
+	position    uint32 // 마지막으로 끝난 트랜잭션의 끝 위치
+	events      []config.SQLEvent
+	transaction string    // 진행 중인 트랜잭션 식별자 (GTID)
+	commitTime  time.Time // 진행 중인 트랜잭션의 커밋 시각 (MySQL 8.0.1 이상)
+	pastEnd     int       // 연속된 종료 시간 이후 이벤트 수
+	inWindow    int       // 구간 안 이벤트 수 (--max-events-per-file)
+}
+
+// 시간 범위 확인 (종료 시간 이후 이벤트가 --past-end-events개 연속되면 파일 처리 종료)
+func (h *canalEventHandler) inRange(header *replication.EventHeader) (bool, error) {
+	// native 백엔드와 같이 목록을 가져온 뒤 기록된 이벤트는 읽지 않음 (--incremental의 다음 시작 위치)
+	if h.size > 0 && header.LogPos > 0 && int64(eventStartPosition(header)) >= h.size {
+		return false, errCanalFileDone
+	}
+	eventTime := h.eventTime(header)
+	if h.extractor.config.Verbose >= config.VerboseEvents {
+		traceEvent(h.filename, header, eventTime)
+	}
+	if beforeWindow(eventTime, !h.commitTime.IsZero(), h.extractor.config.StartTime) {
+		return false, nil
+	}
+	if eventTime.After(h.extractor.config.EndTime) {
+		// native 백엔드와 같이 --past-end-events개 연속될 때까지 계속 읽음
+		h.pastEnd++
+		if h.pastEnd >= max(h.extractor.config.PastEndEvents, 1) {
+			return false, errCanalFileDone
+		}
+		return false, nil
+	}
+	h.pastEnd = 0
+	h.inWindow++
+	if limit := h.extractor.config.MaxEventsPerFile; limit > 0 && h.inWindow > limit {
+		warnEventLimit(h.filename, limit)
+		return false, errCanalFileDone
+	}
+	return true, nil
+}
+
+// 이벤트 시각 (native 백엔드와 같이 트랜잭션의 커밋 시각 우선)
+func (h *canalEventHandler) eventTime(header *replication.EventHeader) time.Time {
+	if !h.commitTime.IsZero() {
+		return h.commitTime
+	}
+	return time.Unix(int64(header.Timestamp), 0)
+}
+
+// 다음 파일로 넘어가면 처리 종료
+func (h *canalEventHandler) OnRotate(header *replication.EventHeader, e *replication.RotateEvent) error {
+	if string(e.NextLogName) != h.filename {
+		return errCanalFileDone
+	}
+	return nil
+}
+
+// 트랜잭션이 끝날 때마다 호출됨 (목록을 가져올 때의 파일 끝에 도달하면 처리 종료)
+// 기록 중인 마지막 파일은 ROTATE가 오지 않으므로 다음 이벤트를 기다리지 않음
+func (h *canalEventHandler) OnPosSynced(header *replication.EventHeader, pos mysql.Position, set mysql.GTIDSet, force bool) error {
+	if pos.Name != h.filename {
+		return nil
+	}
+	h.position = pos.Pos
+	if h.size > 0 && int64(pos.Pos) >= h.size {
+		return errCanalFileDone
+	}
+	return nil
+}
+
+// 트랜잭션 시작 (canal은 BEGIN을 전달하지 않으므로 GTID 이벤트로 구분)
+func (h *canalEventHandler) OnGTID(header *replication.EventHeader, e mysql.BinlogGTIDEvent) error {
+	h.commitTime = gtidCommitTime(e)
+	if mariadb, ok := e.(*replication.MariadbGTIDEvent); ok {
+		h.transaction = mariadb.GTID.String()
+		return nil
+	}
+	h.transaction = gtidTransactionID(header, e, h.filename)
+
+	ok, err := h.inRange(header)
+	if !ok {
+		return err
+	}
+	if header.EventType != replication.ANONYMOUS_GTID_EVENT {
+		h.extractor.renderer.gtids.add(e)
+	}
+	h.extractor.renderer.lags.add(e, h.transaction)
+	return nil
+}
+
+func (h *canalEventHandler) OnXID(header *replication.EventHeader, nextPos mysql.Position) error {
+	h.transaction = ""
+	h.commitTime = time.Time{}
+	return nil
+}
+
+// 이벤트가 속한 트랜잭션 (알 수 없으면 이벤트 자신의 시작 위치)
+func (h *canalEventHandler) currentTransaction(header *replication.EventHeader) string {
+	if h.transaction != "" {
+		return h.transaction
+	}
+	return transactionPosition(header, h.filename)
+}
+
+// DDL 쿼리 (canal은 테이블 구조를 바꾸는 쿼리만 전달)
+func (h *canalEventHandler) OnDDL(header *replication.EventHeader, pos mysql.Position, e *replication.QueryEvent) error {
+	ok, err := h.inRange(header)
+	if !ok {
+		return err
+	}
+
+	// DDL은 쿼리 하나가 트랜잭션
+	transaction := h.currentTransaction(header)
+	timestamp := h.eventTime(header)
+	h.transaction = ""
+	h.commitTime = time.Time{}
+
+	query := string(e.Query)
+	if h.extractor.renderer.skipQuery(query) {
+		return nil
+	}
+
+	h.events = append(h.events, config.SQLEvent{
+		Timestamp:   timestamp,
+		EventType:   "QUERY",
+		Database:    string(e.Schema),
+		SQL:         query,
+		ServerId:    header.ServerID,
+		Position:    header.LogPos,
+		Filename:    h.filename,
+		EventSize:   header.EventSize,
+		ExecTime:    e.ExecutionTime,
+		ErrorCode:   e.ErrorCode,
+		Session:     parseStatusVars(e.StatusVars),
+		Transaction: transaction,
+
+		StartPosition: eventStartPosition(header),
+	})
+	return nil
+}
+
+// row 이벤트 (canal 테이블 메타데이터의 컬럼 이름 사용)
+func (h *canalEventHandler) OnRow(e *canal.RowsEvent) error {
+	ok, err := h.inRange(e.Header)
+	if !ok {
+		return err
+	}
+
+	var eventType string
+	switch e.Action {
+	case canal.InsertAction:
+		eventType = "INSERT"
+	case canal.UpdateAction:
+		eventType = "UPDATE"
+	case canal.DeleteAction:
+		eventType = "DELETE"
+	default:
+		return nil
+	}
+
+	event := &config.SQLEvent{
+		Timestamp: h.eventTime(e.Header),
+		EventType: eventType,
+		Database:  e.Table.Schema,
+		Table:     e.Table.Name,
+		ServerId:  e.Header.ServerID,
+		Position:  e.Header.LogPos,
+		Filename:  h.filename,
+		EventSize: e.Header.EventSize,
+		RowCount:  len(e.Rows),
+		Rows:      e.Rows,
+
+		Transaction: h.currentTransaction(e.Header),
+
+		StartPosition: eventStartPosition(e.Header),
+	}
+	if eventType == "UPDATE" {
+		event.RowCount = len(e.Rows) / 2 // before/after 쌍
+	}
+	if len(e.Rows) == 0 || len(e.Rows[0]) == len(e.Table.Columns) {
+		event.Columns = make([]string, len(e.Table.Columns))
+		for i, col := range e.Table.Columns {
+			event.Columns[i] = col.Name
+		}
+	}
+	event.SQL = h.extractor.renderer.formatRowsSQL(event)
+
+	h.events = append(h.events, *event)
+	return nil
+}
+
+func (h *canalEventHandler) String() string {
+	return "mysqlbinlogo"
+}
@@ -0,0 +1,180 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// Canal flat message (canal.instance 의 flatMessage=true 형태)
+type canalMessage struct {
+	Data      []map[string]interface{} `json:"data"`
+	Database  string                   `json:"database"`
+	ES        int64                    `json:"es"` // 이벤트 시각 (밀리초)
+	ID        int64                    `json:"id"`
+	IsDDL     bool                     `json:"isDdl"`
+	MySQLType map[string]string        `json:"mysqlType"`
+	Old       []map[string]interface{} `json:"old"`
+	PKNames   []string                 `json:"pkNames"`
+	SQL       string                   `json:"sql"`
+	SQLType   map[string]int           `json:"sqlType"`
+	Table     string                   `json:"table"`
+	TS        int64                    `json:"ts"` // 출력 시각 (밀리초)
+	Type      string                   `json:"type"`
+}
+
+// DDL 첫 키워드 → Canal 이벤트 종류
+var canalDDLTypes = map[string]string{
+	"CREATE":   "CREATE",
+	"ALTER":    "ALTER",
+	"DROP":     "ERASE",
+	"RENAME":   "RENAME",
+	"TRUNCATE": "TRUNCATE",
+}
+
+// MySQL 컬럼 타입 → java.sql.Types 값 (Canal의 sqlType)
+var canalSQLTypes = map[string]int{
+	"bit": -7, "tinyint": -6, "smallint": 5, "mediumint": 4, "int": 4, "integer": 4, "bigint": -5,
+	"float": 7, "double": 8, "decimal": 3, "numeric": 3,
+	"char": 1, "varchar": 12, "tinytext": 12, "text": 2005, "mediumtext": 2005, "longtext": 2005,
+	"binary": -2, "varbinary": -3, "tinyblob": -3, "blob": 2004, "mediumblob": 2004, "longblob": 2004,
+	"date": 91, "time": 92, "datetime": 93, "timestamp": 93, "year": 12,
+	"enum": 4, "set": -7, "json": 12,
+}
+
+// Canal flat message 출력기 (binlog 이벤트마다 한 줄)
+type canalWriter struct {
+	encoder  *json.Encoder
+	renderer *SQLExtractor // 스키마 스냅샷의 컬럼 타입과 PK 조회용
+	id       int64
+}
+
+func newCanalWriter(output io.Writer, renderer *SQLExtractor) *canalWriter {
+	return &canalWriter{encoder: json.NewEncoder(output), renderer: renderer}
+}
+
+func (w *canalWriter) writeEvent(event *config.SQLEvent) {
+	w.id++
+	message := canalMessage{
+		Database: event.Database,
+		ES:       event.Timestamp.UnixMilli(),
+		ID:       w.id,
+		Table:    event.Table,
+		TS:       time.Now().UnixMilli(),
+		Type:     event.EventType,
+	}
+
+	if event.EventType == "QUERY" {
+		// DDL은 isDdl=true, 그 외 문장 기반 쿼리는 QUERY
+		message.SQL = event.SQL
+		message.Type = "QUERY"
+		if fields := strings.Fields(event.SQL); len(fields) > 0 {
+			if typ, ok := canalDDLTypes[strings.ToUpper(fields[0])]; ok {
+				message.Type = typ
+				message.IsDDL = true
+			}
+		}
+		w.encoder.Encode(message)
+		return
+	}
+
+	message.MySQLType, message.SQLType = w.columnTypes(event)
+	for _, idx := range w.renderer.primaryKeyIndexes(event) {
+		message.PKNames = append(message.PKNames, columnName(event.Columns, idx))
+	}
+
+	step := 1
+	if event.EventType == "UPDATE" {
+		step = 2
+	}
+	for i := 0; i < len(event.Rows); i += step {
+		row := event.Rows[i]
+		if event.EventType != "UPDATE" || i+1 >= len(event.Rows) {
+			message.Data = append(message.Data, canalRow(event, i))
+			continue
+		}
+
+		// UPDATE는 data에 새 값, old에 바뀐 컬럼의 이전 값만 넣음
+		after := event.Rows[i+1]
+		message.Data = append(message.Data, canalRow(event, i+1))
+		old := make(map[string]interface{})
+		for j := range row {
+			if !columnLogged(event, i, j) {
+				continue
+			}
+			if j >= len(after) || !columnLogged(event, i+1, j) || !w.renderer.valuesEqual(row[j], after[j]) {
+				old[columnName(event.Columns, j)] = canalValue(row[j])
+			}
+		}
+		message.Old = append(message.Old, old)
+	}
+	w.encoder.Encode(message)
+}
+
+func (w *canalWriter) finish() {}
+
+// 스냅샷의 컬럼 타입 (이벤트의 컬럼 구성과 다르면 nil)
+func (w *canalWriter) columnTypes(event *config.SQLEvent) (map[string]string, map[string]int) {
+	ts := w.renderer.schema.Table(event.Database, event.Table)
+	if ts == nil || len(ts.Columns) != len(event.Columns) {
+		return nil, nil
+	}
+
+	mysqlTypes := make(map[string]string, len(ts.Columns))
+	sqlTypes := make(map[string]int, len(ts.Columns))
+	for i, col := range ts.Columns {
+		if !strings.EqualFold(col.Name, event.Columns[i]) {
+			return nil, nil
+		}
+		mysqlTypes[col.Name] = col.Type
+		base, _, _ := strings.Cut(col.Type, "(")
+		base, _, _ = strings.Cut(base, " ")
+		if sqlType, ok := canalSQLTypes[strings.ToLower(base)]; ok {
+			sqlTypes[col.Name] = sqlType
+		}
+	}
+	return mysqlTypes, sqlTypes
+}
+
+// Canal은 모든 값을 문자열로 표현 (NULL은 null, 기록되지 않은 컬럼은 키 없음)
+func canalRow(event *config.SQLEvent, image int) map[string]interface{} {
+	values := event.Rows[image]
+	row := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		if columnLogged(event, image, i) {
+			row[columnName(event.Columns, i)] = canalValue(value)
+		}
+	}
+	return row
+}
+
+func canalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return string(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999")
+	case jsonPartialUpdate:
+		// 부분 JSON 업데이트는 변경 목록의 JSON 문자열
+		text, _ := json.Marshal(v)
+		return string(text)
+	default:
+		return fmt.Sprint(v)
+	}
+}
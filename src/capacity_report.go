@@ -0,0 +1,216 @@
+package src
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 테이블별 쓰기량 보고서 정렬 기준 (--capacity-sort)
+const (
+	CapacitySortBytes  = "bytes"
+	CapacitySortRows   = "rows"
+	CapacitySortEvents = "events"
+	CapacitySortTable  = "table"
+)
+
+// 메시지에 요약하는 테이블 수 (파일에는 모두 기록)
+const capacitySummaryLimit = 10
+
+// 테이블 하나의 한 시간 쓰기량 (--capacity-report의 한 줄)
+type capacityBucket struct {
+	Database string    `json:"database"`
+	Table    string    `json:"table"` // statement 기반 문장에서 테이블을 찾지 못하면 빈 문자열
+	Hour     time.Time `json:"hour"`  // 시간 시작 시각 (UTC)
+	Events   int       `json:"events"`
+	Rows     int       `json:"rows"`
+	Bytes    int64     `json:"bytes"` // row/query 이벤트 크기 합 (GTID, BEGIN, XID, table map 이벤트 제외)
+}
+
+// 테이블 하나의 구간 전체 쓰기량
+type capacityTable struct {
+	database, table string
+	events, rows    int
+	bytes           int64
+	hours           []*capacityBucket // 시간순
+}
+
+func (t *capacityTable) name() string {
+	return groupName(eventGroup{database: t.database, table: t.table})
+}
+
+// 가장 많이 기록한 시간
+func (t *capacityTable) peak() *capacityBucket {
+	peak := t.hours[0]
+	for _, bucket := range t.hours[1:] {
+		if bucket.Bytes > peak.Bytes {
+			peak = bucket
+		}
+	}
+	return peak
+}
+
+func validateCapacityReport(cfg config.Config) error {
+	if cfg.CapacityReport == "" {
+		return nil
+	}
+	switch cfg.CapacitySort {
+	case "", CapacitySortBytes, CapacitySortRows, CapacitySortEvents, CapacitySortTable:
+	default:
+		return fmt.Errorf("지원하지 않는 --capacity-sort입니다: %s (bytes, rows, events, table)", cfg.CapacitySort)
+	}
+	if cfg.Follow {
+		return fmt.Errorf("--capacity-report는 --follow와 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+// 이벤트를 테이블, 시간별로 집계하여 정렬 기준에 따라 정렬 (테이블 안에서는 시간순)
+// statement 기반 문장은 처음 나오는 테이블로 집계
+func buildCapacityReport(events []config.SQLEvent, sortBy string) []*capacityTable {
+	type tableKey struct{ database, table string }
+	type hourKey struct {
+		tableKey
+		hour time.Time
+	}
+
+	tables := make(map[tableKey]*capacityTable)
+	buckets := make(map[hourKey]*capacityBucket)
+	for i := range events {
+		event := &events[i]
+		key := tableKey{event.Database, event.Table}
+		if event.EventType == "QUERY" {
+			if names := queryTables(event.Database, event.SQL); len(names) > 0 {
+				database, table, _ := strings.Cut(names[0], ".")
+				key = tableKey{database, table}
+			}
+		}
+
+		table := tables[key]
+		if table == nil {
+			table = &capacityTable{database: key.database, table: key.table}
+			tables[key] = table
+		}
+		hour := hourKey{key, event.Timestamp.UTC().Truncate(time.Hour)}
+		bucket := buckets[hour]
+		if bucket == nil {
+			bucket = &capacityBucket{Database: key.database, Table: key.table, Hour: hour.hour}
+			buckets[hour] = bucket
+			table.hours = append(table.hours, bucket)
+		}
+
+		bucket.Events++
+		bucket.Rows += event.RowCount
+		bucket.Bytes += int64(event.EventSize)
+		table.events++
+		table.rows += event.RowCount
+		table.bytes += int64(event.EventSize)
+	}
+
+	report := make([]*capacityTable, 0, len(tables))
+	for _, table := range tables {
+		slices.SortFunc(table.hours, func(a, b *capacityBucket) int {
+			return a.Hour.Compare(b.Hour)
+		})
+		report = append(report, table)
+	}
+	slices.SortFunc(report, func(a, b *capacityTable) int {
+		var c int
+		switch sortBy {
+		case CapacitySortRows:
+			c = cmp.Compare(b.rows, a.rows)
+		case CapacitySortEvents:
+			c = cmp.Compare(b.events, a.events)
+		case CapacitySortTable:
+		default:
+			c = cmp.Compare(b.bytes, a.bytes)
+		}
+		return cmp.Or(c, cmp.Compare(a.database, b.database), cmp.Compare(a.table, b.table))
+	})
+	return report
+}
+
+// 테이블별 시간당 쓰기량 파일 저장 (확장자가 .json이면 JSON 배열, 그 밖에는 CSV)과 상위 테이블 요약 출력
+func (ba *BinlogAnalyzer) writeCapacityReport(w io.Writer, events []config.SQLEvent) error {
+	if ba.Config.CapacityReport == "" {
+		return nil
+	}
+
+	report := buildCapacityReport(events, ba.Config.CapacitySort)
+	var buckets []*capacityBucket
+	for _, table := range report {
+		buckets = append(buckets, table.hours...)
+	}
+
+	file, err := os.Create(ba.Config.CapacityReport)
+	if err != nil {
+		return fmt.Errorf("쓰기량 보고서 파일 생성 실패: %v", err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(ba.Config.CapacityReport), ".json") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(buckets)
+	} else {
+		err = writeCapacityCSV(file, buckets)
+	}
+	if err != nil {
+		return fmt.Errorf("쓰기량 보고서 파일 기록 실패: %v", err)
+	}
+	logrus.Infof(T("Capacity report saved to %s (%d tables, %d table-hours)"), ba.Config.CapacityReport, len(report), len(buckets))
+
+	writeCapacitySummary(w, report, ba.Config.EndTime.Sub(ba.Config.StartTime))
+	return nil
+}
+
+func writeCapacityCSV(w io.Writer, buckets []*capacityBucket) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"database", "table", "hour", "events", "rows", "bytes"})
+	for _, bucket := range buckets {
+		writer.Write([]string{
+			bucket.Database,
+			bucket.Table,
+			bucket.Hour.Format(time.RFC3339),
+			strconv.Itoa(bucket.Events),
+			strconv.Itoa(bucket.Rows),
+			strconv.FormatInt(bucket.Bytes, 10),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// 상위 테이블의 구간 전체 쓰기량과 시간당 평균, 가장 많이 기록한 시간
+func writeCapacitySummary(w io.Writer, report []*capacityTable, window time.Duration) {
+	if len(report) == 0 {
+		return
+	}
+	hours := max(window.Hours(), 1)
+	shown := report[:min(len(report), capacitySummaryLimit)]
+
+	fmt.Fprintf(w, T(">> 테이블별 쓰기량 (상위 %d개 / 전체 %d개):\n"), len(shown), len(report))
+	width := 0
+	for _, table := range shown {
+		width = max(width, len(table.name()))
+	}
+	for _, table := range shown {
+		peak := table.peak()
+		name := table.name()
+		fmt.Fprintf(w, T("   %s  %9s, 행 %d개, 이벤트 %d개, 시간당 평균 %s, 최대 %s (%s)\n"),
+			name+strings.Repeat(" ", width-len(name)), formatBytes(table.bytes), table.rows, table.events,
+			formatBytes(int64(float64(table.bytes)/hours)), formatBytes(peak.Bytes), peak.Hour.Format("2006-01-02 15:04"))
+	}
+}
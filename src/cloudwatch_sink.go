@@ -0,0 +1,159 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// PutLogEvents 제한 (한 번에 보내는 이벤트 수와 크기, 이벤트마다 26바이트가 더해짐)
+const (
+	cloudWatchMaxEvents     = 10000
+	cloudWatchMaxBatchBytes = 1048576
+	cloudWatchEventOverhead = 26
+	cloudWatchMaxEventBytes = 256*1024 - cloudWatchEventOverhead
+	cloudWatchMaxSpan       = 24 * time.Hour // 한 요청에 담을 수 있는 이벤트 시각의 범위
+)
+
+// 재시도 (제한 초과, 일시적인 서버 오류는 SDK가 간격을 늘려 가며 다시 보냄)
+const (
+	cloudWatchRetries = 5
+	cloudWatchTimeout = 30 * time.Second
+)
+
+// CloudWatch Logs 전송 싱크 (--sink cloudwatch)
+// 이벤트마다 JSON 로그 이벤트 하나, 로그 이벤트 시각은 이벤트의 커밋 시각
+type cloudWatchSink struct {
+	client *cloudwatchlogs.Client
+	group  string
+	stream string
+}
+
+// 기본 로그 스트림 이름 (스트림 이름에 쓸 수 없는 :와 *는 _로)
+func cloudWatchStreamName(cfg config.Config) string {
+	if cfg.CWLogStream != "" {
+		return cfg.CWLogStream
+	}
+	return strings.NewReplacer(":", "_", "*", "_").Replace("mysqlbinlogo-" + cfg.Host)
+}
+
+func newCloudWatchSink(ctx context.Context, cfg config.Config) (*cloudWatchSink, error) {
+	// 리전(--cw-region이 없을 때), 자격 증명, 엔드포인트(AWS_ENDPOINT_URL_CLOUDWATCH_LOGS, AWS_ENDPOINT_URL)는 AWS SDK 기본 설정을 따름
+	// 자격 증명은 환경 변수, 공유 설정 파일(프로필, SSO), 웹 ID 토큰, ECS 작업 역할, EC2 인스턴스 역할 순서
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(cfg.CWRegion),
+		awsconfig.WithRetryMaxAttempts(cloudWatchRetries),
+		awsconfig.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(cloudWatchTimeout)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("AWS 설정 로드 실패: %v", err)
+	}
+	if awsConfig.Region == "" {
+		return nil, fmt.Errorf("--sink cloudwatch는 --cw-region, AWS_REGION 환경 변수 또는 공유 설정 파일의 region이 필요합니다")
+	}
+
+	s := &cloudWatchSink{
+		client: cloudwatchlogs.NewFromConfig(awsConfig),
+		group:  cfg.CWLogGroup,
+		stream: cloudWatchStreamName(cfg),
+	}
+
+	// 로그 그룹은 보존 기간 등을 따로 관리하므로 만들지 않고, 스트림만 없으면 생성
+	_, err = s.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(s.group),
+		LogStreamName: aws.String(s.stream),
+	})
+	var exists *types.ResourceAlreadyExistsException
+	if errors.As(err, &exists) {
+		err = nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("로그 스트림 %s/%s 생성 실패: %v", s.group, s.stream, err)
+	}
+	return s, nil
+}
+
+// 이벤트를 시각순으로 정렬하여 PutLogEvents 제한에 맞게 나눠 전송
+func (s *cloudWatchSink) Write(ctx context.Context, events []config.SQLEvent) error {
+	logEvents := make([]types.InputLogEvent, 0, len(events))
+	for i := range events {
+		data, err := json.Marshal(&events[i])
+		if err != nil {
+			return err
+		}
+		if len(data) > cloudWatchMaxEventBytes {
+			logrus.Warnf("CloudWatch Logs 이벤트 크기 제한을 넘어 잘라서 보냅니다: %s (%d bytes)", eventKey(&events[i]), len(data))
+			data = data[:cloudWatchMaxEventBytes]
+		}
+		message := strings.ToValidUTF8(string(data), "")
+		logEvents = append(logEvents, types.InputLogEvent{Timestamp: aws.Int64(events[i].Timestamp.UnixMilli()), Message: aws.String(message)})
+	}
+	sort.SliceStable(logEvents, func(i, j int) bool {
+		return *logEvents[i].Timestamp < *logEvents[j].Timestamp
+	})
+
+	for start := 0; start < len(logEvents); {
+		end, size := start, 0
+		for end < len(logEvents) && end-start < cloudWatchMaxEvents {
+			eventSize := len(*logEvents[end].Message) + cloudWatchEventOverhead
+			span := time.Duration(*logEvents[end].Timestamp-*logEvents[start].Timestamp) * time.Millisecond
+			if size+eventSize > cloudWatchMaxBatchBytes || span >= cloudWatchMaxSpan {
+				break
+			}
+			size += eventSize
+			end++
+		}
+		if err := s.put(ctx, logEvents[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+func (s *cloudWatchSink) put(ctx context.Context, logEvents []types.InputLogEvent) error {
+	response, err := s.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(s.group),
+		LogStreamName: aws.String(s.stream),
+		LogEvents:     logEvents,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 보존 기간이 지났거나 14일보다 오래된 이벤트는 오류 없이 거부됨
+	if rejected := response.RejectedLogEventsInfo; rejected != nil {
+		logrus.Warnf("CloudWatch Logs가 일부 이벤트를 거부했습니다 (너무 오래됨: %s, 너무 미래: %s, 보존 기간 지남: %s)",
+			rejectedRange(rejected.TooOldLogEventEndIndex, 0, true), rejectedRange(rejected.TooNewLogEventStartIndex, len(logEvents), false), rejectedRange(rejected.ExpiredLogEventEndIndex, 0, true))
+	}
+	return nil
+}
+
+// 거부된 이벤트 수 (index가 끝 인덱스면 그 앞까지, 시작 인덱스면 그 뒤 전부)
+func rejectedRange(index *int32, total int, end bool) string {
+	switch {
+	case index == nil:
+		return "0"
+	case end:
+		return fmt.Sprint(*index + 1)
+	default:
+		return fmt.Sprint(total - int(*index))
+	}
+}
+
+func (s *cloudWatchSink) Close() error {
+	return nil
+}
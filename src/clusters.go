@@ -0,0 +1,203 @@
+package src
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// ClusterTarget 여러 클러스터 분석의 대상 하나 (설정 파일의 targets.<name>)
+type ClusterTarget struct {
+	Name   string
+	Config config.Config // 접속 정보와 분석 구간, 필터 (출력 옵션은 첫 대상의 값 사용)
+}
+
+// 클러스터 하나의 분석 결과 (모든 분석이 끝난 뒤 섹션으로 출력)
+type clusterResult struct {
+	target   ClusterTarget
+	output   bytes.Buffer // 결과
+	messages bytes.Buffer // 요약 메시지
+	run      *runStats
+	err      error
+	duration time.Duration
+}
+
+func validateClusters(targets []ClusterTarget) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("분석할 접속 대상이 없습니다")
+	}
+
+	// 결과를 클러스터별 섹션으로 모아 출력하므로 파일/외부로 따로 내보내는 옵션은 사용할 수 없음
+	// 클러스터를 동시에 분석하므로 --confirm-over 확인도 물을 수 없음 (--yes와 함께면 허용)
+	cfg := targets[0].Config
+	for _, option := range []struct {
+		name string
+		set  bool
+	}{
+		{"--sink", cfg.Sink != ""},
+		{"--split-by", cfg.SplitBy != ""},
+		{"--output-rotate-size", cfg.OutputRotateSize > 0},
+		{"--output-rotate-events", cfg.OutputRotateEvents > 0},
+		{"--timeline", cfg.Timeline != ""},
+		{"--capacity-report", cfg.CapacityReport != ""},
+		{"--confirm-over", cfg.ConfirmOver > 0 && !cfg.AssumeYes},
+		{"--summary-json", cfg.SummaryJSON != ""},
+		{"--replayable", cfg.Replayable},
+		{"--incremental", cfg.Incremental},
+	} {
+		if option.set {
+			return fmt.Errorf("--clusters는 %s와 함께 사용할 수 없습니다", option.name)
+		}
+	}
+	if err := validateOutputFile(cfg); err != nil {
+		return err
+	}
+	if cfg.Format != "" && cfg.Format != FormatText && cfg.Format != FormatVertical {
+		return fmt.Errorf("--clusters는 --format text, vertical에서만 사용할 수 있습니다")
+	}
+	return nil
+}
+
+// AnalyzeClusters 같은 구간을 여러 클러스터에서 동시에 분석하여 클러스터별 섹션과 합계를 출력 (--clusters)
+// 하나라도 실패하면 오류, 실패는 없고 구간 앞부분이 빠지거나 --deadline이 지난 클러스터가 있으면 ErrRangeNotCovered 또는 ErrDeadlineExceeded
+func AnalyzeClusters(ctx context.Context, targets []ClusterTarget) error {
+	if err := validateClusters(targets); err != nil {
+		return err
+	}
+
+	results := make([]*clusterResult, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		result := &clusterResult{target: target}
+		results[i] = result
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// 상세 로그와 진행 기록은 여러 클러스터가 섞이므로 끔
+			cfg := target.Config
+			cfg.OutputFile = ""
+			cfg.Compress = ""
+			cfg.Verbose = 0
+			cfg.ProgressFormat = ""
+			cfg.PushgatewayCluster = target.Name
+
+			analyzer := &BinlogAnalyzer{Config: cfg, messages: &result.messages, results: &result.output}
+			started := time.Now()
+			result.err = analyzer.Analyze(ctx)
+			result.duration = time.Since(started)
+			result.run = analyzer.run
+			logrus.Infof(T("클러스터 %s 분석 완료 (%s)"), target.Name, result.duration.Round(time.Millisecond))
+		}()
+	}
+	wg.Wait()
+
+	cfg := targets[0].Config
+	var output io.Writer = os.Stdout
+	var file io.WriteCloser
+	if cfg.OutputFile != "" {
+		var err error
+		if file, err = createOutputFile(cfg, cfg.OutputFile); err != nil {
+			return err
+		}
+		output = file
+	}
+	checked := &firstErrorWriter{Writer: output}
+	writeClusterResults(checked, cfg, results)
+	err := checked.err
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("결과 파일 기록 실패: %v", err)
+	}
+	if cfg.OutputFile != "" {
+		logrus.Infof(T("Results saved to %s"), cfg.OutputFile)
+	}
+
+	var failed, incomplete []string
+	cause := ErrRangeNotCovered
+	for _, result := range results {
+		switch {
+		case result.err == nil:
+		case IsIncomplete(result.err):
+			incomplete = append(incomplete, result.target.Name)
+			if errors.Is(result.err, ErrDeadlineExceeded) {
+				cause = ErrDeadlineExceeded
+			}
+		default:
+			failed = append(failed, result.target.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("클러스터 %d개 중 %d개 분석 실패: %s", len(results), len(failed), strings.Join(failed, ", "))
+	}
+	if len(incomplete) > 0 {
+		return fmt.Errorf(T("%w (클러스터: %s)"), cause, strings.Join(incomplete, ", "))
+	}
+	return nil
+}
+
+// 클러스터별 섹션 (결과와 요약 메시지)과 전체 합계 출력
+func writeClusterResults(w io.Writer, cfg config.Config, results []*clusterResult) {
+	for _, result := range results {
+		target := result.target
+		fmt.Fprintf(w, T("# ===== 클러스터 %s (%s:%d) =====\n"), target.Name, target.Config.Host, target.Config.Port)
+		w.Write(result.output.Bytes())
+		w.Write(bytes.TrimLeft(result.messages.Bytes(), "\n"))
+		if result.err != nil {
+			fmt.Fprintf(w, T(">> 오류: %v\n"), result.err)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, T(">> 클러스터별 결과 (%s ~ %s UTC):\n"),
+		cfg.StartTime.Format("2006-01-02 15:04:05.999999"), cfg.EndTime.Format("2006-01-02 15:04:05.999999"))
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.target.Name))
+	}
+
+	var totalEvents, totalRows int
+	for _, result := range results {
+		status := runStatusOK
+		switch {
+		case result.err == nil:
+		case IsIncomplete(result.err):
+			status = runStatusIncomplete
+		default:
+			status = runStatusFailed
+		}
+
+		files, events, rows := 0, 0, 0
+		if run := result.run; run != nil {
+			files = len(run.files)
+			for _, count := range run.events {
+				events += count
+			}
+			for _, count := range run.rows {
+				rows += count
+			}
+		}
+		totalEvents += events
+		totalRows += rows
+
+		name := result.target.Name + strings.Repeat(" ", width-len(result.target.Name))
+		fmt.Fprintf(w, T("   %s  %-10s  파일 %d개, 이벤트 %d개, 변경 행 %d개, %s\n"),
+			name, status, files, events, rows, result.duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, T("   합계: 클러스터 %d개, 이벤트 %d개, 변경 행 %d개\n"), len(results), totalEvents, totalRows)
+}
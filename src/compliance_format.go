@@ -0,0 +1,114 @@
+package src
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// 감사 레코드 (SIEM 수집 스키마, SQL 텍스트 대신 누가 무엇을 어떻게 바꿨는지)
+type complianceRecord struct {
+	Timestamp   string                 `json:"timestamp"`        // 커밋 시각 (RFC 3339, UTC, 마이크로초)
+	Actor       string                 `json:"actor"`            // 실행 계정 user@host (알 수 없으면 빈 문자열)
+	Action      string                 `json:"action"`           // insert, update, delete, DDL/DCL 키워드, query
+	Object      string                 `json:"object"`           // db.table (테이블이 없는 문장은 db)
+	RowID       map[string]interface{} `json:"row_id,omitempty"` // 행의 PK 값 (UPDATE는 변경 전, PK를 모르면 생략)
+	Transaction string                 `json:"transaction,omitempty"`
+	Statement   string                 `json:"statement,omitempty"` // 쿼리 이벤트의 SQL (row 변경은 생략)
+	Source      complianceSource       `json:"source"`
+}
+
+// 감사 레코드의 원본 binlog 좌표
+type complianceSource struct {
+	Host        string `json:"host"` // 분석한 서버 (--host)
+	ServerID    uint32 `json:"server_id"`
+	File        string `json:"file"`
+	Position    uint32 `json:"position"`     // 이벤트 시작 위치
+	EndPosition uint32 `json:"end_position"` // 이벤트 끝 위치 (end_log_pos)
+	Row         int    `json:"row"`          // 이벤트 안에서의 행 번호
+}
+
+// 쿼리 이벤트의 첫 키워드 → action (나머지는 query)
+var complianceActions = map[string]bool{
+	"insert": true, "replace": true, "update": true, "delete": true,
+	"create": true, "alter": true, "drop": true, "rename": true, "truncate": true,
+	"grant": true, "revoke": true,
+}
+
+// 감사 레코드 출력기 (row의 행마다, 쿼리 이벤트는 대상 테이블마다 한 줄)
+type complianceWriter struct {
+	encoder  *json.Encoder
+	renderer *SQLExtractor // PK 조회용
+	host     string
+}
+
+func newComplianceWriter(output io.Writer, renderer *SQLExtractor, host string) *complianceWriter {
+	return &complianceWriter{encoder: json.NewEncoder(output), renderer: renderer, host: host}
+}
+
+func (w *complianceWriter) writeEvent(event *config.SQLEvent) {
+	record := complianceRecord{
+		Timestamp:   event.Timestamp.UTC().Format("2006-01-02T15:04:05.999999Z07:00"),
+		Actor:       eventAccount(event),
+		Action:      strings.ToLower(event.EventType),
+		Object:      event.Database + "." + event.Table,
+		Transaction: event.Transaction,
+		Source: complianceSource{
+			Host:        w.host,
+			ServerID:    event.ServerId,
+			File:        event.Filename,
+			Position:    event.StartPosition,
+			EndPosition: event.Position,
+		},
+	}
+
+	if event.EventType == "QUERY" {
+		w.writeQuery(record, event)
+		return
+	}
+
+	indexes := w.renderer.primaryKeyIndexes(event)
+	step := 1
+	if event.EventType == "UPDATE" {
+		step = 2
+	}
+	for i := 0; i < len(event.Rows); i += step {
+		record.RowID = nil
+		for _, idx := range indexes {
+			if idx < len(event.Rows[i]) && columnLogged(event, i, idx) {
+				if record.RowID == nil {
+					record.RowID = make(map[string]interface{}, len(indexes))
+				}
+				record.RowID[columnName(event.Columns, idx)] = jsonValue(event.Rows[i][idx])
+			}
+		}
+		record.Source.Row = i / step
+		w.encoder.Encode(record)
+	}
+}
+
+// 쿼리 이벤트 (DDL, 권한 변경, 문장 기반 DML)
+func (w *complianceWriter) writeQuery(record complianceRecord, event *config.SQLEvent) {
+	record.Action = "query"
+	if tokens := tokenizeSQL(event.SQL); len(tokens) > 0 && complianceActions[strings.ToLower(tokens[0])] {
+		record.Action = strings.ToLower(tokens[0])
+	}
+	record.Statement = event.SQL
+
+	// GRANT/REVOKE의 TO/FROM 뒤는 테이블이 아니라 계정
+	var tables []string
+	if record.Action != "grant" && record.Action != "revoke" {
+		tables = queryTables(event.Database, event.SQL)
+	}
+	if len(tables) == 0 {
+		tables = []string{event.Database}
+	}
+	for _, table := range tables {
+		record.Object = table
+		w.encoder.Encode(record)
+	}
+}
+
+func (w *complianceWriter) finish() {}
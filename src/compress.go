@@ -0,0 +1,68 @@
+package src
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+
+	"mysqlbinlogo/config"
+)
+
+// 결과 파일 압축 (--compress)
+const (
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+)
+
+func validateCompress(cfg config.Config) error {
+	switch cfg.Compress {
+	case "":
+		return nil
+	case CompressGzip, CompressZstd:
+	default:
+		return fmt.Errorf("지원하지 않는 압축 형식: %s (gzip, zstd 중 선택)", cfg.Compress)
+	}
+	if cfg.OutputFile == "" {
+		return fmt.Errorf("--compress는 --output이 필요합니다")
+	}
+	if cfg.Format == FormatParquet {
+		// 페이지가 이미 압축되어 있고 파일 전체를 압축하면 Athena 등에서 읽을 수 없음
+		return fmt.Errorf("--format parquet은 --compress와 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+// 압축된 결과 파일 (닫으면 압축 스트림을 마무리한 뒤 파일을 닫음)
+// --append로 이어 쓰면 새 gzip 멤버/zstd 프레임이 붙으며 gzip -d, zstd -d는 이어진 스트림을 하나로 풀어 줌
+type compressedFile struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (f *compressedFile) Close() error {
+	err := f.WriteCloser.Close()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// --compress에 따라 결과 파일을 압축 스트림으로 감쌈
+func compressOutput(cfg config.Config, file *os.File) (io.WriteCloser, error) {
+	switch cfg.Compress {
+	case CompressGzip:
+		return &compressedFile{WriteCloser: gzip.NewWriter(file), file: file}, nil
+	case CompressZstd:
+		encoder, err := zstd.NewWriter(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &compressedFile{WriteCloser: encoder, file: file}, nil
+	default:
+		return file, nil
+	}
+}
@@ -1,6 +1,7 @@
 package src
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -8,7 +9,6 @@ import (
 
 	"mysqlbinlogo/config"
 
-	"github.com/go-mysql-org/go-mysql/replication"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,12 +27,12 @@ type FileSearchResult struct {
 }
 
 // 병렬 파일 검색
-func (btf *BinlogTimeFinder) FindTargetFilesConcurrent(files []config.BinlogFile) ([]config.BinlogFile, error) {
+func (btf *BinlogTimeFinder) FindTargetFilesConcurrent(ctx context.Context, files []config.BinlogFile) ([]config.BinlogFile, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("binary log 파일이 없습니다")
 	}
 
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("총 %d개의 binary log 파일 중 시간 범위에 맞는 파일 검색 중... (워커: %d개)\n", len(files), btf.config.Workers)
 	}
 
@@ -58,7 +58,7 @@ func (btf *BinlogTimeFinder) FindTargetFilesConcurrent(files []config.BinlogFile
 	var wg sync.WaitGroup
 	for i := 0; i < workerCount; i++ {
 		wg.Add(1)
-		go btf.searchWorkerDynamic(jobs, results, &wg, i+1)
+		go btf.searchWorkerDynamic(ctx, jobs, results, &wg, i+1)
 	}
 
 	// 작업 분배
@@ -76,52 +76,24 @@ func (btf *BinlogTimeFinder) FindTargetFilesConcurrent(files []config.BinlogFile
 	}()
 
 	// 결과 처리
-	return btf.processSearchResults(results, len(files))
+	targetFiles, err := btf.processSearchResults(results, files[len(files)-1].Name)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return targetFiles, err
 }
 
 // 동적 파일 검색 워커 - 작업이 끝난 워커가 남은 파일들을 처리
-func (btf *BinlogTimeFinder) searchWorkerDynamic(jobs <-chan FileSearchJob, results chan<- FileSearchResult, wg *sync.WaitGroup, workerId int) {
+func (btf *BinlogTimeFinder) searchWorkerDynamic(ctx context.Context, jobs <-chan FileSearchJob, results chan<- FileSearchResult, wg *sync.WaitGroup, workerId int) {
 	defer wg.Done()
 
 	for job := range jobs {
-		if btf.config.Verbose {
+		if btf.config.Verbose >= config.VerboseProbe {
 			logrus.Debugf("워커 %d에서 파일 %d 검사 중: %s\n", workerId, job.Index+1, job.File.Name)
 		}
 
-		// 각 파일마다 새로운 syncer 생성 (독립적인 연결 보장)
-		cfg := replication.BinlogSyncerConfig{
-			ServerID: uint32(100 + workerId), // 워커별로 다른 ServerID 사용
-			Flavor:   "mysql",
-			Host:     btf.config.Host,
-			Port:     uint16(btf.config.Port),
-			User:     btf.config.User,
-			Password: btf.config.Password,
-			Logger:   &config.NullLogger{},
-		}
-		syncer := replication.NewBinlogSyncer(cfg)
-
-		// 재시도 로직으로 안정성 향상
-		var timeRange FileTimeRange
-		var err error
-		maxRetries := 10
-
-		for retry := 0; retry < maxRetries; retry++ {
-			timeRange, err = btf.getFileTimeRangeQuick(syncer, job.File)
-			if err == nil {
-				break
-			}
-
-			if retry < maxRetries-1 {
-				if btf.config.Verbose {
-					logrus.Debugf("워커 %d에서 파일 %s 재시도 중 (%d/%d): %v\n", workerId, job.File.Name, retry+1, maxRetries, err)
-				}
-				// 잠시 대기 후 재시도
-				time.Sleep(100 * time.Millisecond)
-			}
-		}
-
-		// syncer 즉시 닫기 (리소스 정리)
-		//syncer.Close()
+		serverID := uint32(100 + workerId) // 워커별로 다른 ServerID 사용
+		timeRange, err := btf.probeFile(ctx, serverID, job.File)
 
 		result := FileSearchResult{
 			File:      job.File,
@@ -134,14 +106,14 @@ func (btf *BinlogTimeFinder) searchWorkerDynamic(jobs <-chan FileSearchJob, resu
 	}
 }
 
-// 검색 결과 처리 및 필터링
-func (btf *BinlogTimeFinder) processSearchResults(results <-chan FileSearchResult, totalFiles int) ([]config.BinlogFile, error) {
+// 검색 결과 처리 및 필터링 (activeFile: 현재 기록 중인 마지막 파일)
+func (btf *BinlogTimeFinder) processSearchResults(results <-chan FileSearchResult, activeFile string) ([]config.BinlogFile, error) {
 	var allResults []FileSearchResult
 
 	// 모든 결과 수집
 	for result := range results {
 		if result.Error != nil {
-			if btf.config.Verbose {
+			if btf.config.Verbose >= config.VerboseProbe {
 				logrus.Debugf("파일 %s 시간 범위 확인 실패: %v (스킵)\n", result.File.Name, result.Error)
 			}
 			continue
@@ -149,7 +121,7 @@ func (btf *BinlogTimeFinder) processSearchResults(results <-chan FileSearchResul
 		allResults = append(allResults, result)
 	}
 
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("성공적으로 시간 범위를 확인한 파일: %d개\n", len(allResults))
 	}
 
@@ -161,35 +133,28 @@ func (btf *BinlogTimeFinder) processSearchResults(results <-chan FileSearchResul
 	// 시간 범위 확인 (모든 파일 처리)
 	var targetFiles []config.BinlogFile
 
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("검색 시간 범위: %s ~ %s\n",
 			btf.config.StartTime.Format("2006-01-02 15:04:05"),
 			btf.config.EndTime.Format("2006-01-02 15:04:05"))
 	}
 
-	for _, result := range allResults {
+	for i, result := range allResults {
 		timeRange := result.TimeRange
 
-		if btf.config.Verbose {
-			logrus.Debugf("파일 %s: %s ~ %s\n", result.File.Name,
-				timeRange.StartTime.Format("2006-01-02 15:04:05"),
-				timeRange.EndTime.Format("2006-01-02 15:04:05"))
+		// 끝 시간은 다음 파일의 시작 시간 (현재 기록 중인 파일이면 현재 시각)
+		if i+1 < len(allResults) {
+			timeRange.EndTime = allResults[i+1].TimeRange.StartTime
+		} else if result.File.Name == activeFile {
+			timeRange.EndTime = time.Now().UTC()
 		}
 
-		// 시간 범위 확인
-		if btf.isFileInTimeRange(timeRange) {
-			targetFiles = append(targetFiles, result.File)
-			if btf.config.Verbose {
-				logrus.Debugf("파일 %s이 시간 범위에 포함됨\n", result.File.Name)
-			}
-		} else {
-			if btf.config.Verbose {
-				logrus.Debugf("파일 %s은 시간 범위 밖 (skip)\n", result.File.Name)
-			}
+		if btf.checkFile(timeRange) {
+			targetFiles = append(targetFiles, withTimeRange(result.File, timeRange))
 		}
 	}
 
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("최종 선별된 파일: %d개\n", len(targetFiles))
 		for i, file := range targetFiles {
 			logrus.Debugf("  %d. %s\n", i+1, file.Name)
@@ -200,18 +165,18 @@ func (btf *BinlogTimeFinder) processSearchResults(results <-chan FileSearchResul
 }
 
 // 병렬 처리 버전의 public 메서드
-func (btf *BinlogTimeFinder) FindTargetFilesParallel(files []config.BinlogFile) ([]config.BinlogFile, error) {
+func (btf *BinlogTimeFinder) FindTargetFilesParallel(ctx context.Context, files []config.BinlogFile) ([]config.BinlogFile, error) {
 	// 워커 수가 1이면 순차 처리
 	if btf.config.Workers <= 1 {
-		if btf.config.Verbose {
+		if btf.config.Verbose >= config.VerboseFiles {
 			logrus.Debugf("워커 수가 1이므로 순차 처리 모드로 실행합니다.\n")
 		}
-		return btf.FindTargetFilesEfficient(files)
+		return btf.FindTargetFilesEfficient(ctx, files)
 	}
 
 	// 병렬 처리
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("병렬 처리 모드로 실행합니다. (워커: %d개)\n", btf.config.Workers)
 	}
-	return btf.FindTargetFilesConcurrent(files)
+	return btf.FindTargetFilesConcurrent(ctx, files)
 }
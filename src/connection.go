@@ -0,0 +1,259 @@
+package src
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/go-sql-driver/mysql"
+)
+
+// SSL 모드 (mysql 클라이언트의 --ssl-mode와 동일한 의미)
+const (
+	SSLModeDisabled       = "DISABLED"
+	SSLModeRequired       = "REQUIRED"        // 암호화만, 인증서 검증 안 함
+	SSLModeVerifyCA       = "VERIFY_CA"       // CA 검증, 호스트 이름 검증 안 함
+	SSLModeVerifyIdentity = "VERIFY_IDENTITY" // CA와 호스트 이름 모두 검증
+)
+
+// 인증 플러그인 이름
+const (
+	AuthNativePassword      = "mysql_native_password"
+	AuthCachingSha2Password = "caching_sha2_password"
+	AuthSha256Password      = "sha256_password"
+	AuthClearPassword       = "mysql_clear_password"
+)
+
+// --server-public-key로 등록하는 공개키 이름 (go-sql-driver 레지스트리)
+const serverPubKeyName = "mysqlbinlogo"
+
+// MySQL 서버에 연결 (TLS 설정은 Config에 보관하여 이후 생성되는 syncer에서도 사용)
+// syncer는 인증 방식을 제한할 수 없으므로, 같은 계정으로 이 연결이 먼저 성공해야 스트리밍을 시작함
+func (ba *BinlogAnalyzer) connect() error {
+	tlsConfig, err := newTLSConfig(ba.Config)
+	if err != nil {
+		return err
+	}
+	ba.Config.TLS = tlsConfig
+
+	driverCfg, err := newDriverConfig(ba.Config)
+	if err != nil {
+		return err
+	}
+	connector, err := mysql.NewConnector(driverCfg)
+	if err != nil {
+		return err
+	}
+	ba.conn = sql.OpenDB(connector)
+
+	return ba.conn.Ping()
+}
+
+// go-sql-driver 연결 설정 (인증 플러그인 옵션 반영)
+func newDriverConfig(cfg config.Config) (*mysql.Config, error) {
+	driverCfg := mysql.NewConfig()
+	driverCfg.User = cfg.User
+	driverCfg.Passwd = cfg.Password
+	driverCfg.Net = "tcp"
+	driverCfg.Addr = fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	driverCfg.TLS = cfg.TLS
+	driverCfg.Timeout = cfg.ConnectTimeout
+	driverCfg.ReadTimeout = cfg.ReadTimeout
+	driverCfg.DialFunc = newDialer(cfg).DialContext
+	driverCfg.AllowCleartextPasswords = cfg.AllowCleartext
+	if err := driverCfg.Apply(mysql.EnableCompression(cfg.CompressProtocol)); err != nil {
+		return nil, err
+	}
+
+	switch cfg.AuthPlugin {
+	case "", AuthNativePassword:
+	case AuthCachingSha2Password, AuthSha256Password:
+		// SHA-2 계열을 지정하면 더 약한 mysql_native_password로의 전환을 거부
+		driverCfg.AllowNativePasswords = false
+	case AuthClearPassword:
+		// 비밀번호가 평문으로 전송되므로 TLS 또는 명시적인 허용 필요
+		if cfg.TLS == nil && !cfg.AllowCleartext {
+			return nil, fmt.Errorf("%s는 TLS 연결(--ssl-*) 또는 --allow-cleartext가 필요합니다", AuthClearPassword)
+		}
+		driverCfg.AllowCleartextPasswords = true
+	default:
+		return nil, fmt.Errorf("지원하지 않는 --auth-plugin 값: %s (%s, %s, %s, %s)", cfg.AuthPlugin,
+			AuthNativePassword, AuthCachingSha2Password, AuthSha256Password, AuthClearPassword)
+	}
+
+	// TLS 없이 SHA-2 계열 인증을 할 때 사용할 공개키 (없으면 서버에서 받아옴)
+	if cfg.ServerPublicKey != "" {
+		pubKey, err := loadPublicKey(cfg.ServerPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		mysql.RegisterServerPubKey(serverPubKeyName, pubKey)
+		driverCfg.ServerPubKey = serverPubKeyName
+	}
+
+	return driverCfg, nil
+}
+
+// PEM 형식의 RSA 공개키 파일 읽기
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("공개키 파일 읽기 실패: %v", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("공개키 파일에서 PEM 블록을 찾을 수 없습니다: %s", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("공개키 파싱 실패: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("RSA 공개키가 아닙니다: %s", path)
+	}
+	return rsaKey, nil
+}
+
+// binlog syncer 설정 (모든 syncer가 같은 연결 옵션을 사용하도록 한 곳에서 생성)
+func newSyncerConfig(cfg config.Config, serverID uint32) replication.BinlogSyncerConfig {
+	return replication.BinlogSyncerConfig{
+		ServerID:        serverID,
+		Flavor:          "mysql",
+		Host:            cfg.Host,
+		Port:            uint16(cfg.Port),
+		User:            cfg.User,
+		Password:        cfg.Password,
+		TLSConfig:       cfg.TLS,
+		ReadTimeout:     cfg.ReadTimeout,
+		HeartbeatPeriod: heartbeatPeriod(cfg),
+		Dialer:          newDialer(cfg).DialContext,
+		Logger:          config.NewNullLogger(),
+	}
+}
+
+// 연결 타임아웃과 TCP keepalive가 적용된 dialer (keepalive 0이면 사용 안 함)
+func newDialer(cfg config.Config) *net.Dialer {
+	dialer := &net.Dialer{
+		Timeout:   cfg.ConnectTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+	if cfg.KeepAlive == 0 {
+		dialer.KeepAlive = -1
+	}
+	return dialer
+}
+
+// binlog 덤프 연결의 heartbeat 주기
+// 새 이벤트가 없는 동안 읽기 타임아웃으로 연결이 끊기지 않도록 타임아웃의 절반으로 설정
+func heartbeatPeriod(cfg config.Config) time.Duration {
+	if cfg.ReadTimeout <= 0 {
+		return 0
+	}
+	return cfg.ReadTimeout / 2
+}
+
+// SSL 옵션으로부터 TLS 설정 생성 (SSL을 사용하지 않으면 nil)
+func newTLSConfig(cfg config.Config) (*tls.Config, error) {
+	mode := strings.ToUpper(cfg.SSLMode)
+	if mode == "" {
+		// 모드를 지정하지 않으면 인증서 옵션에 따라 결정
+		switch {
+		case cfg.SSLCA != "":
+			mode = SSLModeVerifyCA
+		case cfg.SSLCert != "" || cfg.SSLKey != "":
+			mode = SSLModeRequired
+		default:
+			mode = SSLModeDisabled
+		}
+	}
+
+	switch mode {
+	case SSLModeDisabled:
+		if cfg.SSLCA != "" || cfg.SSLCert != "" || cfg.SSLKey != "" {
+			return nil, fmt.Errorf("--ssl-mode=DISABLED에서는 --ssl-ca/--ssl-cert/--ssl-key를 사용할 수 없습니다")
+		}
+		return nil, nil
+	case SSLModeRequired, SSLModeVerifyCA, SSLModeVerifyIdentity:
+	default:
+		return nil, fmt.Errorf("지원하지 않는 --ssl-mode 값: %s", cfg.SSLMode)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: cfg.Host,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// 클라이언트 인증서 (REQUIRE X509 계정용)
+	if cfg.SSLCert != "" || cfg.SSLKey != "" {
+		if cfg.SSLCert == "" || cfg.SSLKey == "" {
+			return nil, fmt.Errorf("--ssl-cert와 --ssl-key는 함께 지정해야 합니다")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.SSLCert, cfg.SSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("클라이언트 인증서 로드 실패: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.SSLCA != "" {
+		pem, err := os.ReadFile(cfg.SSLCA)
+		if err != nil {
+			return nil, fmt.Errorf("CA 파일 읽기 실패: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 파일에서 인증서를 찾을 수 없습니다: %s", cfg.SSLCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch mode {
+	case SSLModeRequired:
+		tlsConfig.InsecureSkipVerify = true
+	case SSLModeVerifyCA:
+		// 호스트 이름을 제외하고 인증서 체인만 검증
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyCertificateChain(tlsConfig.RootCAs)
+	}
+
+	return tlsConfig, nil
+}
+
+// 서버 인증서 체인 검증 (호스트 이름 검증 없음)
+func verifyCertificateChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("서버 인증서가 없습니다")
+		}
+
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
@@ -0,0 +1,133 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 결과가 불완전할 수 있는 이유를 경고로 남김 (텍스트 결과 헤더에도 기록)
+func (ba *BinlogAnalyzer) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(T(format), args...)
+	ba.warnings = append(ba.warnings, message)
+	logrus.Warn(message)
+}
+
+// 파일을 끝까지 읽지 못했으면 (연결 끊김, 60초 제한) 읽은 만큼만 결과에 담겼음을 경고
+func (ba *BinlogAnalyzer) warnPartialRead(err error) bool {
+	var partial *partialReadError
+	if !errors.As(err, &partial) {
+		return false
+	}
+	ba.incomplete = true
+	ba.warn("파일 %s를 %d 위치까지만 읽어 이후 이벤트가 결과에서 빠짐: %v", partial.file, partial.position, partial.err)
+	return true
+}
+
+// ErrRangeNotCovered 요청한 시작 시간 이전의 binary log가 이미 purge되어 결과가 구간 전체를 담지 못함
+var ErrRangeNotCovered error = localizedError("요청한 시작 시간이 남아 있는 가장 오래된 binary log보다 이릅니다")
+
+// 가장 오래된 binary log의 첫 이벤트가 시작 시간보다 늦으면 경고
+// "이벤트 없음"을 "아무 일도 없었음"으로 오해하지 않도록 결과와 종료 코드로 알림
+func (ba *BinlogAnalyzer) checkOldestBinlog(ctx context.Context, finder *BinlogTimeFinder, files []config.BinlogFile) {
+	if len(files) == 0 {
+		return
+	}
+	oldest := files[0]
+	for _, file := range files[1:] {
+		if ba.extractFileNumber(file.Name) < ba.extractFileNumber(oldest.Name) {
+			oldest = file
+		}
+	}
+
+	timeRange, err := finder.probeFile(ctx, 100, oldest)
+	if err != nil || timeRange.StartTime.IsZero() || !timeRange.StartTime.After(ba.Config.StartTime) {
+		return
+	}
+
+	ba.earliest = timeRange.StartTime
+	ba.warn("요청한 시작 시간(%s)이 가장 오래된 binary log %s의 첫 이벤트(%s)보다 이릅니다. 그 이전 이벤트는 purge되어 결과에 없습니다",
+		ba.Config.StartTime.Format("2006-01-02 15:04:05"), oldest.Name, ba.earliest.Format("2006-01-02 15:04:05"))
+}
+
+// 구간 앞부분이 남아 있지 않으면 ErrRangeNotCovered
+func (ba *BinlogAnalyzer) coverageError() error {
+	if ba.earliest.IsZero() {
+		return nil
+	}
+	earliest := ba.earliest.Format("2006-01-02 15:04:05")
+	if ba.earliest.After(ba.Config.EndTime) {
+		fmt.Fprintf(ba.messageOutput(), T("\n!! 경고: 요청한 구간의 binary log가 모두 purge되었습니다 (가장 오래된 이벤트: %s). 결과가 없는 것은 변경이 없었다는 뜻이 아닙니다.\n"), earliest)
+	} else {
+		fmt.Fprintf(ba.messageOutput(), T("\n!! 경고: %s 이전의 binary log가 없어 결과는 %s ~ %s 구간만 담고 있습니다.\n"),
+			earliest, earliest, ba.Config.EndTime.Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Errorf(T("%w (가장 오래된 이벤트: %s UTC)"), ErrRangeNotCovered, earliest)
+}
+
+// 분석 대상 파일이 끊김 없이 이어지는지 확인
+// 파일 번호가 빠진 곳(purge 또는 수동 삭제)과 시간 확인에 실패해 대상에서 빠진 파일을 경고
+func (ba *BinlogAnalyzer) checkContinuity(files, targets []config.BinlogFile) {
+	if len(targets) == 0 {
+		return
+	}
+
+	sorted := make([]config.BinlogFile, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return ba.extractFileNumber(sorted[i].Name) < ba.extractFileNumber(sorted[j].Name)
+	})
+
+	included := make(map[string]bool, len(targets))
+	for _, file := range targets {
+		included[file.Name] = true
+	}
+	first, last := -1, -1
+	for i, file := range sorted {
+		if included[file.Name] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return
+	}
+
+	// 첫 대상 파일 바로 앞의 빈 곳에도 구간 시작 부분의 이벤트가 있었을 수 있음
+	for i := max(first-1, 0); i < last; i++ {
+		current, next := sorted[i], sorted[i+1]
+		from, to := ba.extractFileNumber(current.Name)+1, ba.extractFileNumber(next.Name)-1
+		if from <= to && to < 999999 {
+			ba.warn("binary log 파일이 빠져 있습니다: %s (%s ~ %s 사이, purge 또는 삭제된 것으로 보임)",
+				binlogFileRange(next.Name, from, to), current.Name, next.Name)
+		}
+	}
+
+	// 대상 파일 사이의 파일이 대상에서 빠졌다면 시작 시간 확인에 실패한 것
+	for i := first + 1; i < last; i++ {
+		if !included[sorted[i].Name] {
+			ba.warn("binary log 파일 %s의 시간 범위를 확인하지 못해 분석에서 빠졌습니다", sorted[i].Name)
+		}
+	}
+}
+
+// 빠진 파일 번호 범위를 파일 이름으로 표시 (예: mysql-bin.000017 ~ mysql-bin.000019)
+func binlogFileRange(sample string, from, to int) string {
+	dot := strings.LastIndex(sample, ".")
+	base, width := sample[:dot+1], len(sample)-dot-1
+	name := func(n int) string {
+		return fmt.Sprintf("%s%0*d", base, width, n)
+	}
+	if from == to {
+		return name(from)
+	}
+	return name(from) + " ~ " + name(to)
+}
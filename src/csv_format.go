@@ -0,0 +1,129 @@
+package src
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// CSV 컬럼 (이름은 JSON 출력의 필드 이름과 같음)
+type csvColumn struct {
+	name  string
+	value func(w *csvWriter, event *config.SQLEvent) string
+}
+
+var csvColumns = []csvColumn{
+	{"timestamp", func(_ *csvWriter, e *config.SQLEvent) string { return e.Timestamp.Format("2006-01-02 15:04:05.999999") }},
+	{"event_type", func(_ *csvWriter, e *config.SQLEvent) string { return e.EventType }},
+	{"database", func(_ *csvWriter, e *config.SQLEvent) string { return e.Database }},
+	{"table", func(_ *csvWriter, e *config.SQLEvent) string { return e.Table }},
+	{"row_count", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.Itoa(e.RowCount) }},
+	{"primary_key", func(w *csvWriter, e *config.SQLEvent) string { return w.renderer.formatPrimaryKeys(e) }},
+	{"transaction", func(_ *csvWriter, e *config.SQLEvent) string { return e.Transaction }},
+	{"server_id", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.FormatUint(uint64(e.ServerId), 10) }},
+	{"filename", func(_ *csvWriter, e *config.SQLEvent) string { return e.Filename }},
+	{"start_position", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.FormatUint(uint64(e.StartPosition), 10) }},
+	{"position", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.FormatUint(uint64(e.Position), 10) }},
+	{"event_size", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.FormatUint(uint64(e.EventSize), 10) }},
+	{"exec_time", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.FormatUint(uint64(e.ExecTime), 10) }},
+	{"error_code", func(_ *csvWriter, e *config.SQLEvent) string { return strconv.FormatUint(uint64(e.ErrorCode), 10) }},
+	{"original_sql", func(_ *csvWriter, e *config.SQLEvent) string { return strings.TrimSpace(e.OriginalSQL) }},
+	{"sql", func(_ *csvWriter, e *config.SQLEvent) string { return e.SQL }},
+	{"captured_at", func(_ *csvWriter, e *config.SQLEvent) string {
+		if e.CapturedAt == nil {
+			return ""
+		}
+		return e.CapturedAt.Format("2006-01-02 15:04:05.999999")
+	}},
+}
+
+// --csv-columns를 지정하지 않으면 출력할 컬럼
+var defaultCSVColumns = []string{"timestamp", "event_type", "database", "table", "row_count", "primary_key", "transaction", "filename", "start_position", "position", "sql"}
+
+func findCSVColumn(name string) *csvColumn {
+	for i := range csvColumns {
+		if csvColumns[i].name == name {
+			return &csvColumns[i]
+		}
+	}
+	return nil
+}
+
+func validateCSVColumns(cfg config.Config) error {
+	if len(cfg.CSVColumns) == 0 {
+		return nil
+	}
+	if cfg.Format != FormatCSV {
+		return fmt.Errorf("--csv-columns는 --format csv에서만 사용할 수 있습니다")
+	}
+	seen := make(map[string]bool)
+	for _, name := range cfg.CSVColumns {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if findCSVColumn(name) == nil {
+			names := make([]string, len(csvColumns))
+			for i, column := range csvColumns {
+				names[i] = column.name
+			}
+			return fmt.Errorf("알 수 없는 CSV 컬럼: %s (%s 중 선택)", name, strings.Join(names, ", "))
+		}
+		if seen[name] {
+			return fmt.Errorf("CSV 컬럼이 중복되었습니다: %s", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// CSV 출력기 (--format csv)
+// 첫 행은 컬럼 이름, 이후 이벤트마다 한 행 (RFC 4180, 줄바꿈이 있는 SQL은 따옴표로 감쌈)
+type csvWriter struct {
+	writer   *csv.Writer
+	columns  []*csvColumn
+	renderer *SQLExtractor
+	started  bool
+}
+
+func newCSVWriter(output io.Writer, names []string, renderer *SQLExtractor) *csvWriter {
+	if len(names) == 0 {
+		names = defaultCSVColumns
+	}
+	w := &csvWriter{writer: csv.NewWriter(output), renderer: renderer}
+	for _, name := range names {
+		w.columns = append(w.columns, findCSVColumn(strings.ToLower(strings.TrimSpace(name))))
+	}
+	return w
+}
+
+func (w *csvWriter) writeHeader() {
+	header := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		header[i] = column.name
+	}
+	w.writer.Write(header)
+	w.started = true
+}
+
+func (w *csvWriter) writeEvent(event *config.SQLEvent) {
+	if !w.started {
+		w.writeHeader()
+	}
+	record := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		record[i] = column.value(w, event)
+	}
+	w.writer.Write(record)
+	// --follow에서도 이벤트가 바로 보이도록 행마다 내보냄
+	w.writer.Flush()
+}
+
+// 이벤트가 없어도 컬럼 이름 행은 출력
+func (w *csvWriter) finish() {
+	if !w.started {
+		w.writeHeader()
+	}
+	w.writer.Flush()
+}
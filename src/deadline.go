@@ -0,0 +1,43 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mysqlbinlogo/config"
+)
+
+// ErrDeadlineExceeded --deadline 안에 분석을 마치지 못해 결과가 그때까지 읽은 이벤트만 담고 있음
+var ErrDeadlineExceeded error = localizedError("--deadline 안에 분석을 마치지 못했습니다")
+
+// IsIncomplete 결과는 출력했지만 일부가 빠진 실행인지 (구간 앞부분 purge, --deadline 초과)
+func IsIncomplete(err error) bool {
+	return errors.Is(err, ErrRangeNotCovered) || errors.Is(err, ErrDeadlineExceeded)
+}
+
+// --deadline이 지났는지 (사용자 취소와 구분)
+func deadlineExceeded(ctx, work context.Context) bool {
+	return ctx.Err() == nil && errors.Is(work.Err(), context.DeadlineExceeded)
+}
+
+// --deadline으로 끝까지 읽지 못한 파일 기록 (읽은 이벤트까지는 결과에 포함)
+func (ba *BinlogAnalyzer) deadlineCut(file config.BinlogFile, events []config.SQLEvent, started bool) []config.SQLEvent {
+	ba.cutFiles = append(ba.cutFiles, file.Name)
+	if !started {
+		return nil
+	}
+	events = ba.filter.Filter(events)
+	ba.run.filePartial(file.Name, len(events))
+	return events
+}
+
+// 끝까지 읽지 못한 파일이 있으면 요약에 알리고 ErrDeadlineExceeded
+func (ba *BinlogAnalyzer) deadlineError() error {
+	if len(ba.cutFiles) == 0 {
+		return nil
+	}
+	fmt.Fprintf(ba.messageOutput(), T("\n!! 경고: --deadline %s 안에 분석을 마치지 못해 파일 %d개를 끝까지 읽지 못했습니다 (%s부터). 결과는 그때까지 읽은 이벤트만 담고 있습니다.\n"),
+		ba.Config.Deadline, len(ba.cutFiles), ba.cutFiles[0])
+	return fmt.Errorf(T("%w (끝까지 읽지 못한 파일 %d개)"), ErrDeadlineExceeded, len(ba.cutFiles))
+}
@@ -0,0 +1,142 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// Debezium 변경 envelope (schemas.enable=false 형태의 value)
+type debeziumEnvelope struct {
+	Before      map[string]interface{} `json:"before"`
+	After       map[string]interface{} `json:"after"`
+	Source      debeziumSource         `json:"source"`
+	Op          string                 `json:"op"` // c, u, d
+	TsMs        int64                  `json:"ts_ms"`
+	Transaction interface{}            `json:"transaction"` // 트랜잭션 메타데이터 없음 (null)
+}
+
+// Debezium MySQL 커넥터의 source 블록
+type debeziumSource struct {
+	Version   string  `json:"version"`
+	Connector string  `json:"connector"`
+	Name      string  `json:"name"` // 논리 서버 이름 (접속 호스트)
+	TsMs      int64   `json:"ts_ms"`
+	Snapshot  string  `json:"snapshot"`
+	DB        string  `json:"db"`
+	Sequence  *string `json:"sequence"`
+	Table     string  `json:"table"`
+	ServerID  uint32  `json:"server_id"`
+	GTID      *string `json:"gtid"`
+	File      string  `json:"file"`
+	Pos       uint32  `json:"pos"`
+	Row       int     `json:"row"` // 이벤트 안에서의 행 번호
+	Thread    *int64  `json:"thread"`
+	Query     *string `json:"query"` // Rows_query 이벤트의 원본 SQL
+}
+
+var debeziumOps = map[string]string{
+	"INSERT": "c",
+	"UPDATE": "u",
+	"DELETE": "d",
+}
+
+// Debezium 형식 출력기 (row의 행마다 envelope 한 줄, 쿼리 이벤트는 제외)
+type debeziumWriter struct {
+	encoder *json.Encoder
+	name    string
+	skipped int // 제외한 쿼리 이벤트 수
+}
+
+func newDebeziumWriter(output io.Writer, name string) *debeziumWriter {
+	return &debeziumWriter{encoder: json.NewEncoder(output), name: name}
+}
+
+func (w *debeziumWriter) writeEvent(event *config.SQLEvent) {
+	op, ok := debeziumOps[event.EventType]
+	if !ok {
+		w.skipped++
+		return
+	}
+
+	source := debeziumSource{
+		Version:   "mysqlbinlogo",
+		Connector: "mysql",
+		Name:      w.name,
+		TsMs:      event.Timestamp.UnixMilli(),
+		Snapshot:  "false",
+		DB:        event.Database,
+		Table:     event.Table,
+		ServerID:  event.ServerId,
+		File:      event.Filename,
+		Pos:       event.Position,
+	}
+	if event.OriginalSQL != "" {
+		query := event.OriginalSQL
+		source.Query = &query
+	}
+
+	// UPDATE는 before/after 쌍
+	step := 1
+	if event.EventType == "UPDATE" {
+		step = 2
+	}
+	for i, row := 0, 0; i < len(event.Rows); i, row = i+step, row+1 {
+		envelope := debeziumEnvelope{Source: source, Op: op, TsMs: time.Now().UnixMilli()}
+		envelope.Source.Row = row
+
+		switch event.EventType {
+		case "INSERT":
+			envelope.After = jsonRow(event, i)
+		case "DELETE":
+			envelope.Before = jsonRow(event, i)
+		case "UPDATE":
+			envelope.Before = jsonRow(event, i)
+			if i+1 < len(event.Rows) {
+				envelope.After = jsonRow(event, i+1)
+			}
+		}
+		w.encoder.Encode(envelope)
+	}
+}
+
+func (w *debeziumWriter) finish() {
+	if w.skipped > 0 {
+		logrus.Infof("debezium 형식: row 변경이 아닌 쿼리 이벤트 %d개 제외", w.skipped)
+	}
+}
+
+// image번째 행 이미지를 컬럼 이름 → 값 맵으로 변환 (기록되지 않은 컬럼은 null이 아니라 키 없음)
+func jsonRow(event *config.SQLEvent, image int) map[string]interface{} {
+	values := event.Rows[image]
+	row := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		if columnLogged(event, image, i) {
+			row[columnName(event.Columns, i)] = jsonValue(value)
+		}
+	}
+	return row
+}
+
+// JSON으로 표현할 값 (문자열 바이트는 문자열로, 나머지 바이너리는 base64)
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return v
+	case time.Time:
+		return v.Format("2006-01-02T15:04:05.999999Z07:00")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"mysqlbinlogo/config"
 
 	"github.com/go-mysql-org/go-mysql/mysql"
-	"github.com/go-mysql-org/go-mysql/replication"
 )
 
 // 시간 기반 binary log 파일 찾기
@@ -33,17 +32,17 @@ func NewBinlogTimeFinder(conn *sql.DB, cfg config.Config) *BinlogTimeFinder {
 type FileTimeRange struct {
 	FileName  string
 	Size      int64
-	StartTime time.Time
-	EndTime   time.Time
+	StartTime time.Time // 첫 이벤트 시각
+	EndTime   time.Time // 마지막 이벤트 시각의 상한 (다음 파일의 시작 시간, 알 수 없으면 zero)
 }
 
 // 효율적으로 시간 범위에 해당하는 파일들만 선별
-func (btf *BinlogTimeFinder) FindTargetFilesEfficient(files []config.BinlogFile) ([]config.BinlogFile, error) {
+func (btf *BinlogTimeFinder) FindTargetFilesEfficient(ctx context.Context, files []config.BinlogFile) ([]config.BinlogFile, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("binary log 파일이 없습니다")
 	}
 
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("총 %d개의 binary log 파일 중 시간 범위에 맞는 파일 검색 중...\n", len(files))
 	}
 
@@ -54,64 +53,62 @@ func (btf *BinlogTimeFinder) FindTargetFilesEfficient(files []config.BinlogFile)
 
 	var targetFiles []config.BinlogFile
 
-	// MySQL 복제 설정
-	cfg := replication.BinlogSyncerConfig{
-		ServerID: 100,
-		Flavor:   "mysql",
-		Host:     btf.config.Host,
-		Port:     uint16(btf.config.Port),
-		User:     btf.config.User,
-		Password: btf.config.Password,
-		Logger:   &config.NullLogger{},
-	}
+	// 끝 시간은 다음 파일의 시작 시간으로 정하므로 한 파일씩 늦게 판정
+	var prev *FileTimeRange
+	var prevFile config.BinlogFile
+	activeFile := files[len(files)-1].Name
 
-	// 각 파일의 시간 범위를 빠르게 확인
+	// 각 파일의 시작 시간을 빠르게 확인
 	for i, file := range files {
-		if btf.config.Verbose {
+		if btf.config.Verbose >= config.VerboseProbe {
 			logrus.Debugf("파일 %d/%d 검사 중: %s\n", i+1, len(files), file.Name)
 		}
 
-		// 새로운 syncer로 파일 시간 범위 확인
-		syncer := replication.NewBinlogSyncer(cfg)
-
-		timeRange, err := btf.getFileTimeRangeQuick(syncer, file)
+		// 새로운 연결로 파일 시작 시간 확인
+		timeRange, err := btf.probeFile(ctx, 100, file)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 
 		if err != nil {
-			if btf.config.Verbose {
+			if btf.config.Verbose >= config.VerboseProbe {
 				logrus.Debugf("파일 %s 시간 범위 확인 실패: %v (스킵)\n", file.Name, err)
 			}
 			continue
 		}
 
-		if btf.config.Verbose {
-			logrus.Debugf("파일 %s: %s ~ %s\n", file.Name,
-				timeRange.StartTime.Format("2006-01-02 15:04:05"),
-				timeRange.EndTime.Format("2006-01-02 15:04:05"))
-		}
-
-		// 시간 범위 확인
-		if btf.isFileInTimeRange(timeRange) {
-			targetFiles = append(targetFiles, file)
-			if btf.config.Verbose {
-				logrus.Debugf("파일 %s이 시간 범위에 포함됨\n", file.Name)
-			}
-		} else {
-			if btf.config.Verbose {
-				logrus.Debugf("파일 %s은 시간 범위 밖 (스킵)\n", file.Name)
+		if prev != nil {
+			prev.EndTime = timeRange.StartTime
+			if btf.checkFile(*prev) {
+				targetFiles = append(targetFiles, withTimeRange(prevFile, *prev))
 			}
+			prev = nil
 		}
 
 		// 성능 최적화: 조기 종료 조건 (순방향)
 		// 현재 파일의 시작 시간이 종료 시간보다 늦으면 종료
-		if !timeRange.StartTime.IsZero() && timeRange.StartTime.After(btf.config.EndTime) {
-			if btf.config.Verbose {
+		if !timeRange.StartTime.IsZero() && timeRange.StartTime.After(btf.config.EndTime.Add(btf.config.FileTimeBuffer)) {
+			if btf.config.Verbose >= config.VerboseProbe {
 				logrus.Debugf("파일 %s의 시작 시간이 검색 종료 시간보다 늦으므로 더 이상 확인하지 않음\n", file.Name)
 			}
 			break
 		}
+
+		prev = &timeRange
+		prevFile = file
+	}
+
+	// 마지막으로 확인한 파일 (현재 기록 중인 파일이면 끝 시간은 현재 시각)
+	if prev != nil {
+		if prevFile.Name == activeFile {
+			prev.EndTime = time.Now().UTC()
+		}
+		if btf.checkFile(*prev) {
+			targetFiles = append(targetFiles, withTimeRange(prevFile, *prev))
+		}
 	}
 
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("최종 선별된 파일: %d개\n", len(targetFiles))
 		for _, file := range targetFiles {
 			logrus.Debugf("  - %s\n", file.Name)
@@ -121,153 +118,119 @@ func (btf *BinlogTimeFinder) FindTargetFilesEfficient(files []config.BinlogFile)
 	return targetFiles, nil
 }
 
-// 파일의 시간 범위를 빠르게 확인 (특정 파일만 처리, 다른 파일로 넘어가지 않음)
-func (btf *BinlogTimeFinder) getFileTimeRangeQuick(syncer *replication.BinlogSyncer, file config.BinlogFile) (FileTimeRange, error) {
+// 파일 시작 시간 확인 (실패 시 --probe-retries만큼 --probe-backoff 간격으로 재시도)
+func (btf *BinlogTimeFinder) probeFile(ctx context.Context, serverID uint32, file config.BinlogFile) (FileTimeRange, error) {
+	var timeRange FileTimeRange
+	var err error
+
+	retries := max(btf.config.ProbeRetries, 0)
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if btf.config.Verbose >= config.VerboseProbe {
+				logrus.Debugf("파일 %s 재시도 중 (%d/%d): %v\n", file.Name, attempt, retries, err)
+			}
+			select {
+			case <-time.After(btf.config.ProbeBackoff):
+			case <-ctx.Done():
+				return timeRange, ctx.Err()
+			}
+		}
+
+		timeRange, err = btf.getFileTimeRangeQuick(ctx, serverID, file)
+		if err == nil {
+			return timeRange, nil
+		}
+	}
+	return timeRange, err
+}
+
+// 파일의 시작 시간 확인 (타임스탬프가 있는 첫 이벤트만 읽음)
+// binary log는 순서대로 기록되므로 파일의 마지막 이벤트 시각은 다음 파일의 시작 시간을 넘지 않음
+// 따라서 끝 시간은 호출하는 쪽에서 다음 파일의 시작 시간으로 설정
+func (btf *BinlogTimeFinder) getFileTimeRangeQuick(parent context.Context, serverID uint32, file config.BinlogFile) (FileTimeRange, error) {
 	timeRange := FileTimeRange{
 		FileName: file.Name,
 		Size:     file.Size,
 	}
 
 	// Binary log 스트리밍 시작 - 특정 파일의 시작 위치에서
-	streamer, err := syncer.StartSync(mysql.Position{Name: file.Name, Pos: 4})
+	streamer, err := openBinlogStream(btf.config, serverID, mysql.Position{Name: file.Name, Pos: 4})
 	if err != nil {
 		return timeRange, fmt.Errorf("스트리밍 시작 실패: %v", err)
 	}
+	defer streamer.Close() // 서버에 덤프 연결이 남지 않도록 즉시 정리
 
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	ctx, cancel := context.WithTimeout(parent, 1*time.Second)
 	defer cancel()
 
-	var firstTimestamp, lastTimestamp uint32
-	eventCount := 0
-	maxEvents := 50 // 50개 이벤트로 제한
-
-	// 시작 시간 찾기
-	for eventCount < maxEvents {
-		select {
-		case <-ctx.Done():
-			if firstTimestamp > 0 {
-				timeRange.StartTime = time.Unix(int64(firstTimestamp), 0).UTC()
+	// 서버가 먼저 보내는 가짜 ROTATE 이벤트는 타임스탬프가 0이므로 건너뜀
+	maxEvents := max(btf.config.ProbeEvents, 2)
+	for eventCount := 0; eventCount < maxEvents; eventCount++ {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			if parent.Err() != nil {
+				return timeRange, parent.Err()
 			}
+			// 타임아웃 또는 오류 - 시간 정보 없이 반환 (포함으로 처리됨)
 			return timeRange, nil
-		default:
-			ev, err := streamer.GetEvent(ctx)
-			if err != nil {
-				// 파일 끝에 도달하거나 오류
-				if firstTimestamp > 0 {
-					timeRange.StartTime = time.Unix(int64(firstTimestamp), 0).UTC()
-					timeRange.EndTime = time.Unix(int64(lastTimestamp), 0).UTC()
-				}
-				return timeRange, nil
-			}
-
-			// 현재 이벤트가 다른 파일로 넘어갔는지 확인
-			if ev.Header.LogPos > 0 {
-				// 파일 크기를 초과했거나 다른 파일로 넘어간 경우 종료
-				// LogPos는 이벤트의 끝 위치이므로 파일 크기보다 클 수 있음
-				// 대신 이벤트 크기를 고려하여 판단
-				if ev.Header.LogPos > uint32(file.Size) && ev.Header.EventSize > 0 {
-					// 이벤트 크기가 파일 크기를 초과하는 경우에만 종료
-					if ev.Header.LogPos-ev.Header.EventSize > uint32(file.Size) {
-						if btf.config.Verbose {
-							logrus.Debugf("파일 %s 경계 도달, 처리 종료 (LogPos: %d, EventSize: %d, FileSize: %d)\n",
-								file.Name, ev.Header.LogPos, ev.Header.EventSize, file.Size)
-						}
-						break
-					}
-				}
-			}
-
-			if ev.Header.Timestamp > 0 {
-				if firstTimestamp == 0 {
-					firstTimestamp = ev.Header.Timestamp
-				}
-				lastTimestamp = ev.Header.Timestamp
-			}
-			eventCount++
 		}
-	}
 
-	// 시작 시간 설정
-	if firstTimestamp > 0 {
-		timeRange.StartTime = time.Unix(int64(firstTimestamp), 0).UTC()
+		if ev.Header.Timestamp > 0 {
+			timeRange.StartTime = time.Unix(int64(ev.Header.Timestamp), 0).UTC()
+			return timeRange, nil
+		}
 	}
 
-	// 마지막 이벤트 찾기 (샘플링 방식, 파일 경계 내에서만)
-	sampleCount := 0
-	maxSamples := 50
+	// 시간 정보 없이 반환 (포함으로 처리됨)
+	logrus.Warnf("파일 %s: 처음 %d개 이벤트에서 시작 시간을 찾지 못해 시간 확인 없이 분석 대상에 포함 (--probe-events)", file.Name, maxEvents)
+	return timeRange, nil
+}
 
-	for sampleCount < maxSamples {
-		select {
-		case <-ctx.Done():
-			if lastTimestamp > 0 {
-				timeRange.EndTime = time.Unix(int64(lastTimestamp), 0).UTC()
-			}
-			return timeRange, nil
-		default:
-			ev, err := streamer.GetEvent(ctx)
-			if err != nil {
-				// 파일 끝 도달
-				if lastTimestamp > 0 {
-					timeRange.EndTime = time.Unix(int64(lastTimestamp), 0).UTC()
-				}
-				return timeRange, nil
-			}
+// 확인한 시간 범위를 파일 정보에 기록 (binlog 보존 기간 권장에서 사용)
+func withTimeRange(file config.BinlogFile, timeRange FileTimeRange) config.BinlogFile {
+	file.StartTime = timeRange.StartTime
+	file.EndTime = timeRange.EndTime
+	return file
+}
 
-			// 파일 경계 확인
-			if ev.Header.LogPos > uint32(file.Size) && ev.Header.EventSize > 0 {
-				// 이벤트 크기가 파일 크기를 초과하는 경우에만 종료
-				if ev.Header.LogPos-ev.Header.EventSize > uint32(file.Size) {
-					if btf.config.Verbose {
-						logrus.Debugf("파일 %s 경계 도달, 샘플링 종료 (LogPos: %d, EventSize: %d, FileSize: %d)\n",
-							file.Name, ev.Header.LogPos, ev.Header.EventSize, file.Size)
-					}
-					break
-				}
-			}
+// 시간 범위 판정 결과를 로그로 남기고 반환
+func (btf *BinlogTimeFinder) checkFile(timeRange FileTimeRange) bool {
+	if btf.config.Verbose >= config.VerboseFiles {
+		logrus.Debugf("파일 %s: %s ~ %s\n", timeRange.FileName,
+			timeRange.StartTime.Format("2006-01-02 15:04:05"),
+			timeRange.EndTime.Format("2006-01-02 15:04:05"))
+	}
 
-			if ev.Header.Timestamp > 0 {
-				lastTimestamp = ev.Header.Timestamp
-				sampleCount++
-			}
+	if btf.isFileInTimeRange(timeRange) {
+		if btf.config.Verbose >= config.VerboseFiles {
+			logrus.Debugf("파일 %s이 시간 범위에 포함됨\n", timeRange.FileName)
 		}
+		return true
 	}
 
-	// 마지막 타임스탬프 설정
-	if lastTimestamp > 0 {
-		timeRange.EndTime = time.Unix(int64(lastTimestamp), 0).UTC()
+	if btf.config.Verbose >= config.VerboseFiles {
+		logrus.Debugf("파일 %s은 시간 범위 밖 (스킵)\n", timeRange.FileName)
 	}
-
-	return timeRange, nil
+	return false
 }
 
-// 파일이 시간 범위에 포함되는지 확인
+// 파일이 시간 범위에 포함되는지 확인 (--file-time-buffer만큼 검색 범위를 앞뒤로 확장)
 func (btf *BinlogTimeFinder) isFileInTimeRange(fileRange FileTimeRange) bool {
 	// 파일 시간 정보가 없으면 일단 포함 (안전을 위해)
 	if fileRange.StartTime.IsZero() && fileRange.EndTime.IsZero() {
-		if btf.config.Verbose {
+		if btf.config.Verbose >= config.VerboseFiles {
 			logrus.Debugf("파일 %s: 시간 정보 없음, 포함으로 처리\n", fileRange.FileName)
 		}
 		return true
 	}
 
-	// 시간 범위가 매우 넓은 경우 (24시간 이상) 일단 포함
-	timeDiff := fileRange.EndTime.Sub(fileRange.StartTime)
-	if timeDiff > 24*time.Hour {
-		if btf.config.Verbose {
-			logrus.Debugf("파일 %s: 시간 범위가 넓음 (%.2f시간), 포함으로 처리\n",
-				fileRange.FileName, timeDiff.Hours())
-		}
-		return true
-	}
-
-	// 버퍼 시간 추가 (6시간 전후로 확장)
-	bufferTime := 6 * time.Hour
-	searchStartTime := btf.config.StartTime.Add(-bufferTime)
-	searchEndTime := btf.config.EndTime.Add(bufferTime)
+	// 파일 시간은 이벤트 헤더의 초 단위 시각이므로 시작 시간의 소수점 이하는 버림
+	searchStartTime := btf.config.StartTime.Add(-btf.config.FileTimeBuffer).Truncate(time.Second)
+	searchEndTime := btf.config.EndTime.Add(btf.config.FileTimeBuffer)
 
 	// 파일의 끝 시간이 검색 시작 시간보다 이르면 제외
 	if !fileRange.EndTime.IsZero() && fileRange.EndTime.Before(searchStartTime) {
-		if btf.config.Verbose {
+		if btf.config.Verbose >= config.VerboseFiles {
 			logrus.Debugf("파일 %s: 끝 시간(%s)이 검색 시작 시간(%s)보다 이름\n",
 				fileRange.FileName,
 				fileRange.EndTime.Format("2006-01-02 15:04:05"),
@@ -278,7 +241,7 @@ func (btf *BinlogTimeFinder) isFileInTimeRange(fileRange FileTimeRange) bool {
 
 	// 파일의 시작 시간이 검색 끝 시간보다 늦으면 제외
 	if !fileRange.StartTime.IsZero() && fileRange.StartTime.After(searchEndTime) {
-		if btf.config.Verbose {
+		if btf.config.Verbose >= config.VerboseFiles {
 			logrus.Debugf("파일 %s: 시작 시간(%s)이 검색 끝 시간(%s)보다 늦음\n",
 				fileRange.FileName,
 				fileRange.StartTime.Format("2006-01-02 15:04:05"),
@@ -288,7 +251,7 @@ func (btf *BinlogTimeFinder) isFileInTimeRange(fileRange FileTimeRange) bool {
 	}
 
 	// 그 외의 경우는 모두 포함 (겹치는 부분이 있음)
-	if btf.config.Verbose {
+	if btf.config.Verbose >= config.VerboseFiles {
 		logrus.Debugf("파일 %s: 시간 범위에 포함됨 (겹치는 부분 존재)\n", fileRange.FileName)
 	}
 	return true
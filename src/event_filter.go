@@ -0,0 +1,203 @@
+package src
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// 출력 대상 이벤트 선별 필터
+type EventFilter struct {
+	excludeTable *regexp.Regexp
+	predicates   map[string][]rowPredicate // key: schema.table
+	minExecTime  time.Duration
+	onlyErrors   bool
+	database     string // --database
+
+	expr *filterExpr // --filter-expr
+
+	// --start-position은 첫 대상 파일, --stop-position은 마지막 대상 파일에만 적용 (mysqlbinlog와 같음)
+	startPosition uint32
+	stopPosition  uint32
+	firstFile     string
+	lastFile      string
+}
+
+// 설정으로부터 이벤트 필터 생성
+func NewEventFilter(cfg config.Config) (*EventFilter, error) {
+	filter := &EventFilter{
+		minExecTime:   cfg.MinExecTime,
+		onlyErrors:    cfg.OnlyErrors,
+		database:      cfg.Database,
+		startPosition: cfg.StartPosition,
+		stopPosition:  cfg.StopPosition,
+	}
+
+	if cfg.ExcludeTableRegex != "" {
+		re, err := regexp.Compile(cfg.ExcludeTableRegex)
+		if err != nil {
+			return nil, fmt.Errorf("--exclude-table-regex 정규식 오류: %v", err)
+		}
+		filter.excludeTable = re
+	}
+
+	for _, expr := range cfg.Where {
+		pred, err := parseRowPredicate(expr)
+		if err != nil {
+			return nil, err
+		}
+		if filter.predicates == nil {
+			filter.predicates = make(map[string][]rowPredicate)
+		}
+		filter.predicates[pred.table] = append(filter.predicates[pred.table], pred)
+	}
+
+	if cfg.FilterExpr != "" {
+		expr, err := parseFilterExpr(cfg.FilterExpr)
+		if err != nil {
+			return nil, err
+		}
+		filter.expr = expr
+	}
+
+	return filter, nil
+}
+
+// --start-position, --stop-position을 적용할 첫 파일과 마지막 파일 지정
+func (f *EventFilter) SetFileRange(first, last string) error {
+	if first == last && f.startPosition > 0 && f.stopPosition > 0 && f.stopPosition <= f.startPosition {
+		return fmt.Errorf("--stop-position(%d)이 --start-position(%d)보다 커야 합니다", f.stopPosition, f.startPosition)
+	}
+	f.firstFile, f.lastFile = first, last
+	return nil
+}
+
+// 필터 조건에 맞는 이벤트만 반환
+func (f *EventFilter) Filter(events []config.SQLEvent) []config.SQLEvent {
+	if f == nil {
+		return events
+	}
+
+	filtered := events[:0]
+	for i := range events {
+		if f.Match(&events[i]) {
+			filtered = append(filtered, events[i])
+		}
+	}
+	return filtered
+}
+
+// 이벤트가 필터 조건에 맞는지 확인
+func (f *EventFilter) Match(event *config.SQLEvent) bool {
+	if f.excludeTable != nil && f.isExcludedTable(event) {
+		return false
+	}
+	if f.predicates != nil && !f.matchPredicates(event) {
+		return false
+	}
+	if time.Duration(event.ExecTime)*time.Second < f.minExecTime {
+		return false
+	}
+	if f.onlyErrors && event.ErrorCode == 0 {
+		return false
+	}
+	if f.database != "" && event.Database != f.database {
+		return false
+	}
+	if event.Filename == f.firstFile && event.StartPosition < f.startPosition {
+		return false
+	}
+	if event.Filename == f.lastFile && f.stopPosition > 0 && event.StartPosition >= f.stopPosition {
+		return false
+	}
+	if f.expr != nil && !f.expr.match(event) {
+		return false
+	}
+	return true
+}
+
+// --where 조건 확인
+// 조건이 지정된 테이블의 row 이벤트만 대상이며, 같은 테이블의 조건은 모두 만족해야 함 (AND)
+// 서로 다른 테이블의 조건은 각 테이블 이벤트에 따로 적용 (OR)
+func (f *EventFilter) matchPredicates(event *config.SQLEvent) bool {
+	if event.EventType == "QUERY" {
+		return false
+	}
+
+	predicates, ok := f.predicates[event.Database+"."+event.Table]
+	if !ok {
+		return false
+	}
+	for _, pred := range predicates {
+		if !pred.matchEvent(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// 제외 대상 테이블 이벤트인지 확인
+// 쿼리 이벤트는 참조하는 테이블이 모두 제외 대상일 때만 제외 (예: RENAME으로 테이블을 교체하는 경우 유지)
+func (f *EventFilter) isExcludedTable(event *config.SQLEvent) bool {
+	if event.EventType != "QUERY" {
+		return f.excludeTable.MatchString(event.Database + "." + event.Table)
+	}
+
+	tables := queryTables(event.Database, event.SQL)
+	if len(tables) == 0 {
+		return false
+	}
+	for _, table := range tables {
+		if !f.excludeTable.MatchString(table) {
+			return false
+		}
+	}
+	return true
+}
+
+// 쿼리가 참조하는 테이블 목록 추출 (schema.table 형태, 간단한 키워드 기반)
+func queryTables(defaultSchema, query string) []string {
+	tokens := tokenizeSQL(query)
+
+	var tables []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			tables = append(tables, name)
+		}
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "TABLE", "TABLES", "INTO", "UPDATE", "FROM", "JOIN", "TO":
+			rest := skipKeywords(tokens[i+1:], "IF", "NOT", "EXISTS", "LOW_PRIORITY", "IGNORE")
+			for {
+				name, next := parseTableName(defaultSchema, rest)
+				if name == "" || isReservedWord(strings.TrimPrefix(name, defaultSchema+".")) {
+					break
+				}
+				add(name)
+				// 쉼표로 나열된 테이블 (DROP TABLE a, b)
+				if len(next) < 2 || next[0] != "," {
+					break
+				}
+				rest = next[1:]
+			}
+		}
+	}
+
+	return tables
+}
+
+// 테이블 이름 위치에 올 수 있는 SQL 키워드인지 확인
+func isReservedWord(token string) bool {
+	switch strings.ToUpper(token) {
+	case "SELECT", "SET", "WHERE", "VALUES", "VALUE", "DUAL", "(", "ON", "AS":
+		return true
+	}
+	return false
+}
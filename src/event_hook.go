@@ -0,0 +1,281 @@
+package src
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"mysqlbinlogo/config"
+)
+
+// 이벤트 훅 (--hook): 출력 전에 이벤트마다 외부 명령에 보내 바꾸거나 버림
+// 명령은 실행마다 한 번 시작하고, 표준 입력으로 이벤트 JSON 한 줄을 받아 표준 출력으로 한 줄을 돌려줌
+// 돌려준 줄이 객체면 그 내용으로 이벤트를 바꾸고, null이면 이벤트를 버림 (표준 오류는 그대로 전달)
+// 명령은 한 줄마다 표준 출력을 flush해야 하며, hookReplyTimeout 안에 응답이 없으면 오류
+type eventHook struct {
+	command string
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	replies chan hookReply // 표준 출력에서 읽은 줄 (읽기 고루틴)
+	done    chan struct{}  // Close하면 닫힘 (읽기 고루틴 종료)
+	timeout time.Duration  // 이벤트 하나의 응답 대기 시간
+
+	closed   bool
+	closeErr error
+}
+
+// 이벤트 하나의 응답 대기 시간 (출력을 버퍼링하는 훅이 실행을 멈추지 않도록)
+const hookReplyTimeout = 30 * time.Second
+
+// 훅이 돌려준 한 줄
+type hookReply struct {
+	line []byte
+	err  error
+}
+
+// 훅에 보내는 이벤트 (row 이미지와 컬럼 이름 포함)
+type hookEvent struct {
+	*config.SQLEvent
+	Columns []string        `json:"columns,omitempty"`
+	Rows    [][]interface{} `json:"rows,omitempty"`
+}
+
+// 훅이 돌려준 이벤트의 row 이미지 (값마다 보낸 것과 비교하여 바뀐 값만 반영)
+type hookRows struct {
+	Columns []string            `json:"columns"`
+	Rows    [][]json.RawMessage `json:"rows"`
+}
+
+// 훅 명령 시작 (셸로 실행)
+func startEventHook(ctx context.Context, command string) (*eventHook, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf(T("이벤트 훅 시작 실패: %v"), err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf(T("이벤트 훅 시작 실패: %v"), err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf(T("이벤트 훅 시작 실패: %v"), err)
+	}
+	h := &eventHook{
+		command: command,
+		cmd:     cmd,
+		stdin:   stdin,
+		replies: make(chan hookReply),
+		done:    make(chan struct{}),
+		timeout: hookReplyTimeout,
+	}
+	go h.readReplies(stdout)
+	return h, nil
+}
+
+// 훅의 표준 출력을 한 줄씩 읽어 전달 (읽기 오류나 Close까지)
+func (h *eventHook) readReplies(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		select {
+		case h.replies <- hookReply{line: line, err: err}:
+		case <-h.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// 결과 이벤트 전체를 훅에 보내고 남은 이벤트 반환 (--hook)
+func (ba *BinlogAnalyzer) applyHook(ctx context.Context, events []config.SQLEvent) ([]config.SQLEvent, error) {
+	hook, err := startEventHook(ctx, ba.Config.Hook)
+	if err != nil {
+		return nil, err
+	}
+	total := len(events)
+	events, err = hook.Filter(events)
+	if closeErr := hook.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ba.Config.Verbose >= config.VerboseFiles {
+		fmt.Fprintf(ba.messageOutput(), T("이벤트 훅 적용: %d개 중 %d개 유지\n"), total, len(events))
+	}
+	return events, nil
+}
+
+// 이벤트 하나를 훅에 보내고 결과 반영 (버려야 하면 false)
+func (h *eventHook) apply(event *config.SQLEvent) (bool, error) {
+	sent := hookEvent{SQLEvent: event, Columns: event.Columns, Rows: hookRowValues(event.Rows)}
+	line, err := json.Marshal(sent)
+	if err != nil {
+		return false, fmt.Errorf(T("이벤트 훅: 이벤트 %s를 JSON으로 변환할 수 없습니다: %v"), eventKey(event), err)
+	}
+	if _, err := h.stdin.Write(append(line, '\n')); err != nil {
+		return false, h.failed(fmt.Errorf(T("이벤트 훅 쓰기 실패: %v"), err))
+	}
+
+	var reply []byte
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-h.replies:
+		if r.err != nil {
+			if r.err == io.EOF {
+				err = fmt.Errorf(T("이벤트 훅이 이벤트 %s에 응답하기 전에 종료되었습니다"), eventKey(event))
+			} else {
+				err = fmt.Errorf(T("이벤트 훅 읽기 실패: %v"), r.err)
+			}
+			return false, h.failed(err)
+		}
+		reply = bytes.TrimSpace(r.line)
+	case <-timer.C:
+		// 응답을 기다리는 훅은 표준 입력을 닫아도 끝나지 않을 수 있어 종료시킴
+		h.cmd.Process.Kill()
+		return false, h.failed(fmt.Errorf(T("이벤트 훅이 이벤트 %s에 %s 동안 응답하지 않았습니다 (훅은 응답 한 줄마다 표준 출력을 flush해야 함)"), eventKey(event), h.timeout))
+	}
+	if bytes.Equal(reply, []byte("null")) {
+		return false, nil
+	}
+
+	// 훅이 돌려주지 않은 필드와 JSON에 없는 필드(row 이미지의 원래 타입 등)는 원래 값 유지
+	changed := *event
+	var rows hookRows
+	if err := json.Unmarshal(reply, &changed); err != nil {
+		return false, fmt.Errorf(T("이벤트 훅이 이벤트 %s에 잘못된 응답을 보냈습니다: %v"), eventKey(event), err)
+	}
+	if err := json.Unmarshal(reply, &rows); err != nil {
+		return false, fmt.Errorf(T("이벤트 훅이 이벤트 %s에 잘못된 응답을 보냈습니다: %v"), eventKey(event), err)
+	}
+	if rows.Columns != nil {
+		changed.Columns = rows.Columns
+	}
+	if rows.Rows != nil {
+		changed.Rows = mergeHookRows(event.Rows, rows.Rows)
+		changed.RowCount = len(changed.Rows)
+		if changed.EventType == "UPDATE" {
+			changed.RowCount /= 2
+		}
+		if len(changed.Rows) != len(event.Rows) {
+			// 행을 빼거나 더하면 기록되지 않은 컬럼 위치는 더 이상 행과 맞지 않음
+			changed.SkippedColumns = nil
+		}
+	}
+	*event = changed
+	return true, nil
+}
+
+// 이벤트 목록을 훅에 차례로 보내고 남은 이벤트 반환
+func (h *eventHook) Filter(events []config.SQLEvent) ([]config.SQLEvent, error) {
+	kept := events[:0]
+	for i := range events {
+		keep, err := h.apply(&events[i])
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			kept = append(kept, events[i])
+		}
+	}
+	return kept, nil
+}
+
+// 훅의 표준 입력을 닫고 종료를 기다림
+func (h *eventHook) Close() error {
+	if h.closed {
+		return h.closeErr
+	}
+	h.closed = true
+	close(h.done)
+	h.stdin.Close()
+	if err := h.cmd.Wait(); err != nil {
+		h.closeErr = fmt.Errorf(T("이벤트 훅 %q 실패: %v"), h.command, err)
+	}
+	return h.closeErr
+}
+
+// 훅과 주고받다 실패하면 명령의 종료 상태를 함께 보고
+func (h *eventHook) failed(err error) error {
+	if waitErr := h.Close(); waitErr != nil {
+		return fmt.Errorf("%v (%v)", err, waitErr)
+	}
+	return err
+}
+
+// 훅에 보낼 row 값 ([]byte는 UTF-8이면 문자열로 보냄)
+func hookRowValues(rows [][]interface{}) [][]interface{} {
+	if rows == nil {
+		return nil
+	}
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(row))
+		for j, value := range row {
+			values[i][j] = hookValue(value)
+		}
+	}
+	return values
+}
+
+func hookValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok && utf8.Valid(b) {
+		return string(b)
+	}
+	return value
+}
+
+// 훅이 돌려준 row 이미지 (보낸 것과 같은 값은 원래 값과 타입을 유지)
+func mergeHookRows(original [][]interface{}, replied [][]json.RawMessage) [][]interface{} {
+	rows := make([][]interface{}, len(replied))
+	for i, row := range replied {
+		rows[i] = make([]interface{}, len(row))
+		for j, raw := range row {
+			if i < len(original) && j < len(original[i]) {
+				if sent, err := json.Marshal(hookValue(original[i][j])); err == nil && bytes.Equal(sent, bytes.TrimSpace(raw)) {
+					rows[i][j] = original[i][j]
+					continue
+				}
+			}
+			rows[i][j] = decodeHookValue(raw)
+		}
+	}
+	return rows
+}
+
+// 훅이 바꾼 값 (정수는 int64, 그 밖의 숫자는 float64)
+func decodeHookValue(raw json.RawMessage) interface{} {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return string(raw)
+	}
+	if number, ok := value.(json.Number); ok {
+		if n, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+			return n
+		}
+		f, _ := number.Float64()
+		return f
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package src
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+func TestEventHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("훅 명령이 sh 문법")
+	}
+	tests := []struct {
+		name    string
+		command string
+		kept    int
+		err     string // 오류 메시지에 포함되어야 하는 부분 (비어 있으면 성공)
+	}{
+		{"unchanged", "cat", 2, ""},
+		{"drop every event", "while read line; do echo null; done", 0, ""},
+		{"exits before answering", "read line; exit 0", 0, "응답하기 전에 종료"},
+		{"never answers", "cat > /dev/null", 0, "flush"},
+		{"invalid reply", "while read line; do echo '{'; done", 0, "잘못된 응답"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := startEventHook(context.Background(), tt.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hook.timeout = 500 * time.Millisecond
+
+			events := []config.SQLEvent{
+				{EventType: "INSERT", Database: "shop", Table: "orders", Filename: "mysql-bin.000001", Position: 100},
+				{EventType: "DELETE", Database: "shop", Table: "orders", Filename: "mysql-bin.000001", Position: 200},
+			}
+			kept, err := hook.Filter(events)
+			hook.Close()
+
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("Filter() error = %v", err)
+				}
+				if len(kept) != tt.kept {
+					t.Errorf("Filter() kept %d events, want %d", len(kept), tt.kept)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Filter() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package src
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// --filter-expr 조건식 (Go 식 문법)
+// 이벤트 필드는 event.Database 같은 필드 이름이나 database 같은 JSON 이름으로 참조하고, rows는 변경 행 수
+// &&, ||, !, 비교, 산술 연산과 괄호, 문자열/숫자/true/false 리터럴, 문자열 함수를 지원
+type filterExpr struct {
+	source string
+	eval   func(event *config.SQLEvent) any
+}
+
+// 식의 값 종류 (컴파일할 때 확인하여 실행 중에는 타입 오류가 없음)
+type exprKind int
+
+const (
+	exprBool exprKind = iota
+	exprNumber
+	exprString
+	exprTime
+)
+
+func (k exprKind) String() string {
+	switch k {
+	case exprBool:
+		return "bool"
+	case exprNumber:
+		return "number"
+	case exprString:
+		return "string"
+	default:
+		return "time"
+	}
+}
+
+// 컴파일된 식 (값은 bool, float64, string, time.Time)
+type compiledExpr struct {
+	kind exprKind
+	eval func(event *config.SQLEvent) any
+}
+
+// 식에서 참조할 수 있는 이벤트 필드 (이름은 소문자, 밑줄 제외: EventType, event_type → eventtype)
+var exprFields = map[string]compiledExpr{
+	"timestamp":     {exprTime, func(e *config.SQLEvent) any { return e.Timestamp }},
+	"eventtype":     {exprString, func(e *config.SQLEvent) any { return e.EventType }},
+	"database":      {exprString, func(e *config.SQLEvent) any { return e.Database }},
+	"table":         {exprString, func(e *config.SQLEvent) any { return e.Table }},
+	"sql":           {exprString, func(e *config.SQLEvent) any { return e.SQL }},
+	"originalsql":   {exprString, func(e *config.SQLEvent) any { return e.OriginalSQL }},
+	"filename":      {exprString, func(e *config.SQLEvent) any { return e.Filename }},
+	"transaction":   {exprString, func(e *config.SQLEvent) any { return e.Transaction }},
+	"serverid":      {exprNumber, func(e *config.SQLEvent) any { return float64(e.ServerId) }},
+	"position":      {exprNumber, func(e *config.SQLEvent) any { return float64(e.Position) }},
+	"startposition": {exprNumber, func(e *config.SQLEvent) any { return float64(e.StartPosition) }},
+	"eventsize":     {exprNumber, func(e *config.SQLEvent) any { return float64(e.EventSize) }},
+	"exectime":      {exprNumber, func(e *config.SQLEvent) any { return float64(e.ExecTime) }},
+	"errorcode":     {exprNumber, func(e *config.SQLEvent) any { return float64(e.ErrorCode) }},
+	"capturedat":    {exprTime, func(e *config.SQLEvent) any { return capturedAtValue(e) }},
+	"rowcount":      {exprNumber, func(e *config.SQLEvent) any { return float64(e.RowCount) }},
+	"rows":          {exprNumber, func(e *config.SQLEvent) any { return float64(e.RowCount) }},
+}
+
+// 이벤트를 수신한 시각 (실시간 추적 모드가 아니면 zero 시각)
+func capturedAtValue(e *config.SQLEvent) time.Time {
+	if e.CapturedAt == nil {
+		return time.Time{}
+	}
+	return *e.CapturedAt
+}
+
+// 조건식 파싱 (결과가 bool인 식만 허용)
+func parseFilterExpr(source string) (*filterExpr, error) {
+	node, err := parser.ParseExpr(source)
+	if err != nil {
+		return nil, fmt.Errorf("--filter-expr 구문 오류: %v", err)
+	}
+	compiled, err := compileExpr(node)
+	if err != nil {
+		return nil, fmt.Errorf("--filter-expr 오류: %v", err)
+	}
+	if compiled.kind != exprBool {
+		return nil, fmt.Errorf("--filter-expr는 참/거짓 조건이어야 합니다 (%s 값)", compiled.kind)
+	}
+	return &filterExpr{source: source, eval: compiled.eval}, nil
+}
+
+// 이벤트가 조건식을 만족하는지 확인
+func (f *filterExpr) match(event *config.SQLEvent) bool {
+	return f.eval(event).(bool)
+}
+
+func compileExpr(node ast.Expr) (compiledExpr, error) {
+	switch node := node.(type) {
+	case *ast.ParenExpr:
+		return compileExpr(node.X)
+	case *ast.BasicLit:
+		return compileLiteral(node)
+	case *ast.Ident:
+		switch node.Name {
+		case "true", "false":
+			value := node.Name == "true"
+			return compiledExpr{exprBool, func(*config.SQLEvent) any { return value }}, nil
+		}
+		return lookupExprField(node.Name)
+	case *ast.SelectorExpr:
+		if base, ok := node.X.(*ast.Ident); !ok || base.Name != "event" {
+			return compiledExpr{}, fmt.Errorf("필드는 event.<이름> 형식으로 참조해야 합니다: %s", types.ExprString(node))
+		}
+		return lookupExprField(node.Sel.Name)
+	case *ast.UnaryExpr:
+		return compileUnary(node)
+	case *ast.BinaryExpr:
+		return compileBinary(node)
+	case *ast.CallExpr:
+		return compileCall(node)
+	}
+	return compiledExpr{}, fmt.Errorf("지원하지 않는 식입니다: %s", types.ExprString(node))
+}
+
+func lookupExprField(name string) (compiledExpr, error) {
+	field, ok := exprFields[strings.ToLower(strings.ReplaceAll(name, "_", ""))]
+	if !ok {
+		return compiledExpr{}, fmt.Errorf("알 수 없는 필드입니다: %s", name)
+	}
+	return field, nil
+}
+
+func compileLiteral(lit *ast.BasicLit) (compiledExpr, error) {
+	switch lit.Kind {
+	case token.INT, token.FLOAT:
+		value, _ := constant.Float64Val(constant.ToFloat(constant.MakeFromLiteral(lit.Value, lit.Kind, 0)))
+		return compiledExpr{exprNumber, func(*config.SQLEvent) any { return value }}, nil
+	case token.STRING:
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return compiledExpr{}, fmt.Errorf("문자열 리터럴 오류: %s", lit.Value)
+		}
+		return compiledExpr{exprString, func(*config.SQLEvent) any { return value }}, nil
+	}
+	return compiledExpr{}, fmt.Errorf("지원하지 않는 리터럴입니다: %s (문자열은 큰따옴표로 감쌈)", lit.Value)
+}
+
+func compileUnary(node *ast.UnaryExpr) (compiledExpr, error) {
+	operand, err := compileExpr(node.X)
+	if err != nil {
+		return compiledExpr{}, err
+	}
+	switch {
+	case node.Op == token.NOT && operand.kind == exprBool:
+		return compiledExpr{exprBool, func(e *config.SQLEvent) any { return !operand.eval(e).(bool) }}, nil
+	case node.Op == token.SUB && operand.kind == exprNumber:
+		return compiledExpr{exprNumber, func(e *config.SQLEvent) any { return -operand.eval(e).(float64) }}, nil
+	}
+	return compiledExpr{}, fmt.Errorf("%s에 %s 연산자를 쓸 수 없습니다: %s", operand.kind, node.Op, types.ExprString(node))
+}
+
+func compileBinary(node *ast.BinaryExpr) (compiledExpr, error) {
+	x, err := compileExpr(node.X)
+	if err != nil {
+		return compiledExpr{}, err
+	}
+	y, err := compileExpr(node.Y)
+	if err != nil {
+		return compiledExpr{}, err
+	}
+
+	switch node.Op {
+	case token.LAND, token.LOR:
+		if x.kind != exprBool || y.kind != exprBool {
+			break
+		}
+		if node.Op == token.LAND {
+			return compiledExpr{exprBool, func(e *config.SQLEvent) any { return x.eval(e).(bool) && y.eval(e).(bool) }}, nil
+		}
+		return compiledExpr{exprBool, func(e *config.SQLEvent) any { return x.eval(e).(bool) || y.eval(e).(bool) }}, nil
+
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		// 시각은 문자열 리터럴과 비교 (--start-time과 같은 형식)
+		if x.kind == exprTime && y.kind == exprString {
+			if y, err = timeLiteral(node.Y); err != nil {
+				return compiledExpr{}, err
+			}
+		} else if x.kind == exprString && y.kind == exprTime {
+			if x, err = timeLiteral(node.X); err != nil {
+				return compiledExpr{}, err
+			}
+		}
+		if x.kind != y.kind || (x.kind == exprBool && node.Op != token.EQL && node.Op != token.NEQ) {
+			break
+		}
+		op := node.Op
+		return compiledExpr{exprBool, func(e *config.SQLEvent) any {
+			c := compareExprValues(x.eval(e), y.eval(e))
+			switch op {
+			case token.EQL:
+				return c == 0
+			case token.NEQ:
+				return c != 0
+			case token.LSS:
+				return c < 0
+			case token.LEQ:
+				return c <= 0
+			case token.GTR:
+				return c > 0
+			default:
+				return c >= 0
+			}
+		}}, nil
+
+	case token.ADD:
+		if x.kind == exprString && y.kind == exprString {
+			return compiledExpr{exprString, func(e *config.SQLEvent) any { return x.eval(e).(string) + y.eval(e).(string) }}, nil
+		}
+		fallthrough
+	case token.SUB, token.MUL, token.QUO, token.REM:
+		if x.kind != exprNumber || y.kind != exprNumber {
+			break
+		}
+		op := node.Op
+		return compiledExpr{exprNumber, func(e *config.SQLEvent) any {
+			a, b := x.eval(e).(float64), y.eval(e).(float64)
+			switch op {
+			case token.ADD:
+				return a + b
+			case token.SUB:
+				return a - b
+			case token.MUL:
+				return a * b
+			case token.QUO:
+				return a / b
+			default:
+				return math.Mod(a, b)
+			}
+		}}, nil
+	}
+	return compiledExpr{}, fmt.Errorf("%s와 %s에 %s 연산자를 쓸 수 없습니다: %s", x.kind, y.kind, node.Op, types.ExprString(node))
+}
+
+// 시각과 비교하는 문자열 리터럴
+func timeLiteral(node ast.Expr) (compiledExpr, error) {
+	for {
+		paren, ok := node.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		node = paren.X
+	}
+	lit, ok := node.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return compiledExpr{}, fmt.Errorf("시각은 문자열 리터럴과만 비교할 수 있습니다: %s", types.ExprString(node))
+	}
+	text, _ := strconv.Unquote(lit.Value)
+	value, err := ParseTime(text)
+	if err != nil {
+		return compiledExpr{}, err
+	}
+	return compiledExpr{exprTime, func(*config.SQLEvent) any { return value }}, nil
+}
+
+func compareExprValues(a, b any) int {
+	switch a := a.(type) {
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case string:
+		return strings.Compare(a, b.(string))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	default:
+		if a == b {
+			return 0
+		}
+		return 1
+	}
+}
+
+// 문자열 함수: contains, hasPrefix, hasSuffix, matches(정규식), lower, upper, len
+func compileCall(node *ast.CallExpr) (compiledExpr, error) {
+	name, ok := node.Fun.(*ast.Ident)
+	if !ok {
+		return compiledExpr{}, fmt.Errorf("지원하지 않는 함수입니다: %s", types.ExprString(node.Fun))
+	}
+	args := make([]compiledExpr, len(node.Args))
+	for i, arg := range node.Args {
+		compiled, err := compileExpr(arg)
+		if err != nil {
+			return compiledExpr{}, err
+		}
+		if compiled.kind != exprString {
+			return compiledExpr{}, fmt.Errorf("%s의 인자는 문자열이어야 합니다: %s", name.Name, types.ExprString(arg))
+		}
+		args[i] = compiled
+	}
+
+	wantArgs := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s는 인자 %d개가 필요합니다: %s", name.Name, n, types.ExprString(node))
+		}
+		return nil
+	}
+	predicate := func(fn func(s, t string) bool) (compiledExpr, error) {
+		if err := wantArgs(2); err != nil {
+			return compiledExpr{}, err
+		}
+		return compiledExpr{exprBool, func(e *config.SQLEvent) any { return fn(args[0].eval(e).(string), args[1].eval(e).(string)) }}, nil
+	}
+
+	switch name.Name {
+	case "contains":
+		return predicate(strings.Contains)
+	case "hasPrefix":
+		return predicate(strings.HasPrefix)
+	case "hasSuffix":
+		return predicate(strings.HasSuffix)
+	case "matches":
+		// 정규식은 리터럴로만 받아 한 번만 컴파일
+		if err := wantArgs(2); err != nil {
+			return compiledExpr{}, err
+		}
+		lit, ok := node.Args[1].(*ast.BasicLit)
+		if !ok {
+			return compiledExpr{}, fmt.Errorf("matches의 정규식은 문자열 리터럴이어야 합니다: %s", types.ExprString(node))
+		}
+		pattern, _ := strconv.Unquote(lit.Value)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return compiledExpr{}, fmt.Errorf("matches 정규식 오류: %v", err)
+		}
+		return compiledExpr{exprBool, func(e *config.SQLEvent) any { return re.MatchString(args[0].eval(e).(string)) }}, nil
+	case "lower", "upper":
+		if err := wantArgs(1); err != nil {
+			return compiledExpr{}, err
+		}
+		fn := strings.ToLower
+		if name.Name == "upper" {
+			fn = strings.ToUpper
+		}
+		return compiledExpr{exprString, func(e *config.SQLEvent) any { return fn(args[0].eval(e).(string)) }}, nil
+	case "len":
+		if err := wantArgs(1); err != nil {
+			return compiledExpr{}, err
+		}
+		return compiledExpr{exprNumber, func(e *config.SQLEvent) any { return float64(len(args[0].eval(e).(string))) }}, nil
+	}
+	return compiledExpr{}, fmt.Errorf("지원하지 않는 함수입니다: %s (contains, hasPrefix, hasSuffix, matches, lower, upper, len)", name.Name)
+}
@@ -0,0 +1,151 @@
+package src
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+func TestFilterExprMatch(t *testing.T) {
+	event := &config.SQLEvent{
+		Timestamp:   time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		EventType:   "UPDATE",
+		Database:    "shop",
+		Table:       "orders",
+		SQL:         "UPDATE `shop`.`orders` SET status = 'paid'",
+		ServerId:    100,
+		Position:    1200,
+		ErrorCode:   0,
+		RowCount:    5,
+		Transaction: "uuid:7",
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// 필드 이름
+		{`database == "shop"`, true},
+		{`event.Database == "shop"`, true},
+		{`event_type == "UPDATE" && event.EventType == "UPDATE"`, true},
+		{`rows == 5 && rowcount == 5`, true},
+
+		// 우선순위: &&가 ||보다 먼저
+		{`true || false && false`, true},
+		{`(true || false) && false`, false},
+		{`false && true || true`, true},
+		{`false && (true || true)`, false},
+		{`database == "other" || table == "orders" && rows > 1`, true},
+		{`(database == "other" || table == "orders") && rows > 10`, false},
+
+		// 우선순위: 단항 !는 비교보다 먼저 (괄호로 비교 결과를 부정)
+		{`!(database == "other")`, true},
+		{`!(rows > 1) || table == "orders"`, true},
+		{`!true == false`, true},
+
+		// 우선순위: 산술이 비교보다 먼저, * / %가 + -보다 먼저
+		{`rows + 1 * 2 == 7`, true},
+		{`(rows + 1) * 2 == 12`, true},
+		{`rows - 2 - 1 == 2`, true},
+		{`rows % 3 + 1 == 3`, true},
+		{`position / 100 / 2 == 6`, true},
+		{`-rows + 10 == 5`, true},
+		{`-(rows + 10) == -15`, true},
+
+		// 비교
+		{`serverid >= 100 && serverid < 101`, true},
+		{`errorcode != 0`, false},
+		{`table < "users"`, true},
+		{`true == (rows == 5)`, true},
+		{`"sh" + "op" == database`, true},
+
+		// 시각은 문자열 리터럴과 비교
+		{`timestamp >= "2024-01-15 09:00:00 UTC" && timestamp < "2024-01-15 11:00:00 UTC"`, true},
+		{`"2024-01-16 00:00:00 UTC" < timestamp`, false},
+		{`timestamp == ("2024-01-15 10:00:00 UTC")`, true},
+
+		// 함수
+		{`contains(sql, "status") && hasPrefix(table, "ord") && hasSuffix(transaction, ":7")`, true},
+		{`matches(sql, "(?i)^update ") && !matches(sql, "DELETE")`, true},
+		{`upper(database) == "SHOP" && lower("ORDERS") == table`, true},
+		{`len(database) * 2 == 8`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := parseFilterExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("parseFilterExpr() error = %v", err)
+			}
+			if got := f.match(event); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterExprErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string // 오류 메시지에 포함되어야 하는 부분
+	}{
+		// 구문
+		{`database ==`, "구문 오류"},
+		{`database = "shop"`, "구문 오류"},
+
+		// 결과가 bool이 아님
+		{`rows + 1`, "참/거짓 조건이어야 합니다 (number 값)"},
+		{`database`, "참/거짓 조건이어야 합니다 (string 값)"},
+		{`timestamp`, "참/거짓 조건이어야 합니다 (time 값)"},
+		{`lower(table)`, "참/거짓 조건이어야 합니다 (string 값)"},
+
+		// 연산자와 값 종류
+		{`database == 1`, "string와 number에 == 연산자를 쓸 수 없습니다"},
+		{`rows == "5"`, "number와 string에 == 연산자를 쓸 수 없습니다"},
+		{`rows && true`, "number와 bool에 && 연산자를 쓸 수 없습니다"},
+		{`true || database`, "bool와 string에 || 연산자를 쓸 수 없습니다"},
+		{`true < false`, "bool와 bool에 < 연산자를 쓸 수 없습니다"},
+		{`database - "s" == ""`, "string와 string에 - 연산자를 쓸 수 없습니다"},
+		{`rows + "1" == 6`, "number와 string에 + 연산자를 쓸 수 없습니다"},
+		{`!rows`, "number에 ! 연산자를 쓸 수 없습니다"},
+		{`-database == ""`, "string에 - 연산자를 쓸 수 없습니다"},
+		{`rows & 1 == 1`, "연산자를 쓸 수 없습니다"},
+
+		// 우선순위 때문에 생기는 타입 오류: ! 는 비교보다 먼저 적용
+		{`!database == "shop"`, "string에 ! 연산자를 쓸 수 없습니다"},
+		// 비교가 &&보다 먼저이므로 rows > (1 && ...)가 아님
+		{`rows > 1 && 2`, "bool와 number에 && 연산자를 쓸 수 없습니다"},
+
+		// 시각
+		{`timestamp > 5`, "time와 number에 > 연산자를 쓸 수 없습니다"},
+		{`timestamp > database`, "시각은 문자열 리터럴과만 비교할 수 있습니다"},
+		{`timestamp + 1 > 0`, "time와 number에 + 연산자를 쓸 수 없습니다"},
+
+		// 필드와 리터럴
+		{`unknown == 1`, "알 수 없는 필드입니다: unknown"},
+		{`event.Unknown == 1`, "알 수 없는 필드입니다: Unknown"},
+		{`row.Database == "shop"`, "event.<이름> 형식으로 참조해야 합니다"},
+		{`database == 's'`, "지원하지 않는 리터럴입니다"},
+		{`rows[0] == 1`, "지원하지 않는 식입니다"},
+
+		// 함수
+		{`contains(database)`, "contains는 인자 2개가 필요합니다"},
+		{`contains(database, 1)`, "contains의 인자는 문자열이어야 합니다"},
+		{`len(rows) > 0`, "len의 인자는 문자열이어야 합니다"},
+		{`matches(sql, table)`, "matches의 정규식은 문자열 리터럴이어야 합니다"},
+		{`matches(sql, "(")`, "matches 정규식 오류"},
+		{`strings.Contains(sql, "x")`, "지원하지 않는 함수입니다"},
+		{`trim(sql) == ""`, "지원하지 않는 함수입니다: trim"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := parseFilterExpr(tt.expr)
+			if err == nil {
+				t.Fatalf("parseFilterExpr() = %+v, want error", f)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseFilterExpr() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package src
+
+import (
+	"regexp"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// fingerprint 최대 길이 (긴 IN 목록이나 다중 VALUES는 앞에서 접히므로 대부분 이보다 짧음)
+const maxFingerprintLength = 1024
+
+var (
+	fingerprintNumber = regexp.MustCompile(`^[+-]?(0x[0-9a-fA-F]+|[0-9]+([eE][+-]?[0-9]+)?)$`)
+	fingerprintList   = regexp.MustCompile(`\?(, \?)+`)               // IN (?, ?, ?)
+	fingerprintTuples = regexp.MustCompile(`\(\?\+?\)(, \(\?\+?\))+`) // VALUES (?+), (?+)
+)
+
+// 문장 fingerprint (리터럴은 ?, 값 목록은 ?+, 소문자, 주석과 공백 정리)
+// 예: UPDATE orders SET status='paid' WHERE id IN (1,2,3) → update orders set status = ? where id in (?+)
+func statementFingerprint(query string) string {
+	tokens := tokenizeSQL(query)
+	var sb strings.Builder
+	prev := ""
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case strings.HasPrefix(token, "'") || strings.HasPrefix(token, "\""), fingerprintNumber.MatchString(token):
+			// 소수는 1 . 5로 나뉘어 있음
+			if i+2 < len(tokens) && tokens[i+1] == "." && fingerprintNumber.MatchString(tokens[i+2]) {
+				i += 2
+			}
+			token = "?"
+		case token == ";":
+			continue
+		default:
+			token = strings.ToLower(token)
+		}
+
+		if sb.Len() > 0 && prev != "(" && prev != "." && token != "," && token != ")" && token != "." {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(token)
+		prev = token
+	}
+
+	fingerprint := fingerprintList.ReplaceAllString(sb.String(), "?+")
+	fingerprint = fingerprintTuples.ReplaceAllString(fingerprint, "(?+)...")
+	if len(fingerprint) > maxFingerprintLength {
+		fingerprint = strings.ToValidUTF8(fingerprint[:maxFingerprintLength], "")
+	}
+	return fingerprint
+}
+
+// 이벤트의 문장 fingerprint (row 이벤트는 원본 SQL, 없으면 유형과 테이블)
+func eventFingerprint(event *config.SQLEvent) string {
+	switch {
+	case event.EventType == "QUERY":
+		return statementFingerprint(event.SQL)
+	case event.OriginalSQL != "":
+		return statementFingerprint(event.OriginalSQL)
+	default:
+		return strings.ToLower(event.EventType + " " + event.Database + "." + event.Table)
+	}
+}
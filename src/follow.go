@@ -0,0 +1,340 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// 지연 시간 집계 출력 주기
+const latencyReportInterval = time.Minute
+
+// Follow 현재 binary log 위치부터 새로 기록되는 이벤트를 실시간으로 출력 (ctx 취소 시 종료)
+func (ba *BinlogAnalyzer) Follow(ctx context.Context) error {
+	if err := validateSink(ba.Config); err != nil {
+		return err
+	}
+	if err := validateFormat(ba.Config); err != nil {
+		return err
+	}
+	if err := validateSplit(ba.Config); err != nil {
+		return err
+	}
+	if err := validateOutputFile(ba.Config); err != nil {
+		return err
+	}
+	if err := validateTimeline(ba.Config); err != nil {
+		return err
+	}
+	if err := validateCapacityReport(ba.Config); err != nil {
+		return err
+	}
+	if err := validatePushgateway(ba.Config); err != nil {
+		return err
+	}
+	if err := validateRunSummary(ba.Config); err != nil {
+		return err
+	}
+	if err := validateRunHistory(ba.Config); err != nil {
+		return err
+	}
+	if len(ba.Config.BinlogFiles) > 0 {
+		return fmt.Errorf("--binlog-files는 --follow와 함께 사용할 수 없습니다")
+	}
+	if ba.Config.RetentionAdvice {
+		return fmt.Errorf("--retention-advice는 --follow와 함께 사용할 수 없습니다")
+	}
+	if ba.Config.StartPosition > 0 || ba.Config.StopPosition > 0 {
+		return fmt.Errorf("--start-position, --stop-position은 --follow와 함께 사용할 수 없습니다")
+	}
+
+	filter, err := NewEventFilter(ba.Config)
+	if err != nil {
+		return err
+	}
+	ba.filter = filter
+
+	pos, err := ba.prepareFollow()
+	if err != nil {
+		return err
+	}
+	defer ba.conn.Close()
+
+	// --sink가 있으면 파일 대신 싱크로 전송
+	var writeEvent func(*config.SQLEvent) error
+	var flush func() error
+	if ba.Config.Sink != "" {
+		sink, err := newEventSink(ctx, ba.Config)
+		if err != nil {
+			return fmt.Errorf("%s 싱크 생성 실패: %v", ba.Config.Sink, err)
+		}
+		buffer := newSinkBuffer(sink)
+		defer func() {
+			if err := buffer.close(ctx); err != nil {
+				logrus.Warnf("%s 싱크 종료 실패: %v", ba.Config.Sink, err)
+			}
+		}()
+		writeEvent = func(ev *config.SQLEvent) error { return buffer.add(ctx, ev) }
+		flush = func() error { return buffer.tick(ctx) }
+	} else if rotating(ba.Config) {
+		// 추적하는 동안 한도에 이를 때마다 번호가 붙은 다음 파일로
+		writer := ba.newRotatingWriter(nil)
+		defer writer.finish()
+		writeEvent = func(ev *config.SQLEvent) error {
+			writer.writeEvent(ev)
+			return writer.err
+		}
+	} else {
+		output := &firstErrorWriter{Writer: os.Stdout}
+		if ba.Config.OutputFile != "" {
+			file, err := createOutputFile(ba.Config, ba.Config.OutputFile)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				// 압축 파일은 닫을 때 gzip 트레일러, zstd 프레임 끝을 씀
+				if err := file.Close(); err != nil {
+					logrus.Warnf("결과 파일 닫기 실패: %v", err)
+				}
+			}()
+			output.Writer = file
+		}
+
+		writer := ba.newEventWriter(output)
+		defer writer.finish()
+		writeEvent = func(ev *config.SQLEvent) error {
+			writer.writeEvent(ev)
+			if output.err != nil {
+				return fmt.Errorf("결과 기록 실패: %v", output.err)
+			}
+			return nil
+		}
+	}
+
+	// --hook은 추적하는 동안 하나의 명령에 이벤트를 차례로 보냄
+	var hook *eventHook
+	if ba.Config.Hook != "" {
+		if hook, err = startEventHook(ctx, ba.Config.Hook); err != nil {
+			return err
+		}
+		defer func() {
+			if err := hook.Close(); err != nil && ctx.Err() == nil {
+				logrus.Warn(err)
+			}
+		}()
+	}
+
+	logrus.Infof("실시간 추적 시작: %s:%d (Ctrl+C로 종료)", pos.Name, pos.Pos)
+
+	stats := &latencyStats{}
+	window := &latencyStats{}
+	reported := time.Now()
+
+	err = ba.streamEvents(ctx, pos,
+		func(sqlEvent *config.SQLEvent) error {
+			if !ba.filter.Match(sqlEvent) {
+				return nil
+			}
+			if hook != nil {
+				if keep, err := hook.apply(sqlEvent); err != nil || !keep {
+					return err
+				}
+			}
+
+			latency := captureLatency(sqlEvent)
+			stats.add(latency)
+			window.add(latency)
+
+			return writeEvent(sqlEvent)
+		},
+		func() error {
+			if time.Since(reported) >= latencyReportInterval {
+				if window.count() > 0 {
+					logrus.Infof("캡처 지연 (최근 %s): %s", latencyReportInterval, window.summary())
+				}
+				window = &latencyStats{}
+				reported = time.Now()
+			}
+			if flush != nil {
+				return flush()
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	if stats.count() > 0 {
+		logrus.Infof("실시간 추적 종료: %d개 이벤트, 캡처 지연 %s", stats.count(), stats.summary())
+	} else {
+		logrus.Infof("실시간 추적 종료: 출력된 이벤트 없음")
+	}
+	return nil
+}
+
+// 실시간 추적 준비 (연결, 스키마 스냅샷, 현재 binary log 위치)
+func (ba *BinlogAnalyzer) prepareFollow() (mysql.Position, error) {
+	if err := ba.connect(); err != nil {
+		return mysql.Position{}, fmt.Errorf("MySQL 연결 실패: %v", err)
+	}
+
+	ba.checkRowsQueryLogging()
+	ba.schema = NewSchemaSnapshot(ba.conn)
+
+	pos, err := ba.currentBinlogPosition()
+	if err != nil {
+		ba.conn.Close()
+		return mysql.Position{}, fmt.Errorf("현재 binary log 위치 확인 실패: %v", err)
+	}
+	return pos, nil
+}
+
+// pos부터 이벤트를 읽어 SQL 이벤트마다 onEvent 호출 (필터는 호출하는 쪽에서 적용, ctx 취소 시 nil 반환)
+// onTick이 있으면 이벤트가 없어도 1초에 한 번 이상 호출 (주기 작업용), 콜백이 오류를 반환하면 중단
+func (ba *BinlogAnalyzer) streamEvents(ctx context.Context, pos mysql.Position, onEvent func(*config.SQLEvent) error, onTick func() error) error {
+	streamer, err := openBinlogStream(ba.Config, 100, pos)
+	if err != nil {
+		return fmt.Errorf("스트리밍 시작 실패: %v", err)
+	}
+	defer streamer.Close()
+
+	extractor := NewSQLExtractor(ba.Config, ba.schema)
+
+	filename := pos.Name
+	for {
+		if onTick != nil {
+			if err := onTick(); err != nil {
+				return err
+			}
+		}
+
+		// 이벤트가 없을 때도 주기 작업을 할 수 있도록 짧은 대기 시간 사용
+		waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+		ev, err := streamer.GetEvent(waitCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				continue
+			}
+			return fmt.Errorf("이벤트 읽기 실패: %v", err)
+		}
+		capturedAt := time.Now()
+
+		if rotate, ok := ev.Event.(*replication.RotateEvent); ok {
+			filename = string(rotate.NextLogName)
+			continue
+		}
+
+		sqlEvent := extractor.convertToSQLEvent(ev, filename)
+		if sqlEvent == nil {
+			continue
+		}
+		sqlEvent.CapturedAt = &capturedAt
+		if err := onEvent(sqlEvent); err != nil {
+			return err
+		}
+	}
+}
+
+// 현재 기록 중인 binary log 위치 (SHOW MASTER STATUS)
+func (ba *BinlogAnalyzer) currentBinlogPosition() (mysql.Position, error) {
+	for _, query := range []string{"SHOW MASTER STATUS", "SHOW BINARY LOG STATUS"} {
+		rows, err := ba.conn.Query(query)
+		if err != nil {
+			continue
+		}
+
+		columns, err := rows.Columns()
+		if err != nil || !rows.Next() {
+			rows.Close()
+			continue
+		}
+
+		// File, Position 이후 컬럼은 버전에 따라 다름
+		values := make([]interface{}, len(columns))
+		var file string
+		var position uint32
+		values[0] = &file
+		values[1] = &position
+		for i := 2; i < len(values); i++ {
+			values[i] = new(interface{})
+		}
+		err = rows.Scan(values...)
+		rows.Close()
+		if err != nil {
+			return mysql.Position{}, err
+		}
+		return mysql.Position{Name: file, Pos: position}, nil
+	}
+	return mysql.Position{}, fmt.Errorf("binary log가 활성화되어 있지 않거나 권한이 없습니다")
+}
+
+// 커밋(이벤트 헤더 시간)부터 캡처까지의 지연 시간
+// 헤더 시간은 초 단위이므로 최대 1초의 오차가 있음
+func captureLatency(event *config.SQLEvent) time.Duration {
+	if event.CapturedAt == nil {
+		return 0
+	}
+	latency := event.CapturedAt.Sub(event.Timestamp)
+	if latency < 0 {
+		return 0
+	}
+	return latency.Round(time.Millisecond)
+}
+
+// 백분위 계산용으로 보관하는 최대 샘플 수 (장시간 추적 시 메모리 제한)
+const maxLatencySamples = 10000
+
+// 캡처 지연 시간 집계
+type latencyStats struct {
+	samples []time.Duration // 최대 maxLatencySamples개 (reservoir sampling)
+	n       int
+	total   time.Duration
+	max     time.Duration
+}
+
+func (ls *latencyStats) add(latency time.Duration) {
+	ls.n++
+	ls.total += latency
+	if latency > ls.max {
+		ls.max = latency
+	}
+
+	if len(ls.samples) < maxLatencySamples {
+		ls.samples = append(ls.samples, latency)
+	} else if i := rand.Intn(ls.n); i < maxLatencySamples {
+		ls.samples[i] = latency
+	}
+}
+
+func (ls *latencyStats) count() int {
+	return ls.n
+}
+
+// 평균/p50/p95/최대 요약
+func (ls *latencyStats) summary() string {
+	sorted := append([]time.Duration(nil), ls.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) time.Duration {
+		return sorted[int(float64(len(sorted)-1)*p)]
+	}
+	avg := (ls.total / time.Duration(ls.n)).Round(time.Millisecond)
+
+	return fmt.Sprintf("avg=%s p50=%s p95=%s max=%s",
+		avg, percentile(0.5), percentile(0.95), ls.max)
+}
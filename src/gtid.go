@@ -0,0 +1,59 @@
+package src
+
+import (
+	"sync"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+// 분석 구간에서 실행된 트랜잭션의 GTID 집합 (필터와 무관하게 구간 안의 모든 GTID 이벤트)
+// 여러 워커의 추출기가 함께 기록하므로 잠금 사용
+type gtidCoverage struct {
+	mu      sync.Mutex
+	mysql   *mysql.MysqlGTIDSet
+	mariadb *mysql.MariadbGTIDSet
+}
+
+func newGTIDCoverage() *gtidCoverage {
+	mysqlSet, _ := mysql.ParseMysqlGTIDSet("")
+	mariadbSet, _ := mysql.ParseMariadbGTIDSet("")
+	return &gtidCoverage{
+		mysql:   mysqlSet.(*mysql.MysqlGTIDSet),
+		mariadb: mariadbSet.(*mysql.MariadbGTIDSet),
+	}
+}
+
+// GTID 이벤트의 GTID 추가 (추출기마다 없으면 nil)
+func (c *gtidCoverage) add(e mysql.BinlogGTIDEvent) {
+	if c == nil {
+		return
+	}
+	gtid, err := e.GTIDNext()
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch gtid.(type) {
+	case *mysql.MysqlGTIDSet:
+		c.mysql.Update(gtid.String())
+	case *mysql.MariadbGTIDSet:
+		// MariaDB는 도메인/서버별 마지막 시퀀스 (gtid_slave_pos 형식)
+		c.mariadb.Update(gtid.String())
+	}
+}
+
+// GTID_PURGED 또는 gtid_slave_pos에 쓸 수 있는 문자열 (GTID가 없으면 빈 문자열)
+func (c *gtidCoverage) String() string {
+	if c == nil {
+		return ""
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.mysql.IsEmpty() {
+		return c.mysql.String()
+	}
+	return c.mariadb.String()
+}
@@ -0,0 +1,127 @@
+package src
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// 행 하나(테이블과 PK 값)의 변경 집계
+type hotRow struct {
+	table        string // schema.table
+	key          string // PK 값 (복합 키는 괄호로 묶음)
+	changes      int
+	types        map[string]int // 이벤트 유형별 변경 수
+	transactions map[string]bool
+	first, last  time.Time
+}
+
+// 변경 유형 요약 (예: UPDATE 40, DELETE 1)
+func (r *hotRow) typeSummary() string {
+	var parts []string
+	for _, eventType := range []string{"INSERT", "UPDATE", "DELETE"} {
+		if count := r.types[eventType]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", eventType, count))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// row 이벤트가 변경한 행마다 fn 호출 (table은 schema.table, key는 PK 값이며 복합 키는 괄호로 묶음)
+// UPDATE는 before 이미지의 PK를 쓰고, PK를 알 수 없는 테이블의 행은 건너뛰어 그 수를 반환
+func forEachChangedRow(events []config.SQLEvent, renderer *SQLExtractor, fn func(event *config.SQLEvent, table, key string)) int {
+	skipped := 0
+	for i := range events {
+		event := &events[i]
+		if event.EventType == "QUERY" {
+			continue
+		}
+		keys := renderer.primaryKeyValues(event)
+		if len(keys) == 0 {
+			skipped += event.RowCount
+			continue
+		}
+		table := qualifiedTableName(event)
+		for _, key := range keys {
+			if strings.Contains(key, ", ") {
+				key = "(" + key + ")"
+			}
+			fn(event, table, key)
+		}
+	}
+	return skipped
+}
+
+// 이벤트가 속한 트랜잭션 (기록되지 않았으면 이벤트 자체)
+func eventTransaction(event *config.SQLEvent) string {
+	if event.Transaction != "" {
+		return event.Transaction
+	}
+	return eventKey(event)
+}
+
+// 가장 자주 변경된 행을 PK 기준으로 top개 출력 (잠금 경합 후보, --hot-rows-top)
+func writeHotRows(w io.Writer, events []config.SQLEvent, renderer *SQLExtractor, top int) {
+	if top <= 0 {
+		return
+	}
+
+	rows := make(map[string]*hotRow)
+	counted := 0
+	skipped := forEachChangedRow(events, renderer, func(event *config.SQLEvent, table, key string) {
+		row := rows[table+"\x00"+key]
+		if row == nil {
+			row = &hotRow{table: table, key: key, types: make(map[string]int), transactions: make(map[string]bool), first: event.Timestamp}
+			rows[table+"\x00"+key] = row
+		}
+		row.changes++
+		row.types[event.EventType]++
+		row.transactions[eventTransaction(event)] = true
+		if event.Timestamp.Before(row.first) {
+			row.first = event.Timestamp
+		}
+		if event.Timestamp.After(row.last) {
+			row.last = event.Timestamp
+		}
+		counted++
+	})
+
+	if counted == 0 {
+		fmt.Fprintln(w, T(">> 자주 변경된 행: PK를 알 수 있는 row 이벤트가 없습니다"))
+		return
+	}
+
+	sorted := make([]*hotRow, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, row)
+	}
+	slices.SortFunc(sorted, func(a, b *hotRow) int {
+		return cmp.Or(
+			cmp.Compare(b.changes, a.changes),
+			cmp.Compare(len(b.transactions), len(a.transactions)),
+			cmp.Compare(a.table, b.table),
+			cmp.Compare(a.key, b.key),
+		)
+	})
+	shown := sorted[:min(len(sorted), top)]
+
+	fmt.Fprintf(w, T(">> 자주 변경된 행 (상위 %d개 / 변경된 행 %d개, 변경 %d회 기준):\n"), len(shown), len(sorted), counted)
+	width := 0
+	for _, row := range shown {
+		width = max(width, len(row.table)+len(row.key)+1)
+	}
+	for _, row := range shown {
+		name := row.table + " " + row.key
+		fmt.Fprintf(w, T("   %s  %d회 (%s), 트랜잭션 %d개, %s ~ %s\n"),
+			name+strings.Repeat(" ", width-len(name)), row.changes, row.typeSummary(), len(row.transactions),
+			row.first.UTC().Format("2006-01-02 15:04:05"), row.last.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if skipped > 0 {
+		fmt.Fprintf(w, T("   PK를 알 수 없는 테이블의 행 %d개 제외\n"), skipped)
+	}
+}
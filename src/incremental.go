@@ -0,0 +1,162 @@
+package src
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 증분 분석 상태 파일 (--incremental --state-file)
+// 지난 실행이 읽은 마지막 위치를 기록하여 다음 실행은 그 다음 이벤트부터 분석
+type incrementalState struct {
+	Host      string    `json:"host"`
+	Port      int       `json:"port"`
+	File      string    `json:"file"`     // 지난 실행이 읽은 마지막 binary log 파일
+	Position  uint32    `json:"position"` // 그 파일에서 읽은 끝 위치 (다음 실행의 시작 위치)
+	EndTime   time.Time `json:"end_time"` // 지난 실행의 구간 끝 (서버 시각, 다음 실행의 구간 시작)
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func validateIncremental(cfg config.Config) error {
+	if !cfg.Incremental {
+		if cfg.StateFile != "" {
+			return fmt.Errorf("--state-file은 --incremental과 함께 사용해야 합니다")
+		}
+		return nil
+	}
+	if cfg.StateFile == "" {
+		return fmt.Errorf("--incremental은 --state-file이 필요합니다")
+	}
+	if cfg.Follow {
+		return fmt.Errorf("--incremental은 --follow와 함께 사용할 수 없습니다")
+	}
+	if len(cfg.BinlogFiles) > 0 {
+		return fmt.Errorf("--incremental은 --binlog-files와 함께 사용할 수 없습니다")
+	}
+	if cfg.StartPosition > 0 || cfg.StopPosition > 0 {
+		return fmt.Errorf("--start-position, --stop-position은 --incremental과 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+// 상태 파일을 읽어 지난 실행이 끝난 위치부터 이어 분석하도록 설정 (--incremental)
+// 상태 파일이 없으면 처음 실행이므로 --start-time부터 분석
+func (ba *BinlogAnalyzer) loadIncrementalState() error {
+	if !ba.Config.Incremental {
+		return nil
+	}
+
+	data, err := os.ReadFile(ba.Config.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		if ba.Config.StartTime.IsZero() {
+			return fmt.Errorf("상태 파일 %s가 없습니다. 처음 실행할 때는 --start-time을 지정하세요", ba.Config.StateFile)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("상태 파일 읽기 실패: %v", err)
+	}
+
+	var state incrementalState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("상태 파일 %s 형식 오류: %v", ba.Config.StateFile, err)
+	}
+	if state.File == "" {
+		return fmt.Errorf("상태 파일 %s에 위치가 기록되어 있지 않습니다", ba.Config.StateFile)
+	}
+	if state.Host != ba.Config.Host || state.Port != ba.Config.Port {
+		return fmt.Errorf("상태 파일 %s는 다른 서버(%s:%d)의 위치입니다", ba.Config.StateFile, state.Host, state.Port)
+	}
+
+	// 이어 읽는 구간은 기록한 위치로만 정함 (시작 시간은 결과 헤더에 보여 줄 뿐 이벤트를 거르지 않음, newExtractor 참고)
+	ba.resume = &state
+	ba.Config.StartTime = state.EndTime
+	ba.Config.StartPosition = state.Position
+	return nil
+}
+
+// 지난 실행이 읽은 마지막 파일부터 최신 파일까지 (그 파일이 purge되었으면 오류)
+func (ba *BinlogAnalyzer) resumeFiles(files []config.BinlogFile) ([]config.BinlogFile, error) {
+	for i, file := range files {
+		if file.Name == ba.resume.File {
+			return files[i:], nil
+		}
+	}
+	return nil, fmt.Errorf("상태 파일에 기록된 %s가 서버에 없습니다 (purge됨). 그 사이의 이벤트는 읽을 수 없으므로 상태 파일을 지우고 --start-time부터 다시 시작하세요", ba.resume.File)
+}
+
+// 이번 실행이 읽을 끝 위치 (파일 목록의 마지막 파일 크기)와 구간 끝 (서버 시각)
+// 목록을 가져온 뒤의 서버 시각을 쓰므로 그 위치까지의 이벤트는 모두 구간 안에 있음
+func (ba *BinlogAnalyzer) beginIncremental(files []config.BinlogFile) error {
+	if !ba.Config.Incremental || len(files) == 0 {
+		return nil
+	}
+
+	var now string
+	if err := ba.conn.QueryRow("SELECT UTC_TIMESTAMP(6)").Scan(&now); err != nil {
+		return fmt.Errorf("서버 시각 조회 실패: %v", err)
+	}
+	endTime, err := time.Parse("2006-01-02 15:04:05.999999", now)
+	if err != nil {
+		return fmt.Errorf("서버 시각 형식 오류: %q", now)
+	}
+	ba.Config.EndTime = endTime
+
+	last := files[len(files)-1]
+	ba.next = &incrementalState{
+		Host:     ba.Config.Host,
+		Port:     ba.Config.Port,
+		File:     last.Name,
+		Position: uint32(last.Size),
+		EndTime:  endTime,
+	}
+	return nil
+}
+
+// 끝까지 읽은 실행이면 다음 실행의 시작 위치 기록
+// 구간 앞부분이 purge된 경우는 남은 이벤트를 모두 읽었으므로 기록하고, --deadline으로 멈췄거나 실패하면 그대로 둠
+func (ba *BinlogAnalyzer) saveIncrementalState(runErr error) error {
+	if ba.next == nil {
+		return nil
+	}
+	if runErr != nil && (!errors.Is(runErr, ErrRangeNotCovered) || errors.Is(runErr, ErrDeadlineExceeded)) {
+		return nil
+	}
+	if ba.incomplete {
+		// 빠진 이벤트를 다음 실행이 다시 읽도록 이전 위치를 그대로 둠
+		logrus.Warnf("끝까지 읽지 못한 파일이 있어 상태 파일 %s를 갱신하지 않음 (다음 실행은 이전 위치부터 다시 읽음)", ba.Config.StateFile)
+		return nil
+	}
+
+	ba.next.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(ba.next, "", "  ")
+	if err != nil {
+		return fmt.Errorf("상태 파일 변환 실패: %v", err)
+	}
+
+	// 중간에 끊겨도 이전 상태가 남도록 임시 파일에 쓴 뒤 교체
+	tmp, err := os.CreateTemp(filepath.Dir(ba.Config.StateFile), filepath.Base(ba.Config.StateFile)+".*")
+	if err != nil {
+		return fmt.Errorf("상태 파일 저장 실패: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("상태 파일 저장 실패: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("상태 파일 저장 실패: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), ba.Config.StateFile); err != nil {
+		return fmt.Errorf("상태 파일 저장 실패: %v", err)
+	}
+	logrus.Infof(T("Incremental state saved to %s (next run starts at %s:%d)"), ba.Config.StateFile, ba.next.File, ba.next.Position)
+	return nil
+}
@@ -0,0 +1,381 @@
+package src
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 분석 작업 상태
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// 존재하지 않는 작업
+var errJobNotFound = errors.New("작업을 찾을 수 없습니다")
+
+// AnalysisRequest 분석 작업 요청 (POST /analyses)
+type AnalysisRequest struct {
+	Target            string   `json:"target,omitempty"`  // 접속 대상 (비어 있으면 기본 대상)
+	StartTime         string   `json:"start_time"`        // YYYY-MM-DD HH:MM:SS[.ffffff] (UTC)
+	EndTime           string   `json:"end_time"`          // YYYY-MM-DD HH:MM:SS[.ffffff] (UTC)
+	Backend           string   `json:"backend,omitempty"` // native, canal
+	ExcludeTableRegex string   `json:"exclude_table_regex,omitempty"`
+	Where             []string `json:"where,omitempty"`
+	FilterExpr        string   `json:"filter_expr,omitempty"`
+	MinExecTime       string   `json:"min_exec_time,omitempty"` // 예: 3s
+	OnlyErrors        bool     `json:"only_errors,omitempty"`
+	Replayable        bool     `json:"replayable,omitempty"`
+}
+
+// Job 분석 작업
+type Job struct {
+	ID         string          `json:"id"`
+	Request    AnalysisRequest `json:"request"`
+	User       string          `json:"user,omitempty"` // 제출한 사용자 (API 토큰 이름)
+	Status     string          `json:"status"`
+	Error      string          `json:"error,omitempty"`
+	Warnings   []string        `json:"warnings,omitempty"` // 결과가 불완전할 수 있는 이유 (빠진 파일, purge된 구간)
+	Progress   *progressRecord `json:"progress,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// 작업이 끝났는지 여부
+func (j *Job) finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCanceled
+}
+
+// JobQueue 분석 작업 큐
+// 동시에 실행하는 작업 수를 제한하고, 작업 상태와 결과는 디렉터리에 파일로 보관 (재시작 시 복원)
+type JobQueue struct {
+	dir       string
+	slots     chan struct{}
+	configFor func(AnalysisRequest) (config.Config, error) // 요청을 분석 설정으로 변환 (검증 포함)
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	saveMu sync.Mutex // 상태 파일 쓰기 직렬화
+}
+
+// NewJobQueue 작업 큐 생성 (dir에 남아 있는 작업 복원)
+func NewJobQueue(dir string, concurrency int, configFor func(AnalysisRequest) (config.Config, error)) (*JobQueue, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("작업 디렉터리 생성 실패: %v", err)
+	}
+
+	q := &JobQueue{
+		dir:       dir,
+		slots:     make(chan struct{}, concurrency),
+		configFor: configFor,
+		jobs:      make(map[string]*Job),
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// 저장된 작업 복원 (대기 중이던 작업은 다시 대기열에 넣고, 실행 중이던 작업은 실패로 처리)
+func (q *JobQueue) load() error {
+	paths, err := filepath.Glob(filepath.Join(q.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	var queued []*Job
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("작업 상태 읽기 실패: %v", err)
+		}
+		job := &Job{}
+		if err := json.Unmarshal(data, job); err != nil {
+			logrus.Warnf("작업 상태 파일 형식 오류 (무시): %s: %v", path, err)
+			continue
+		}
+		q.jobs[job.ID] = job
+
+		switch job.Status {
+		case JobQueued:
+			queued = append(queued, job)
+		case JobRunning:
+			q.finish(job, fmt.Errorf("서버 재시작으로 중단됨"))
+		}
+	}
+
+	// 제출 순서대로 다시 대기열에 넣음
+	sort.Slice(queued, func(i, j int) bool {
+		return queued[i].CreatedAt.Before(queued[j].CreatedAt)
+	})
+	for _, job := range queued {
+		cfg, err := q.configFor(job.Request)
+		if err != nil {
+			q.finish(job, err)
+			continue
+		}
+		q.start(job, cfg)
+	}
+	return nil
+}
+
+// Submit 작업 제출 (요청이 잘못되었으면 오류)
+func (q *JobQueue) Submit(req AnalysisRequest, user string) (Job, error) {
+	cfg, err := q.configFor(req)
+	if err != nil {
+		return Job{}, err
+	}
+
+	job := &Job{
+		ID:        newJobID(),
+		Request:   req,
+		User:      user,
+		Status:    JobQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	q.mu.Lock()
+	q.jobs[job.ID] = job
+	q.mu.Unlock()
+	q.save(job)
+
+	q.start(job, cfg)
+	return q.snapshot(job), nil
+}
+
+// 작업 실행 고루틴 시작 (실행 슬롯이 빌 때까지 대기)
+func (q *JobQueue) start(job *Job, cfg config.Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q.mu.Lock()
+	job.cancel = cancel
+	q.mu.Unlock()
+
+	go func() {
+		defer cancel()
+
+		select {
+		case q.slots <- struct{}{}:
+			defer func() { <-q.slots }()
+		case <-ctx.Done():
+			q.finish(job, ctx.Err())
+			return
+		}
+
+		q.mu.Lock()
+		now := time.Now().UTC()
+		job.Status = JobRunning
+		job.StartedAt = &now
+		q.mu.Unlock()
+		q.save(job)
+
+		q.finish(job, q.run(ctx, job, cfg))
+	}()
+}
+
+// 분석 실행 (결과는 작업 디렉터리의 <id>.out, 웹 UI 검색용 이벤트 목록은 <id>.events.jsonl)
+func (q *JobQueue) run(ctx context.Context, job *Job, cfg config.Config) error {
+	cfg.OutputFile = q.ResultPath(job.ID)
+	cfg.AppendOutput = false
+	cfg.OverwriteOutput = true // 작업 결과 파일은 작업 디렉터리가 관리
+	cfg.Verbose = 0
+	cfg.Sink = "" // 작업 결과는 항상 작업 디렉터리에 보관
+	cfg.SQLiteFile = ""
+	cfg.Compress = ""       // 웹 UI가 결과 파일을 그대로 읽음
+	cfg.Format = FormatText // 결과는 text/plain .sql로 내려줌
+	cfg.OutputTemplate = ""
+	cfg.Hook = "" // 서버의 --hook 명령을 웹 요청마다 실행하지 않음
+	cfg.OutputRotateSize = 0
+	cfg.OutputRotateEvents = 0
+	cfg.SplitBy = ""
+	cfg.Timeline = ""
+	cfg.CapacityReport = ""
+	cfg.ConfirmOver = 0 // 웹 UI에서 요청한 작업은 확인할 터미널이 없음
+	cfg.Incremental = false
+	cfg.StateFile = ""
+	cfg.PushgatewayURL = ""
+	cfg.SummaryJSON = ""
+	cfg.HistoryDB = ""
+
+	analyzer := &BinlogAnalyzer{Config: cfg, messages: io.Discard}
+	analyzer.onResults = func(events []config.SQLEvent) error {
+		return writeEventList(q.EventsPath(job.ID), events)
+	}
+	analyzer.progress = startProgressReporter(func(record progressRecord) {
+		q.mu.Lock()
+		job.Progress = &record
+		q.mu.Unlock()
+	})
+	defer analyzer.progress.Close()
+
+	err := analyzer.Analyze(ctx)
+	q.mu.Lock()
+	job.Warnings = analyzer.warnings
+	q.mu.Unlock()
+	return err
+}
+
+// 작업 종료 상태 기록
+func (q *JobQueue) finish(job *Job, err error) {
+	q.mu.Lock()
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	switch {
+	case err == nil, IsIncomplete(err):
+		// 구간 앞부분이 purge되었거나 --deadline이 지난 경우도 결과는 있으므로 성공 (warnings에 기록됨)
+		job.Status = JobSucceeded
+	case errors.Is(err, context.Canceled):
+		job.Status = JobCanceled
+	default:
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+	q.mu.Unlock()
+	q.save(job)
+}
+
+// Get 작업 조회
+func (q *JobQueue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, errJobNotFound
+	}
+	return *job, nil
+}
+
+// List 전체 작업 (최근 제출 순)
+func (q *JobQueue) List() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Cancel 대기 중이거나 실행 중인 작업은 취소하고, 끝난 작업은 상태와 결과를 삭제
+func (q *JobQueue) Cancel(id string) (Job, error) {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return Job{}, errJobNotFound
+	}
+
+	if !job.finished() {
+		if job.cancel != nil {
+			job.cancel()
+		}
+		snapshot := *job
+		q.mu.Unlock()
+		return snapshot, nil
+	}
+
+	delete(q.jobs, id)
+	snapshot := *job
+	q.mu.Unlock()
+
+	os.Remove(q.statePath(id))
+	os.Remove(q.ResultPath(id))
+	os.Remove(q.EventsPath(id))
+	return snapshot, nil
+}
+
+// ResultPath 작업 결과 파일 경로
+func (q *JobQueue) ResultPath(id string) string {
+	return filepath.Join(q.dir, id+".out")
+}
+
+// EventsPath 작업 결과 이벤트 목록 (JSON lines) 경로
+func (q *JobQueue) EventsPath(id string) string {
+	return filepath.Join(q.dir, id+".events.jsonl")
+}
+
+func (q *JobQueue) statePath(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
+
+func (q *JobQueue) snapshot(job *Job) Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return *job
+}
+
+// 작업 상태 저장 (임시 파일에 쓴 뒤 이름 변경)
+func (q *JobQueue) save(job *Job) {
+	q.saveMu.Lock()
+	defer q.saveMu.Unlock()
+
+	q.mu.Lock()
+	data, err := json.MarshalIndent(job, "", "  ")
+	q.mu.Unlock()
+	if err != nil {
+		logrus.Warnf("작업 %s 상태 저장 실패: %v", job.ID, err)
+		return
+	}
+
+	path := q.statePath(job.ID)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		logrus.Warnf("작업 %s 상태 저장 실패: %v", job.ID, err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		logrus.Warnf("작업 %s 상태 저장 실패: %v", job.ID, err)
+	}
+}
+
+// 이벤트 목록을 JSON lines로 저장
+func writeEventList(path string, events []config.SQLEvent) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for i := range events {
+		if err := encoder.Encode(&events[i]); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// 작업 ID (제출 시각 + 난수, 정렬하면 제출 순서)
+func newJobID() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,327 @@
+package src
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+// MySQL JSON 바이너리 형식의 값 종류 (sql-common/json_binary.h)
+const (
+	jsonbSmallObject = 0x00
+	jsonbLargeObject = 0x01
+	jsonbSmallArray  = 0x02
+	jsonbLargeArray  = 0x03
+	jsonbLiteral     = 0x04
+	jsonbInt16       = 0x05
+	jsonbUint16      = 0x06
+	jsonbInt32       = 0x07
+	jsonbUint32      = 0x08
+	jsonbInt64       = 0x09
+	jsonbUint64      = 0x0a
+	jsonbDouble      = 0x0b
+	jsonbString      = 0x0c
+	jsonbOpaque      = 0x0f
+)
+
+// JSON 바이너리 값을 JSON 텍스트로 변환 (객체 키는 저장된 순서 그대로)
+func decodeJSONBinary(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	if err := writeJSONBinaryValue(&buf, data[0], data[1:]); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSONBinaryValue(buf *bytes.Buffer, tp byte, data []byte) error {
+	switch tp {
+	case jsonbSmallObject, jsonbLargeObject, jsonbSmallArray, jsonbLargeArray:
+		return writeJSONBinaryContainer(buf, data, tp == jsonbSmallObject || tp == jsonbSmallArray, tp <= jsonbLargeObject)
+	case jsonbLiteral:
+		if len(data) < 1 {
+			return fmt.Errorf("JSON 리터럴이 잘렸습니다")
+		}
+		switch data[0] {
+		case 0x00:
+			buf.WriteString("null")
+		case 0x01:
+			buf.WriteString("true")
+		case 0x02:
+			buf.WriteString("false")
+		default:
+			return fmt.Errorf("알 수 없는 JSON 리터럴: %d", data[0])
+		}
+		return nil
+	case jsonbInt16, jsonbUint16, jsonbInt32, jsonbUint32, jsonbInt64, jsonbUint64, jsonbDouble:
+		return writeJSONBinaryNumber(buf, tp, data)
+	case jsonbString:
+		length, n, err := jsonBinaryVarLength(data)
+		if err != nil {
+			return err
+		}
+		if n+length > len(data) {
+			return fmt.Errorf("JSON 문자열이 잘렸습니다")
+		}
+		writeJSONString(buf, string(data[n:n+length]))
+		return nil
+	case jsonbOpaque:
+		return writeJSONBinaryOpaque(buf, data)
+	default:
+		return fmt.Errorf("알 수 없는 JSON 값 종류: %d", tp)
+	}
+}
+
+func writeJSONBinaryNumber(buf *bytes.Buffer, tp byte, data []byte) error {
+	sizes := map[byte]int{jsonbInt16: 2, jsonbUint16: 2, jsonbInt32: 4, jsonbUint32: 4, jsonbInt64: 8, jsonbUint64: 8, jsonbDouble: 8}
+	if len(data) < sizes[tp] {
+		return fmt.Errorf("JSON 숫자가 잘렸습니다")
+	}
+	switch tp {
+	case jsonbInt16:
+		buf.WriteString(strconv.FormatInt(int64(int16(binary.LittleEndian.Uint16(data))), 10))
+	case jsonbUint16:
+		buf.WriteString(strconv.FormatUint(uint64(binary.LittleEndian.Uint16(data)), 10))
+	case jsonbInt32:
+		buf.WriteString(strconv.FormatInt(int64(int32(binary.LittleEndian.Uint32(data))), 10))
+	case jsonbUint32:
+		buf.WriteString(strconv.FormatUint(uint64(binary.LittleEndian.Uint32(data)), 10))
+	case jsonbInt64:
+		buf.WriteString(strconv.FormatInt(int64(binary.LittleEndian.Uint64(data)), 10))
+	case jsonbUint64:
+		buf.WriteString(strconv.FormatUint(binary.LittleEndian.Uint64(data), 10))
+	case jsonbDouble:
+		buf.WriteString(strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(data)), 'g', -1, 64))
+	}
+	return nil
+}
+
+// 객체/배열: 개수, 크기, 키 항목, 값 항목 순서 (작은 형식은 2바이트, 큰 형식은 4바이트 오프셋)
+func writeJSONBinaryContainer(buf *bytes.Buffer, data []byte, small, object bool) error {
+	offsetSize := 4
+	if small {
+		offsetSize = 2
+	}
+	readOffset := func(pos int) int {
+		if small {
+			return int(binary.LittleEndian.Uint16(data[pos:]))
+		}
+		return int(binary.LittleEndian.Uint32(data[pos:]))
+	}
+	if len(data) < 2*offsetSize {
+		return fmt.Errorf("JSON 컨테이너가 잘렸습니다")
+	}
+	count := readOffset(0)
+	size := readOffset(offsetSize)
+	keyEntrySize := offsetSize + 2
+	valueEntrySize := offsetSize + 1
+	header := 2*offsetSize + count*valueEntrySize
+	if object {
+		header += count * keyEntrySize
+	}
+	if size > len(data) || header > size {
+		return fmt.Errorf("JSON 컨테이너 크기 오류 (size %d, header %d, data %d)", size, header, len(data))
+	}
+	data = data[:size]
+
+	open, close := byte('['), byte(']')
+	if object {
+		open, close = '{', '}'
+	}
+	buf.WriteByte(open)
+	for i := range count {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if object {
+			entry := 2*offsetSize + i*keyEntrySize
+			keyOffset := readOffset(entry)
+			keyLength := int(binary.LittleEndian.Uint16(data[entry+offsetSize:]))
+			if keyOffset+keyLength > len(data) {
+				return fmt.Errorf("JSON 키가 잘렸습니다")
+			}
+			writeJSONString(buf, string(data[keyOffset:keyOffset+keyLength]))
+			buf.WriteString(": ")
+		}
+
+		entry := 2*offsetSize + i*valueEntrySize
+		if object {
+			entry += count * keyEntrySize
+		}
+		tp := data[entry]
+		// 작은 값은 값 항목 안에 바로 저장
+		inline := tp == jsonbLiteral || tp == jsonbInt16 || tp == jsonbUint16 ||
+			(!small && (tp == jsonbInt32 || tp == jsonbUint32))
+		if inline {
+			if err := writeJSONBinaryValue(buf, tp, data[entry+1:entry+valueEntrySize]); err != nil {
+				return err
+			}
+			continue
+		}
+		valueOffset := readOffset(entry + 1)
+		if valueOffset >= len(data) {
+			return fmt.Errorf("JSON 값 오프셋 오류: %d", valueOffset)
+		}
+		if err := writeJSONBinaryValue(buf, tp, data[valueOffset:]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(close)
+	return nil
+}
+
+// 날짜, 시간, DECIMAL 등 MySQL 타입 값 (그 밖의 타입은 mysql 클라이언트처럼 base64:typeN:...)
+func writeJSONBinaryOpaque(buf *bytes.Buffer, data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("JSON opaque 값이 잘렸습니다")
+	}
+	tp := data[0]
+	length, n, err := jsonBinaryVarLength(data[1:])
+	if err != nil {
+		return err
+	}
+	if 1+n+length > len(data) {
+		return fmt.Errorf("JSON opaque 값이 잘렸습니다")
+	}
+	value := data[1+n : 1+n+length]
+
+	switch tp {
+	case mysql.MYSQL_TYPE_NEWDECIMAL:
+		if len(value) < 2 {
+			return fmt.Errorf("JSON DECIMAL 값이 잘렸습니다")
+		}
+		decimal, err := decodeBinaryDecimal(value[2:], int(value[0]), int(value[1]))
+		if err != nil {
+			return err
+		}
+		buf.WriteString(decimal)
+	case mysql.MYSQL_TYPE_TIME, mysql.MYSQL_TYPE_DATE, mysql.MYSQL_TYPE_DATETIME, mysql.MYSQL_TYPE_TIMESTAMP:
+		if len(value) < 8 {
+			return fmt.Errorf("JSON 시간 값이 잘렸습니다")
+		}
+		writeJSONString(buf, formatPackedTime(tp, int64(binary.LittleEndian.Uint64(value))))
+	default:
+		writeJSONString(buf, fmt.Sprintf("base64:type%d:%s", tp, base64.StdEncoding.EncodeToString(value)))
+	}
+	return nil
+}
+
+// 7비트씩 이어지는 가변 길이 (최대 5바이트)
+func jsonBinaryVarLength(data []byte) (int, int, error) {
+	var length uint64
+	for i := 0; i < len(data) && i < 5; i++ {
+		length |= uint64(data[i]&0x7f) << (7 * i)
+		if data[i]&0x80 == 0 {
+			if length > math.MaxUint32 {
+				break
+			}
+			return int(length), i + 1, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("JSON 가변 길이 오류")
+}
+
+// 내부 packed 형식의 TIME/DATE/DATETIME/TIMESTAMP
+func formatPackedTime(tp byte, packed int64) string {
+	sign := ""
+	if packed < 0 {
+		sign = "-"
+		packed = -packed
+	}
+	frac := packed % (1 << 24)
+	intPart := packed >> 24
+
+	if tp == mysql.MYSQL_TYPE_TIME {
+		hour := (intPart >> 12) % (1 << 10)
+		minute := (intPart >> 6) % (1 << 6)
+		second := intPart % (1 << 6)
+		return fmt.Sprintf("%s%02d:%02d:%02d.%06d", sign, hour, minute, second, frac)
+	}
+
+	ymd := intPart >> 17
+	ym := ymd >> 5
+	hms := intPart % (1 << 17)
+	date := fmt.Sprintf("%04d-%02d-%02d", ym/13, ym%13, ymd%(1<<5))
+	if tp == mysql.MYSQL_TYPE_DATE {
+		return date
+	}
+	return fmt.Sprintf("%s %02d:%02d:%02d.%06d", date, hms>>12, (hms>>6)%(1<<6), hms%(1<<6), frac)
+}
+
+// MySQL DECIMAL 바이너리 형식 (9자리씩 4바이트, 남은 자리는 필요한 만큼의 바이트, 음수는 모든 비트 반전)
+func decodeBinaryDecimal(data []byte, precision, scale int) (string, error) {
+	const digitsPerGroup = 9
+	compressedBytes := [digitsPerGroup + 1]int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
+
+	integral := precision - scale
+	fullIntegral, partialIntegral := integral/digitsPerGroup, integral%digitsPerGroup
+	fullFraction, partialFraction := scale/digitsPerGroup, scale%digitsPerGroup
+	size := compressedBytes[partialIntegral] + fullIntegral*4 + fullFraction*4 + compressedBytes[partialFraction]
+	if integral < 0 || size > len(data) || size == 0 {
+		return "", fmt.Errorf("JSON DECIMAL 크기 오류 (precision %d, scale %d)", precision, scale)
+	}
+
+	buf := bytes.Clone(data[:size])
+	negative := buf[0]&0x80 == 0
+	buf[0] ^= 0x80
+	if negative {
+		for i := range buf {
+			buf[i] ^= 0xff
+		}
+	}
+
+	pos := 0
+	read := func(n int) uint64 {
+		var v uint64
+		for _, b := range buf[pos : pos+n] {
+			v = v<<8 | uint64(b)
+		}
+		pos += n
+		return v
+	}
+
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	var digits strings.Builder
+	if n := compressedBytes[partialIntegral]; n > 0 {
+		digits.WriteString(strconv.FormatUint(read(n), 10))
+	}
+	for range fullIntegral {
+		fmt.Fprintf(&digits, "%09d", read(4))
+	}
+	integer := strings.TrimLeft(digits.String(), "0")
+	if integer == "" {
+		integer = "0"
+	}
+	sb.WriteString(integer)
+
+	if scale > 0 {
+		sb.WriteByte('.')
+		for range fullFraction {
+			fmt.Fprintf(&sb, "%09d", read(4))
+		}
+		if n := compressedBytes[partialFraction]; n > 0 {
+			fmt.Fprintf(&sb, "%0*d", partialFraction, read(n))
+		}
+	}
+	return sb.String(), nil
+}
+
+// HTML 이스케이프 없이 JSON 문자열 출력
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode가 붙이는 줄바꿈
+}
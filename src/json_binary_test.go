@@ -0,0 +1,153 @@
+package src
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+// 테스트용 JSON 바이너리 값 (종류 1바이트 + 데이터)
+func jsonbStringValue(s string) []byte {
+	return append([]byte{jsonbString, byte(len(s))}, s...)
+}
+
+func jsonbIntValue(v int16) []byte {
+	return binary.LittleEndian.AppendUint16([]byte{jsonbInt16}, uint16(v))
+}
+
+func jsonbLiteralValue(v byte) []byte {
+	return []byte{jsonbLiteral, v}
+}
+
+// 작은 형식(2바이트 오프셋)의 객체나 배열 (keys가 nil이면 배열)
+// literal과 int16은 값 항목 안에, 그 밖의 값은 항목 뒤에 차례로 저장
+func jsonbContainer(keys []string, values ...[]byte) []byte {
+	object := keys != nil
+	count := len(values)
+	header := 4 + count*3
+	if object {
+		header += count * 4
+	}
+
+	var keyData, valueData []byte
+	keyEntries := make([]byte, 0, count*4)
+	valueEntries := make([]byte, 0, count*3)
+	offset := header
+	for _, key := range keys {
+		keyEntries = binary.LittleEndian.AppendUint16(keyEntries, uint16(offset+len(keyData)))
+		keyEntries = binary.LittleEndian.AppendUint16(keyEntries, uint16(len(key)))
+		keyData = append(keyData, key...)
+	}
+	offset += len(keyData)
+	for _, value := range values {
+		valueEntries = append(valueEntries, value[0])
+		if value[0] == jsonbLiteral || value[0] == jsonbInt16 {
+			valueEntries = append(valueEntries, append(value[1:], 0)[:2]...)
+			continue
+		}
+		valueEntries = binary.LittleEndian.AppendUint16(valueEntries, uint16(offset+len(valueData)))
+		valueData = append(valueData, value[1:]...)
+	}
+
+	data := binary.LittleEndian.AppendUint16(nil, uint16(count))
+	data = binary.LittleEndian.AppendUint16(data, uint16(header+len(keyData)+len(valueData)))
+	data = append(data, keyEntries...)
+	data = append(data, valueEntries...)
+	data = append(data, keyData...)
+	data = append(data, valueData...)
+
+	tp := byte(jsonbSmallArray)
+	if object {
+		tp = jsonbSmallObject
+	}
+	return append([]byte{tp}, data...)
+}
+
+// MySQL 타입 값 (opaque)
+func jsonbOpaqueValue(fieldType byte, value []byte) []byte {
+	return append([]byte{jsonbOpaque, fieldType, byte(len(value))}, value...)
+}
+
+// 내부 packed 형식의 DATETIME
+func packedDatetime(year, month, day, hour, minute, second, micro int64) []byte {
+	ymd := (year*13+month)<<5 | day
+	hms := hour<<12 | minute<<6 | second
+	return binary.LittleEndian.AppendUint64(nil, uint64((ymd<<17|hms)<<24|micro))
+}
+
+func TestDecodeJSONBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, `null`},
+		{"literals", jsonbContainer(nil, jsonbLiteralValue(0x00), jsonbLiteralValue(0x01), jsonbLiteralValue(0x02)), `[null, true, false]`},
+		{"negative int16", jsonbIntValue(-42), `-42`},
+		{"uint32", binary.LittleEndian.AppendUint32([]byte{jsonbUint32}, 4000000000), `4000000000`},
+		{"double", binary.LittleEndian.AppendUint64([]byte{jsonbDouble}, 0x3ff8000000000000), `1.5`},
+		{"string without html escape", jsonbStringValue(`<a href="x">&</a>`), `"<a href=\"x\">&</a>"`},
+		{"object keeps key order", jsonbContainer([]string{"name", "age"}, jsonbStringValue("kim"), jsonbIntValue(30)), `{"name": "kim", "age": 30}`},
+		{
+			"nested object and array",
+			jsonbContainer([]string{"id", "tags"}, jsonbIntValue(1), jsonbContainer(nil, jsonbStringValue("a"), jsonbStringValue("b"))),
+			`{"id": 1, "tags": ["a", "b"]}`,
+		},
+		{"empty array", jsonbContainer(nil), `[]`},
+		{"decimal", jsonbOpaqueValue(mysql.MYSQL_TYPE_NEWDECIMAL, []byte{5, 2, 0x80, 0x7b, 0x2d}), `123.45`},
+		{"negative decimal", jsonbOpaqueValue(mysql.MYSQL_TYPE_NEWDECIMAL, []byte{5, 2, 0x7f, 0x84, 0xd2}), `-123.45`},
+		{"datetime", jsonbOpaqueValue(mysql.MYSQL_TYPE_DATETIME, packedDatetime(2024, 1, 15, 10, 12, 3, 500)), `"2024-01-15 10:12:03.000500"`},
+		{"other opaque type", jsonbOpaqueValue(mysql.MYSQL_TYPE_BLOB, []byte("hi")), `"base64:type252:aGk="`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeJSONBinary(tt.data)
+			if err != nil {
+				t.Fatalf("decodeJSONBinary() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("decodeJSONBinary() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONBinaryErrors(t *testing.T) {
+	object := jsonbContainer([]string{"name"}, jsonbStringValue("kim"))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"unknown type", []byte{0x0d}},
+		{"unknown literal", jsonbLiteralValue(0x03)},
+		{"truncated number", []byte{jsonbInt64, 1, 2}},
+		{"truncated string", []byte{jsonbString, 10, 'a'}},
+		{"truncated container", object[:len(object)-2]},
+		{"container header larger than size", []byte{jsonbSmallArray, 5, 0, 4, 0}},
+		{"bad var length", []byte{jsonbString, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := decodeJSONBinary(tt.data); err == nil {
+				t.Errorf("decodeJSONBinary() = %s, want error", got)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONBinaryLargeContainer(t *testing.T) {
+	// 큰 형식(4바이트 오프셋)의 배열 [7]: int16은 값 항목 안에 저장
+	data := []byte{jsonbLargeArray}
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 13)
+	data = append(data, jsonbInt16, 7, 0, 0, 0)
+	got, err := decodeJSONBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, []byte(`[7]`)) {
+		t.Errorf("decodeJSONBinary() = %s, want [7]", got)
+	}
+}
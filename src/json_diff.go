@@ -0,0 +1,166 @@
+package src
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// JSON diff 연산 (sql/json_diff.h)
+const (
+	jsonDiffReplace = "replace"
+	jsonDiffInsert  = "insert"
+	jsonDiffRemove  = "remove"
+)
+
+var jsonDiffOperations = map[replication.JsonDiffOperation]string{
+	replication.JsonDiffOperationReplace: jsonDiffReplace,
+	replication.JsonDiffOperationInsert:  jsonDiffInsert,
+	replication.JsonDiffOperationRemove:  jsonDiffRemove,
+}
+
+// JSON 컬럼의 경로 하나에 대한 변경 (Value는 JSON 텍스트, remove면 없음)
+type jsonDiff struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// PARTIAL_UPDATE_ROWS_EVENT의 after 이미지에서 문서 전체 대신 기록된 JSON 컬럼의 변경 목록
+// (binlog_row_value_options=PARTIAL_JSON)
+type jsonPartialUpdate []jsonDiff
+
+// 변경을 적용하는 SQL 함수 (replace와 객체 멤버 insert는 JSON_SET, 배열 insert는 JSON_ARRAY_INSERT)
+func (d jsonDiff) function() string {
+	switch {
+	case d.Op == jsonDiffRemove:
+		return "JSON_REMOVE"
+	case d.Op == jsonDiffInsert && strings.HasSuffix(d.Path, "]"):
+		return "JSON_ARRAY_INSERT"
+	default:
+		return "JSON_SET"
+	}
+}
+
+// column에 변경을 차례로 적용하는 식 (같은 함수가 이어지면 한 호출로 묶음, literal은 경로와 값의 SQL 표현)
+func (u jsonPartialUpdate) expression(column string, literal func(interface{}) string) string {
+	expr := column
+	for i := 0; i < len(u); {
+		function := u[i].function()
+		args := []string{expr}
+		for ; i < len(u) && u[i].function() == function; i++ {
+			args = append(args, literal(u[i].Path))
+			if u[i].Op != jsonDiffRemove {
+				args = append(args, fmt.Sprintf("CAST(%s AS JSON)", literal(string(u[i].Value))))
+			}
+		}
+		expr = fmt.Sprintf("%s(%s)", function, strings.Join(args, ", "))
+	}
+	return expr
+}
+
+// after 이미지의 부분 JSON 값을 변경 목록으로 바꿈
+// go-mysql은 컬럼마다 첫 diff만 디코딩하므로 원본 이벤트에서 그 diff로 시작하는 목록을 찾아 모두 읽고,
+// 찾지 못하면 첫 diff만 사용
+func decodePartialJSON(raw []byte, rows [][]interface{}) {
+	cursor := 0
+	for i := 1; i < len(rows); i += 2 {
+		for j, value := range rows[i] {
+			first, ok := value.(*replication.JsonDiff)
+			if !ok {
+				continue
+			}
+			diffs, end := findJSONDiffs(raw, cursor, first)
+			if diffs == nil {
+				diffs = []jsonDiff{{Op: jsonDiffOperations[first.Op], Path: first.Path}}
+				if first.Op != replication.JsonDiffOperationRemove {
+					diffs[0].Value = json.RawMessage(first.Value)
+				}
+			} else {
+				cursor = end
+			}
+			rows[i][j] = jsonPartialUpdate(diffs)
+		}
+	}
+}
+
+// raw의 from 이후에서 first로 시작하는 diff 목록 (앞의 4바이트가 목록 전체 길이)
+func findJSONDiffs(raw []byte, from int, first *replication.JsonDiff) ([]jsonDiff, int) {
+	prefix := append([]byte{byte(first.Op)}, mysql.PutLengthEncodedInt(uint64(len(first.Path)))...)
+	prefix = append(prefix, first.Path...)
+
+	for offset := from; offset < len(raw); {
+		k := bytes.Index(raw[offset:], prefix)
+		if k < 0 {
+			return nil, 0
+		}
+		start := offset + k
+		offset = start + 1
+		if start < 4 {
+			continue
+		}
+		end := start + int(binary.LittleEndian.Uint32(raw[start-4:]))
+		if end > len(raw) {
+			continue
+		}
+		if diffs, err := readJSONDiffs(raw[start:end]); err == nil {
+			return diffs, end
+		}
+	}
+	return nil, 0
+}
+
+// diff 목록 (연산 1바이트, 경로 길이와 경로, remove가 아니면 값 길이와 JSON 바이너리 값)
+func readJSONDiffs(data []byte) ([]jsonDiff, error) {
+	var diffs []jsonDiff
+	for pos := 0; pos < len(data); {
+		op, ok := jsonDiffOperations[replication.JsonDiffOperation(data[pos])]
+		if !ok {
+			return nil, fmt.Errorf("알 수 없는 JSON diff 연산: %d", data[pos])
+		}
+		pos++
+
+		path, n, err := readLengthEncoded(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+		pos += n
+		diff := jsonDiff{Op: op, Path: string(path)}
+
+		if op != jsonDiffRemove {
+			value, n, err := readLengthEncoded(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			pos += n
+			text, err := decodeJSONBinary(value)
+			if err != nil {
+				return nil, err
+			}
+			diff.Value = text
+		}
+		diffs = append(diffs, diff)
+	}
+	if len(diffs) == 0 {
+		return nil, fmt.Errorf("JSON diff가 없습니다")
+	}
+	return diffs, nil
+}
+
+// 길이(length-encoded integer)가 앞에 붙은 바이트열과 읽은 크기
+func readLengthEncoded(data []byte) ([]byte, int, error) {
+	header := map[byte]int{0xfc: 3, 0xfd: 4, 0xfe: 9}
+	if len(data) == 0 || data[0] == 0xfb || len(data) < header[data[0]] {
+		return nil, 0, fmt.Errorf("JSON diff가 잘렸습니다")
+	}
+	length, _, n := mysql.LengthEncodedInt(data)
+	if uint64(len(data)-n) < length {
+		return nil, 0, fmt.Errorf("JSON diff가 잘렸습니다")
+	}
+	return data[n : n+int(length)], n + int(length), nil
+}
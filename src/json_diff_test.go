@@ -0,0 +1,166 @@
+package src
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// 테스트용 JSON diff (연산, 경로, remove가 아니면 JSON 바이너리 값)
+func jsonDiffBytes(op replication.JsonDiffOperation, path string, value []byte) []byte {
+	data := append([]byte{byte(op)}, mysql.PutLengthEncodedInt(uint64(len(path)))...)
+	data = append(data, path...)
+	if op != replication.JsonDiffOperationRemove {
+		data = append(data, mysql.PutLengthEncodedInt(uint64(len(value)))...)
+		data = append(data, value...)
+	}
+	return data
+}
+
+func testLiteral(v interface{}) string {
+	return fmt.Sprintf("'%v'", v)
+}
+
+func TestJSONDiffs(t *testing.T) {
+	replace := replication.JsonDiffOperationReplace
+	insert := replication.JsonDiffOperationInsert
+	remove := replication.JsonDiffOperationRemove
+
+	tests := []struct {
+		name  string
+		diffs [][]byte
+		want  []jsonDiff
+		expr  string
+	}{
+		{
+			name:  "replace object member",
+			diffs: [][]byte{jsonDiffBytes(replace, "$.name", jsonbStringValue("lee"))},
+			want:  []jsonDiff{{Op: jsonDiffReplace, Path: "$.name", Value: []byte(`"lee"`)}},
+			expr:  `JSON_SET(doc, '$.name', CAST('"lee"' AS JSON))`,
+		},
+		{
+			name:  "replace array element",
+			diffs: [][]byte{jsonDiffBytes(replace, "$.tags[1]", jsonbIntValue(7))},
+			want:  []jsonDiff{{Op: jsonDiffReplace, Path: "$.tags[1]", Value: []byte(`7`)}},
+			expr:  `JSON_SET(doc, '$.tags[1]', CAST('7' AS JSON))`,
+		},
+		{
+			name:  "insert object member",
+			diffs: [][]byte{jsonDiffBytes(insert, "$.address", jsonbContainer([]string{"city"}, jsonbStringValue("Seoul")))},
+			want:  []jsonDiff{{Op: jsonDiffInsert, Path: "$.address", Value: []byte(`{"city": "Seoul"}`)}},
+			expr:  `JSON_SET(doc, '$.address', CAST('{"city": "Seoul"}' AS JSON))`,
+		},
+		{
+			name:  "insert array element",
+			diffs: [][]byte{jsonDiffBytes(insert, "$.tags[0]", jsonbStringValue("new"))},
+			want:  []jsonDiff{{Op: jsonDiffInsert, Path: "$.tags[0]", Value: []byte(`"new"`)}},
+			expr:  `JSON_ARRAY_INSERT(doc, '$.tags[0]', CAST('"new"' AS JSON))`,
+		},
+		{
+			name:  "remove object member",
+			diffs: [][]byte{jsonDiffBytes(remove, "$.old", nil)},
+			want:  []jsonDiff{{Op: jsonDiffRemove, Path: "$.old"}},
+			expr:  `JSON_REMOVE(doc, '$.old')`,
+		},
+		{
+			name:  "remove array element",
+			diffs: [][]byte{jsonDiffBytes(remove, "$.tags[2]", nil)},
+			want:  []jsonDiff{{Op: jsonDiffRemove, Path: "$.tags[2]"}},
+			expr:  `JSON_REMOVE(doc, '$.tags[2]')`,
+		},
+		{
+			name: "consecutive diffs of one function are grouped",
+			diffs: [][]byte{
+				jsonDiffBytes(replace, "$.a", jsonbIntValue(1)),
+				jsonDiffBytes(insert, "$.b", jsonbLiteralValue(0x01)),
+				jsonDiffBytes(remove, "$.c", nil),
+				jsonDiffBytes(remove, "$.d[0]", nil),
+				jsonDiffBytes(insert, "$.e[1]", jsonbLiteralValue(0x00)),
+			},
+			want: []jsonDiff{
+				{Op: jsonDiffReplace, Path: "$.a", Value: []byte(`1`)},
+				{Op: jsonDiffInsert, Path: "$.b", Value: []byte(`true`)},
+				{Op: jsonDiffRemove, Path: "$.c"},
+				{Op: jsonDiffRemove, Path: "$.d[0]"},
+				{Op: jsonDiffInsert, Path: "$.e[1]", Value: []byte(`null`)},
+			},
+			expr: `JSON_ARRAY_INSERT(JSON_REMOVE(JSON_SET(doc, '$.a', CAST('1' AS JSON), '$.b', CAST('true' AS JSON)), '$.c', '$.d[0]'), '$.e[1]', CAST('null' AS JSON))`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data []byte
+			for _, diff := range tt.diffs {
+				data = append(data, diff...)
+			}
+			got, err := readJSONDiffs(data)
+			if err != nil {
+				t.Fatalf("readJSONDiffs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readJSONDiffs() = %+v, want %+v", got, tt.want)
+			}
+			if expr := jsonPartialUpdate(got).expression("doc", testLiteral); expr != tt.expr {
+				t.Errorf("expression() = %s, want %s", expr, tt.expr)
+			}
+		})
+	}
+}
+
+func TestReadJSONDiffsErrors(t *testing.T) {
+	valid := jsonDiffBytes(replication.JsonDiffOperationReplace, "$.name", jsonbStringValue("lee"))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown operation", append([]byte{9}, valid[1:]...)},
+		{"truncated path", valid[:4]},
+		{"truncated value", valid[:len(valid)-1]},
+		{"bad value", jsonDiffBytes(replication.JsonDiffOperationReplace, "$.a", []byte{0x0d})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := readJSONDiffs(tt.data); err == nil {
+				t.Errorf("readJSONDiffs() = %+v, want error", got)
+			}
+		})
+	}
+}
+
+func TestDecodePartialJSON(t *testing.T) {
+	// go-mysql이 첫 diff만 디코딩한 컬럼은 원본 이벤트의 diff 목록 전체로 바꿈
+	diffs := append(jsonDiffBytes(replication.JsonDiffOperationReplace, "$.name", jsonbStringValue("lee")),
+		jsonDiffBytes(replication.JsonDiffOperationRemove, "$.old", nil)...)
+	raw := []byte{0xaa, 0xbb}
+	raw = binary.LittleEndian.AppendUint32(raw, uint32(len(diffs)))
+	raw = append(raw, diffs...)
+
+	first := &replication.JsonDiff{Op: replication.JsonDiffOperationReplace, Path: "$.name", Value: `"lee"`}
+	missing := &replication.JsonDiff{Op: replication.JsonDiffOperationInsert, Path: "$.tags[0]", Value: `1`}
+	rows := [][]interface{}{
+		{int32(1), []byte("{}"), []byte("{}")},
+		{int32(1), first, missing},
+	}
+	decodePartialJSON(raw, rows)
+
+	want := []interface{}{
+		int32(1),
+		jsonPartialUpdate{
+			{Op: jsonDiffReplace, Path: "$.name", Value: []byte(`"lee"`)},
+			{Op: jsonDiffRemove, Path: "$.old"},
+		},
+		// 원본에서 찾지 못하면 첫 diff만 사용
+		jsonPartialUpdate{{Op: jsonDiffInsert, Path: "$.tags[0]", Value: []byte(`1`)}},
+	}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("decodePartialJSON() after image = %+v, want %+v", rows[1], want)
+	}
+	if !reflect.DeepEqual(rows[0][1], []byte("{}")) {
+		t.Errorf("decodePartialJSON() changed the before image: %+v", rows[0])
+	}
+}
@@ -0,0 +1,50 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"mysqlbinlogo/config"
+)
+
+// JSON 배열 출력기 (--format json)
+// 이벤트마다 한 줄의 객체로 쓰고 전체를 배열로 감싸 jq 등으로 바로 읽을 수 있게 함 (싱크로 보내는 이벤트 JSON과 같은 필드)
+type jsonWriter struct {
+	output io.Writer
+	count  int
+}
+
+func (w *jsonWriter) writeEvent(event *config.SQLEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	if w.count == 0 {
+		fmt.Fprint(w.output, "[\n")
+	} else {
+		fmt.Fprint(w.output, ",\n")
+	}
+	w.output.Write(data)
+	w.count++
+}
+
+func (w *jsonWriter) finish() {
+	if w.count == 0 {
+		fmt.Fprint(w.output, "[]\n")
+		return
+	}
+	fmt.Fprint(w.output, "\n]\n")
+}
+
+// JSON lines 출력기 (--format ndjson)
+// 이벤트마다 한 줄씩 써서 앞의 이벤트를 기다리지 않고 바로 처리할 수 있음 (--follow에서도 이벤트마다 한 줄)
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) writeEvent(event *config.SQLEvent) {
+	w.encoder.Encode(event)
+}
+
+func (w *ndjsonWriter) finish() {}
@@ -0,0 +1,86 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// Maxwell 데몬의 JSON 레이아웃 (output_binlog_position, output_server_id를 켠 형태)
+type maxwellRecord struct {
+	Database string                 `json:"database"`
+	Table    string                 `json:"table"`
+	Type     string                 `json:"type"` // insert, update, delete
+	TS       int64                  `json:"ts"`   // 이벤트 시각 (초)
+	Position string                 `json:"position"`
+	ServerID uint32                 `json:"server_id"`
+	Data     map[string]interface{} `json:"data"`
+	Old      map[string]interface{} `json:"old,omitempty"` // UPDATE에서 바뀐 컬럼의 이전 값
+}
+
+var maxwellTypes = map[string]string{
+	"INSERT": "insert",
+	"UPDATE": "update",
+	"DELETE": "delete",
+}
+
+// Maxwell 형식 출력기 (row의 행마다 한 줄, 쿼리 이벤트는 제외)
+type maxwellWriter struct {
+	encoder *json.Encoder
+	skipped int // 제외한 쿼리 이벤트 수
+}
+
+func newMaxwellWriter(output io.Writer) *maxwellWriter {
+	return &maxwellWriter{encoder: json.NewEncoder(output)}
+}
+
+func (w *maxwellWriter) writeEvent(event *config.SQLEvent) {
+	typ, ok := maxwellTypes[event.EventType]
+	if !ok {
+		w.skipped++
+		return
+	}
+
+	step := 1
+	if event.EventType == "UPDATE" {
+		step = 2
+	}
+	for i := 0; i < len(event.Rows); i += step {
+		record := maxwellRecord{
+			Database: event.Database,
+			Table:    event.Table,
+			Type:     typ,
+			TS:       event.Timestamp.Unix(),
+			Position: fmt.Sprintf("%s:%d", event.Filename, event.Position),
+			ServerID: event.ServerId,
+			Data:     jsonRow(event, i),
+		}
+
+		// Maxwell은 UPDATE의 data에 새 값, old에 바뀐 컬럼의 이전 값만 넣음
+		if event.EventType == "UPDATE" && i+1 < len(event.Rows) {
+			before, after := event.Rows[i], event.Rows[i+1]
+			record.Data = jsonRow(event, i+1)
+			record.Old = make(map[string]interface{})
+			for j := range before {
+				if !columnLogged(event, i, j) {
+					continue
+				}
+				if j >= len(after) || !columnLogged(event, i+1, j) || !reflect.DeepEqual(before[j], after[j]) {
+					record.Old[columnName(event.Columns, j)] = jsonValue(before[j])
+				}
+			}
+		}
+		w.encoder.Encode(record)
+	}
+}
+
+func (w *maxwellWriter) finish() {
+	if w.skipped > 0 {
+		logrus.Infof("maxwell 형식: row 변경이 아닌 쿼리 이벤트 %d개 제외", w.skipped)
+	}
+}
@@ -0,0 +1,380 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 사용자 메시지 언어 (--lang)
+const (
+	LangKorean  = "ko"
+	LangEnglish = "en"
+)
+
+// 현재 메시지 언어 (SetLanguage로 변경)
+var messageLang = LangKorean
+
+// 언어별 메시지 카탈로그 (한국어 원문 형식 문자열 → 번역, 없는 메시지는 원문 그대로 출력)
+var messageCatalog = map[string]map[string]string{
+	LangEnglish: {
+		// 분석 진행
+		"분석 시작: %s ~ %s\n":                     "Analysis start: %s ~ %s\n",
+		"MySQL 서버에 연결 중... %s:%d\n":            "Connecting to MySQL server... %s:%d\n",
+		"분석 진행률":                               "Analysis progress",
+		"MySQL 연결 중...":                        "Connecting to MySQL...",
+		"MySQL 연결 실패: %v":                      "MySQL connection failed: %v",
+		"MySQL 연결 완료":                          "Connected to MySQL",
+		"바이너리 로그 파일 검색 중...":                   "Searching binary log files...",
+		"binary log 파일 목록 가져오기 실패: %v":         "Failed to list binary log files: %v",
+		"총 %d개의 binary log 파일을 찾았습니다.\n":       "Found %d binary log files.\n",
+		"파일 검색 설정 - Workers: %d\n":             "File search settings - Workers: %d\n",
+		"대상 파일 찾기 실패: %w":                      "Failed to find target files: %w",
+		"파일 검색 완료":                             "File search complete",
+		"분석 대상 파일: %d개 (처리 순서)\n":              "Files to analyze: %d (in processing order)\n",
+		"  %d. %s (크기: %d bytes)\n":            "  %d. %s (size: %d bytes)\n",
+		"분석 중단: %w":                            "Analysis aborted: %w",
+		"파일 완료: %d/%d (%d개 이벤트)":               "File done: %d/%d (%d events)",
+		"처리 중... (%d개 이벤트)":                    "Processing... (%d events)",
+		"파일 실패: %d/%d":                         "File failed: %d/%d",
+		"파일 처리 중: %s (%d/%d)\n":                "Processing file: %s (%d/%d)\n",
+		"파일 %s 처리 실패: %v (계속 진행)\n":            "Failed to process file %s: %v (continuing)\n",
+		"파일 완료: %s (%d개 이벤트)\n":                "File done: %s (%d events)\n",
+		"결과 정리 중... (총 %d개 이벤트)":               "Preparing results... (%d events in total)",
+		"결과 정리 중... (총 %d개 이벤트)\n":             "Preparing results... (%d events in total)\n",
+		"분석 완료":                                "Analysis complete",
+		"중복 제거 전: %d개 이벤트, 중복 제거 후: %d개 이벤트\n": "Before deduplication: %d events, after: %d events\n",
+		"DDL 히스토리 반영: %d개 row 이벤트의 컬럼 구성 보정\n": "DDL history applied: column layout corrected for %d row events\n",
+		"결과 출력 실패: %v":                         "Failed to write results: %v",
+		"중복 이벤트 병합: pos=%d, time=%s, server id=%d, GTID=%s, 원본=%s, 제거=%s\n": "Duplicate events merged: pos=%d, time=%s, server id=%d, GTID=%s, kept=%s, removed=%s\n",
+		"예상치 못한 SHOW BINARY LOGS 결과 컬럼 수: %d":                               "Unexpected number of SHOW BINARY LOGS columns: %d",
+
+		// 진행률 바 대신 한 줄씩 기록 (터미널이 아닐 때)
+		"진행: ": "Progress: ",
+		"파일 %d/%d, %s / %s (%.0f%%), 이벤트 %d개": "Files %d/%d, %s / %s (%.0f%%), %d events",
+		", 남은 시간 약 %s":                        ", about %s left",
+
+		// 결과 요약
+		"\n\n지정된 시간대(%s ~ %s)에 해당하는 binary log 파일을 찾을 수 없습니다\n":   "\n\nNo binary log files cover the requested time range (%s ~ %s)\n",
+		"\n\n지정된 조건에 맞는 SQL 이벤트를 찾을 수 없습니다.":                      "\n\nNo SQL events match the given conditions.",
+		"\n>> 총 %d개의 고유한 SQL 이벤트를 발견했습니다.\n":                      "\n>> Found %d unique SQL events.\n",
+		">> 중복 제거: %d개 → %d개 (총 %d개 중복 이벤트 제거)\n":                 ">> Deduplication: %d → %d (%d duplicate events removed)\n",
+		">> 중복 제거: %d개 → %d개 (중복 없음)\n":                           ">> Deduplication: %d → %d (no duplicates)\n",
+		">> 구간의 GTID 집합: %s\n":                                    ">> GTID set of the window: %s\n",
+		">> 계정별 활동: 계정 정보가 있는 이벤트가 없습니다 (Q_INVOKER 또는 SQL 주석 필요)": ">> Activity by account: no events carry account information (needs Q_INVOKER or SQL comments)",
+		">> 계정별 활동 (계정 정보가 있는 이벤트 %d개 / 전체 %d개):\n":               ">> Activity by account (%d of %d events carry account information):\n",
+		"   %s  문장 %d개, 행 %d개, %s ~ %s\n":                         "   %s  %d statements, %d rows, %s ~ %s\n",
+		"   외 %d개 계정\n": "   and %d more accounts\n",
+		">> 행 수 경고: --warn-rows %d를 넘은 row 이벤트가 없습니다\n": ">> Row count warnings: no row events above --warn-rows %d\n",
+		">> 행 수 경고: --warn-rows %d를 넘은 row 이벤트 %d개\n":   ">> Row count warnings: row events above --warn-rows %d: %d\n",
+		"   %s %s %d행 (%s:%d, %s)\n":                    "   %s %s %d rows (%s:%d, %s)\n",
+		"   외 %d개 이벤트\n":                                "   and %d more events\n",
+
+		">> 자주 변경된 행: PK를 알 수 있는 row 이벤트가 없습니다":          ">> Hot rows: no row events with a known primary key",
+		">> 자주 변경된 행 (상위 %d개 / 변경된 행 %d개, 변경 %d회 기준):\n": ">> Hot rows (top %d of %d changed rows, %d changes):\n",
+		"   %s  %d회 (%s), 트랜잭션 %d개, %s ~ %s\n":           "   %s  %d changes (%s), %d transactions, %s ~ %s\n",
+		"   PK를 알 수 없는 테이블의 행 %d개 제외\n":                  "   %d rows of tables without a known primary key left out\n",
+
+		">> 충돌 가능성이 있는 갱신: %s 안에 같은 행을 갱신한 서로 다른 트랜잭션이 없습니다\n":  ">> Conflicting updates: no row was updated by different transactions within %s\n",
+		">> 충돌 가능성이 있는 갱신 (%s 안에 같은 행을 갱신한 서로 다른 트랜잭션, %d건):\n": ">> Conflicting updates (rows updated by different transactions within %s, %d):\n",
+		"   %s %s  트랜잭션 %d개가 %s 동안 %d회 갱신 (%s ~ %s)\n":          "   %s %s  %d transactions within %s, %d updates (%s ~ %s)\n",
+		" 외 %d개":     " and %d more",
+		"   외 %d건\n": "   and %d more\n",
+
+		">> 테이블 메타데이터 없음: row 이벤트 %d개를 컬럼 이름 없이 col_N으로 출력했습니다\n": ">> Missing table metadata: %d row events were written with col_N instead of column names\n",
+		"   %s: %d개 (%s:%d ~ %s:%d) - %s\n":                 "   %s: %d events (%s:%d ~ %s:%d) - %s\n",
+		"테이블 정보를 조회하지 않음":                                   "table information was not looked up",
+		"information_schema에 테이블이 없음 (삭제되었거나 권한 없음)":        "table not in information_schema (dropped or no privilege)",
+		"컬럼 수 불일치 (이벤트 %d개, 현재 테이블 %d개, 구간 안의 DDL로도 복원 불가)": "column count mismatch (event %d, current table %d, not recoverable from DDL in the window)",
+		"컬럼 구성을 알 수 없음":                                     "column layout unknown",
+
+		// 스키마 변경 확인 (--schema-drift)
+		"현재 테이블이 없음 (삭제, 이름 변경 또는 권한 없음)": "table does not exist now (dropped, renamed or no privilege)",
+		"없어진 컬럼: %s": "columns gone: %s",
+		"추가된 컬럼: %s": "columns added: %s",
+		">> 스키마 변경 확인: 이벤트가 참조하는 테이블과 컬럼이 모두 현재 스키마와 같습니다": ">> Schema drift: all tables and columns used by the events match the current schema",
+		">> 스키마 변경 확인: 현재 스키마와 달라 그대로 재실행할 수 없는 이벤트 %d개\n": ">> Schema drift: %d events no longer match the current schema and cannot be replayed as-is\n",
+
+		// binlog_rows_query_log_events
+		"binlog_rows_query_log_events 확인 실패: %v\n":                                                              "Failed to check binlog_rows_query_log_events: %v\n",
+		"binlog_rows_query_log_events: ON (row 이벤트의 원본 SQL 포함)":                                                 "binlog_rows_query_log_events: ON (row events include the original SQL)",
+		"binlog_rows_query_log_events: OFF (row 이벤트는 재구성된 pseudo-SQL로만 출력됩니다. --set-rows-query로 활성화 가능)":        "binlog_rows_query_log_events: OFF (row events are shown as reconstructed pseudo-SQL only; enable with --set-rows-query)",
+		"binlog_rows_query_log_events 활성화 실패 (SUPER 또는 SYSTEM_VARIABLES_ADMIN 권한 필요, Aurora는 파라미터 그룹에서 설정): %v": "Failed to enable binlog_rows_query_log_events (requires SUPER or SYSTEM_VARIABLES_ADMIN; on Aurora use the parameter group): %v",
+		"binlog_rows_query_log_events를 ON으로 변경했습니다 (이후 기록되는 이벤트부터 원본 SQL이 포함됩니다)":                               "Set binlog_rows_query_log_events to ON (events written from now on include the original SQL)",
+
+		// 구간 누락 경고
+		"파일 %s 처리 실패, 결과에서 빠짐: %v":                  "Failed to process file %s, missing from the results: %v",
+		"요청한 시작 시간이 남아 있는 가장 오래된 binary log보다 이릅니다": "The requested start time is earlier than the oldest remaining binary log",
+		"요청한 시작 시간(%s)이 가장 오래된 binary log %s의 첫 이벤트(%s)보다 이릅니다. 그 이전 이벤트는 purge되어 결과에 없습니다":          "The requested start time (%s) is earlier than the first event of the oldest binary log %s (%s). Earlier events were purged and are not in the results",
+		"\n!! 경고: 요청한 구간의 binary log가 모두 purge되었습니다 (가장 오래된 이벤트: %s). 결과가 없는 것은 변경이 없었다는 뜻이 아닙니다.\n": "\n!! WARNING: all binary logs of the requested range were purged (oldest event: %s). No results does not mean no changes.\n",
+		"\n!! 경고: %s 이전의 binary log가 없어 결과는 %s ~ %s 구간만 담고 있습니다.\n":                                  "\n!! WARNING: no binary logs before %s, so the results only cover %s ~ %s.\n",
+		"%w (가장 오래된 이벤트: %s UTC)": "%w (oldest event: %s UTC)",
+		"binary log 파일이 빠져 있습니다: %s (%s ~ %s 사이, purge 또는 삭제된 것으로 보임)": "Binary log files are missing: %s (between %s and %s, probably purged or deleted)",
+		"binary log 파일 %s의 시간 범위를 확인하지 못해 분석에서 빠졌습니다":                  "Could not read the time range of binary log file %s, so it was left out of the analysis",
+
+		"파일 %s를 %d 위치까지만 읽어 이후 이벤트가 결과에서 빠짐: %v": "File %s was only read up to position %d, later events are missing from the results: %v",
+
+		// 시간 제한 (--deadline)
+		"--deadline 안에 분석을 마치지 못했습니다":                                  "The analysis did not finish within --deadline",
+		"%w (끝까지 읽지 못한 파일 %d개)":                                        "%w (%d files not read to the end)",
+		"대상 파일 찾기 실패: --deadline %s 안에 끝나지 않았습니다":                      "Failed to find target files: not finished within --deadline %s",
+		"--deadline %s 초과: 파일 %d개를 끝까지 읽지 못해 그 뒤의 이벤트가 결과에서 빠짐 (%s부터)": "--deadline %s exceeded: %d files were not read to the end, so their later events are missing from the results (from %s)",
+		"\n!! 경고: --deadline %s 안에 분석을 마치지 못해 파일 %d개를 끝까지 읽지 못했습니다 (%s부터). 결과는 그때까지 읽은 이벤트만 담고 있습니다.\n": "\n!! WARNING: the analysis did not finish within --deadline %s and %d files were not read to the end (from %s). The results only contain the events read until then.\n",
+
+		// 명령줄
+		"Binary log 분석 중 오류 발생: %v\n":                                 "Error while analyzing binary logs: %v\n",
+		"--start-time과 --end-time을 지정해야 합니다 (--follow 모드 제외)":         "--start-time and --end-time are required (except with --follow)",
+		"시작 시간 형식이 올바르지 않습니다: %v\n":                                   "Invalid start time: %v\n",
+		"종료 시간 형식이 올바르지 않습니다: %v\n":                                   "Invalid end time: %v\n",
+		"시작 시간이 종료 시간보다 늦을 수 없습니다.":                                   "The start time cannot be later than the end time.",
+		"검색 시간 범위 (UTC): %s ~ %s\n":                                   "Search time range (UTC): %s ~ %s\n",
+		"--%s를 지정해야 합니다 (환경 변수, --config 또는 --defaults-file로도 지정 가능)": "--%s is required (can also be set by environment variable, --config or --defaults-file)",
+		"%s: %s 값 오류: %v":      "%s: invalid %s value: %v",
+		"실시간 추적 중 오류 발생: %v\n": "Error while following: %v\n",
+
+		"--incremental은 현재 시각까지 분석하므로 --end-time을 지정할 수 없습니다": "--incremental analyzes up to the current time, so --end-time cannot be given",
+
+		// audit 보고서
+		"# %s 변경 이력: %s ~ %s UTC\n":      "# Changes to %s: %s ~ %s UTC\n",
+		"# 트랜잭션 %d개, 문장 %d개, 변경 행 %d개\n": "# %d transactions, %d statements, %d rows changed\n",
+		"알 수 없음": "unknown",
+		"\n[%s] 트랜잭션 %s  계정 %s  %s:%d\n": "\n[%s] transaction %s  account %s  %s:%d\n",
+		"    %s %s.%s %d행":               "    %s %s.%s %d rows",
+		"      원본 SQL: %s\n":             "      original SQL: %s\n",
+
+		// 여러 클러스터 분석 (--clusters)
+		"클러스터 %s 분석 완료 (%s)":                            "Cluster %s analyzed (%s)",
+		"%w (클러스터: %s)":                                 "%w (clusters: %s)",
+		"# ===== 클러스터 %s (%s:%d) =====\n":               "# ===== Cluster %s (%s:%d) =====\n",
+		">> 오류: %v\n":                                   ">> Error: %v\n",
+		">> 클러스터별 결과 (%s ~ %s UTC):\n":                  ">> Results by cluster (%s ~ %s UTC):\n",
+		"   %s  %-10s  파일 %d개, 이벤트 %d개, 변경 행 %d개, %s\n": "   %s  %-10s  %d files, %d events, %d rows changed, %s\n",
+		"   합계: 클러스터 %d개, 이벤트 %d개, 변경 행 %d개\n":          "   Total: %d clusters, %d events, %d rows changed\n",
+		"설정 파일에 접속 대상이 없습니다: %s":                        "No such target in the config file: %s",
+		"--clusters는 --follow와 함께 사용할 수 없습니다":           "--clusters cannot be used with --follow",
+		"--target과 --clusters는 함께 사용할 수 없습니다":           "--target and --clusters cannot be used together",
+		"접속 대상 %s에 host, user, password가 모두 있어야 합니다":    "Target %s must set host, user and password",
+
+		// 실행 비교 (diff, history)
+		"# 실행 비교": "# Run comparison",
+		"이전":      "before",
+		"이후":      "after",
+		"#   %s: %s  %s:%d  %s ~ %s UTC (%s, 파일 %d개, 이벤트 %d개)\n": "#   %s: %s  %s:%d  %s ~ %s UTC (%s, %d files, %d events)\n",
+		">> 유형/테이블별 변화 (%d개):\n":                                 ">> Changes by type and table (%d):\n",
+		"이벤트 %d → %d (%+d)":                                      "events %d → %d (%+d)",
+		", 변경 행 %d → %d (%+d)":                                   ", rows changed %d → %d (%+d)",
+		">> 새 문장 fingerprint (이후에만 있음, %d개):\n":                  ">> New statement fingerprints (only after, %d):\n",
+		">> 사라진 문장 fingerprint (이전에만 있음, %d개):\n":                ">> Gone statement fingerprints (only before, %d):\n",
+		"   외 %d개\n":                    "   and %d more\n",
+		"--history-db를 지정해야 합니다":        "--history-db is required",
+		"실행 이력 조회 중 오류 발생: %v\n":        "Error while reading the run history: %v\n",
+		"실행 번호가 올바르지 않습니다: %s":          "Invalid run number: %s",
+		"실행 번호는 두 개를 지정해야 합니다 (이전, 이후)": "Give two run numbers (before, after)",
+		"diff 중 오류 발생: %v\n":            "Error while comparing: %v\n",
+		"실행 번호 두 개 또는 --before-start, --before-end, --after-start, --after-end를 지정해야 합니다": "Give two run numbers or --before-start, --before-end, --after-start and --after-end",
+		"%s 형식이 올바르지 않습니다: %v\n":                                                          "Invalid %s: %v\n",
+		"%s가 %s보다 늦을 수 없습니다":                                                              "%s cannot be later than %s",
+		"#%-5d %s  %s:%d  %s ~ %s UTC  %-10s  파일 %d개, 이벤트 %d개\n":                          "#%-5d %s  %s:%d  %s ~ %s UTC  %-10s  %d files, %d events\n",
+
+		// binlog 보존 기간 권장 (--retention-advice)
+		"--retention-advice는 파일을 시간으로 찾을 때만 사용할 수 있습니다 (--binlog-files 제외)":        "--retention-advice only works when files are found by time (not with --binlog-files)",
+		">> binlog 보존 기간 권장: 대상 파일의 시간 범위를 확인하지 못해 계산할 수 없습니다":                     ">> Binlog retention advice: unavailable, the time ranges of the analyzed files are unknown",
+		">> binlog 보존 기간 권장 (대상 파일 %d개, %.1f시간 기준):\n":                             ">> Binlog retention advice (from %d analyzed files covering %.1f hours):\n",
+		"   쓰기량: 평균 %s/시간, 최대 %s/시간 (%s)\n":                                        "   Write rate: %s/hour on average, %s/hour at peak (%s)\n",
+		"   파일 로테이션: 시간당 %.1f개 (평균 %s마다), 평균 파일 크기 %s\n":                           "   Rotation: %.1f files per hour (every %s on average), %s per file on average\n",
+		"   현재 binlog: 파일 %d개, %s\n":                                               "   Current binlogs: %d files, %s\n",
+		"   현재 보존 설정: 확인 실패":                                                       "   Current retention: unknown",
+		"   현재 보존 설정: %s = NULL (복제본이나 CDC가 읽고 나면 곧바로 삭제)\n":                       "   Current retention: %s = NULL (purged as soon as replicas and CDC have read them)\n",
+		"   현재 보존 설정: %s = %s (자동 삭제 안 함)\n":                                       "   Current retention: %s = %s (never purged automatically)\n",
+		"   현재 보존 설정: %s = %s (%.0f시간, 예상 저장 공간 %s)\n":                             "   Current retention: %s = %s (%.0f hours, expected storage %s)\n",
+		"   구간 시작 시각의 binlog는 이미 삭제되었습니다 (가장 오래된 이벤트: %s UTC)\n":                   "   The binlogs at the start of the window are already purged (oldest event: %s UTC)\n",
+		"   권장 보존 기간: %d시간 (구간 시작부터 지금까지 %.1f시간 + 여유 %.0f%%, 최소 %d시간, 최대 %d시간)\n":  "   Recommended retention: %d hours (%.1f hours from the window start until now + %.0f%% margin, at least %d, at most %d hours)\n",
+		"   예상 저장 공간: %s (최대 쓰기량 기준 %s)\n":                                         "   Expected storage: %s (%s at the peak write rate)\n",
+		"   적용: CALL mysql.rds_set_configuration('binlog retention hours', %d);\n": "   Apply: CALL mysql.rds_set_configuration('binlog retention hours', %d);\n",
+		"   적용: SET PERSIST binlog_expire_logs_seconds = %d;\n":                    "   Apply: SET PERSIST binlog_expire_logs_seconds = %d;\n",
+
+		// 테이블별 쓰기량 (--capacity-report)
+		">> 테이블별 쓰기량 (상위 %d개 / 전체 %d개):\n":                    ">> Write volume by table (top %d of %d):\n",
+		"   %s  %9s, 행 %d개, 이벤트 %d개, 시간당 평균 %s, 최대 %s (%s)\n": "   %s  %9s, %d rows, %d events, %s/hour on average, %s at peak (%s)\n",
+
+		// 출력 크기 확인 (--confirm-over)
+		"\n\n대상 파일 %d개의 크기 합 %s가 --confirm-over %s보다 큽니다 (결과도 비슷한 크기가 될 수 있음). 계속하시겠습니까? [y/N] ": "\n\nThe %d matched files total %s, more than --confirm-over %s (the output can be about as large). Continue? [y/N] ",
+		"대상 파일 %d개의 크기 합 %s가 --confirm-over %s보다 큽니다. 계속하려면 --yes를 지정하세요":                        "The %d matched files total %s, more than --confirm-over %s. Specify --yes to continue",
+		"예상 출력 크기를 확인하고 분석을 중단했습니다":                                                              "Analysis canceled at the output size confirmation",
+
+		// 이벤트 훅 (--hook)
+		"이벤트 훅 시작 실패: %v":                      "Failed to start event hook: %v",
+		"이벤트 훅: 이벤트 %s를 JSON으로 변환할 수 없습니다: %v": "Event hook: cannot encode event %s as JSON: %v",
+		"이벤트 훅 쓰기 실패: %v":                      "Event hook write failed: %v",
+		"이벤트 훅 읽기 실패: %v":                      "Event hook read failed: %v",
+		"이벤트 훅이 이벤트 %s에 응답하기 전에 종료되었습니다":       "Event hook exited before answering event %s",
+		"이벤트 훅이 이벤트 %s에 잘못된 응답을 보냈습니다: %v":     "Event hook sent an invalid reply for event %s: %v",
+		"이벤트 훅 %q 실패: %v":                      "Event hook %q failed: %v",
+		"이벤트 훅 적용: %d개 중 %d개 유지\n":             "Event hook applied: %d events in, %d kept\n",
+
+		"이벤트 훅이 이벤트 %s에 %s 동안 응답하지 않았습니다 (훅은 응답 한 줄마다 표준 출력을 flush해야 함)": "Event hook did not answer event %s within %s (the hook must flush stdout after each reply line)",
+
+		// 시간 입력
+		"알 수 없는 시간대입니다: %s": "Unknown time zone: %s",
+		timeInputFormats: `Accepted formats:
+  2024-01-15 10:00:00[.123456]     UTC
+  2024-01-15                       UTC midnight
+  2024-01-15T10:00:00+09:00        RFC 3339 (Z or offset)
+  2024-01-15 10:00:00 +09:00       offset
+  2024-01-15 10:00:00 Asia/Seoul   time zone name
+  1705312800[.5]                   Unix epoch seconds`,
+	},
+}
+
+// 결과 헤더와 완료 로그의 문구 (원문이 영어, --messages로만 바꿈)
+// "# at", "# exec_time" 등 mysqlbinlog와 같은 줄은 다른 도구가 읽으므로 바꿀 수 없음
+var outputMessages = []string{
+	"# Binary Log Analysis Results",
+	"# Time Range: %s ~ %s",
+	"# binlog_rows_query_log_events: %s",
+	"# Total Events: %d",
+	"# GTID Set: %s",
+	"# WARNING: %s",
+	"# WARNING: %d rows changed (more than --warn-rows %d)",
+	"# Unresolved Table Metadata (row events shown with col_N):",
+	"#   %s: %d row events, %s:%d ~ %s:%d",
+	"# Columns: unknown, shown as col_N (see Unresolved Table Metadata)",
+	"# Schema Snapshot (information_schema, captured %s UTC):",
+	"#   %s: (not found, columns shown as col_N)",
+	"# Binary Log File: %s",
+	"# Event Size: %d bytes",
+	"# Event Size: %d bytes  Rows: %d",
+	"# Capture Latency: %s",
+	"# PK: %s",
+	"# Original SQL: %s",
+	"# Reconstructed pseudo-SQL (from row event):",
+	"Analysis complete: %d SQL events",
+	"Analysis complete: %d SQL events sent to %s",
+	"Results saved to %s",
+	"Results saved to %s (%d events)",
+	"Timeline saved to %s (%d buckets of %s)",
+	"Capacity report saved to %s (%d tables, %d table-hours)",
+	"Metrics pushed to %s",
+	"Run summary saved to %s",
+	"Incremental state saved to %s (next run starts at %s:%d)",
+	"Run #%d recorded in %s",
+}
+
+// 형식 문자열의 인자 자리 (%s, %-10d, %v 등)
+var messageVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// 지원 언어 확인
+func validateLanguage(lang string) error {
+	switch lang {
+	case LangKorean, LangEnglish:
+		return nil
+	default:
+		return fmt.Errorf("지원하지 않는 언어: %s (en, ko 중 선택)", lang)
+	}
+}
+
+// SetLanguage 사용자 메시지 언어 변경 (--lang)
+func SetLanguage(lang string) error {
+	if err := validateLanguage(lang); err != nil {
+		return err
+	}
+	messageLang = lang
+	return nil
+}
+
+// LoadMessages 메시지 파일(YAML 또는 JSON, 언어 → 원문 → 번역)을 카탈로그에 덮어씀 (--messages)
+func LoadMessages(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("메시지 파일 읽기 실패: %v", err)
+	}
+	var catalog map[string]map[string]string
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("메시지 파일 형식 오류 (%s): %v", path, err)
+	}
+
+	// 하나라도 잘못되면 아무것도 반영하지 않음
+	for lang, messages := range catalog {
+		if err := validateLanguage(lang); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		for source, translated := range messages {
+			if err := validateMessage(source, translated); err != nil {
+				return fmt.Errorf("%s: %s: %v", path, lang, err)
+			}
+		}
+	}
+	for lang, messages := range catalog {
+		if messageCatalog[lang] == nil {
+			messageCatalog[lang] = make(map[string]string, len(messages))
+		}
+		for source, translated := range messages {
+			messageCatalog[lang][source] = translated
+		}
+	}
+	return nil
+}
+
+// 번역이 원문과 같은 인자 자리를 같은 순서로 가지는지, 결과 헤더 줄은 주석으로 남는지 확인
+func validateMessage(source, translated string) error {
+	if !slices.Equal(messageVerb.FindAllString(source, -1), messageVerb.FindAllString(translated, -1)) {
+		return fmt.Errorf("인자 자리(%%s, %%d 등)가 원문과 다릅니다: %q → %q", source, translated)
+	}
+	if strings.HasPrefix(source, "#") && !strings.HasPrefix(translated, "#") {
+		return fmt.Errorf("결과 헤더 문구는 #으로 시작해야 합니다: %q → %q", source, translated)
+	}
+	return nil
+}
+
+// WriteMessages 현재 언어의 모든 메시지를 메시지 파일 형식(JSON)으로 출력 (config messages)
+func WriteMessages(w io.Writer) error {
+	messages := make(map[string]string)
+	for source := range messageCatalog[LangEnglish] {
+		messages[source] = T(source)
+	}
+	for _, source := range outputMessages {
+		messages[source] = T(source)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]map[string]string{messageLang: messages})
+}
+
+// DefaultLanguage 로캘 환경 변수(LC_ALL, LC_MESSAGES, LANG)에 따른 기본 언어
+// 로캘이 없으면 한국어, 한국어가 아닌 로캘이면 영어
+func DefaultLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(locale), LangKorean) {
+			return LangKorean
+		}
+		return LangEnglish
+	}
+	return LangKorean
+}
+
+// T 현재 언어의 메시지 (카탈로그에 없으면 원문)
+func T(message string) string {
+	if translated, ok := messageCatalog[messageLang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// 메시지가 언어를 따르는 오류 (errors.Is로 비교하는 sentinel 오류용)
+type localizedError string
+
+func (e localizedError) Error() string {
+	return T(string(e))
+}
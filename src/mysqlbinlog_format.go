@@ -0,0 +1,294 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// mysqlbinlog -vv --base64-output=decode-rows와 같은 모양의 출력기 (--format mysqlbinlog)
+// 공식 도구의 출력을 읽던 스크립트를 그대로 쓸 수 있도록 이벤트 헤더, 쿼리, row 이벤트의 ### 줄을 같은 형식으로 출력
+// binlog에만 있는 정보(table id, CRC32, thread_id)와 Table_map, Xid 이벤트는 알 수 없어 쓰지 않음
+type mysqlbinlogWriter struct {
+	output   io.Writer
+	renderer *SQLExtractor
+	started  bool
+	database string
+}
+
+// row 이벤트 헤더의 이벤트 이름
+var mysqlbinlogRowEvents = map[string]string{
+	"INSERT": "Write_rows",
+	"UPDATE": "Update_rows",
+	"DELETE": "Delete_rows",
+}
+
+func (w *mysqlbinlogWriter) writeEvent(event *config.SQLEvent) {
+	output := w.output
+	if !w.started {
+		writeReplayablePreamble(output)
+		w.started = true
+	}
+
+	fmt.Fprintf(output, "# at %d\n", event.StartPosition)
+	header := fmt.Sprintf("%s server id %d  end_log_pos %d", mysqlbinlogTime(event), event.ServerId, event.Position)
+
+	name, rows := mysqlbinlogRowEvents[event.EventType]
+	if !rows {
+		fmt.Fprintf(output, "%s \tQuery\texec_time=%d\terror_code=%d\n", header, event.ExecTime, event.ErrorCode)
+		if event.Database != "" && event.Database != w.database {
+			fmt.Fprintf(output, "use %s/*!*/;\n", quoteIdentifier(event.Database))
+			w.database = event.Database
+		}
+		fmt.Fprintf(output, "SET TIMESTAMP=%s/*!*/;\n", replayTimestamp(event.Timestamp))
+		fmt.Fprintf(output, "%s\n/*!*/;\n", event.SQL)
+		return
+	}
+
+	// binlog_rows_query_log_events로 기록된 원본 SQL은 mysqlbinlog -vv처럼 # 줄로
+	fmt.Fprintf(output, "%s \t%s: flags: STMT_END_F\n", header, name)
+	if event.OriginalSQL != "" {
+		for _, line := range strings.Split(strings.TrimSpace(event.OriginalSQL), "\n") {
+			fmt.Fprintf(output, "# %s\n", line)
+		}
+	}
+
+	table := quotedTableName(event)
+	columns := w.renderer.snapshotColumns(event)
+	for i := range event.Rows {
+		switch {
+		case event.EventType == "INSERT":
+			fmt.Fprintf(output, "### INSERT INTO %s\n### SET\n", table)
+		case event.EventType == "DELETE":
+			fmt.Fprintf(output, "### DELETE FROM %s\n### WHERE\n", table)
+		case i%2 == 0:
+			fmt.Fprintf(output, "### UPDATE %s\n### WHERE\n", table)
+		default:
+			fmt.Fprintln(output, "### SET")
+		}
+		w.writeRow(event, i, columns)
+	}
+}
+
+// 행 이미지의 컬럼마다 "###   @N=값" (기록되지 않은 컬럼은 mysqlbinlog처럼 건너뜀)
+// 현재 스키마와 컬럼 구성이 같으면 -vv의 타입 주석을 붙임
+func (w *mysqlbinlogWriter) writeRow(event *config.SQLEvent, image int, columns []ColumnInfo) {
+	for j, value := range event.Rows[image] {
+		if !columnLogged(event, image, j) {
+			continue
+		}
+		position := fmt.Sprintf("@%d", j+1)
+		var column *ColumnInfo
+		if columns != nil {
+			column = &columns[j]
+		}
+
+		var text string
+		if update, ok := value.(jsonPartialUpdate); ok {
+			text = update.expression(position, mysqlbinlogValueFunc(column))
+		} else {
+			text = mysqlbinlogValue(value, column)
+		}
+
+		if column == nil {
+			fmt.Fprintf(w.output, "###   %s=%s\n", position, text)
+			continue
+		}
+		typeName, meta := mysqlbinlogColumnType(column)
+		fmt.Fprintf(w.output, "###   %s=%s /* %s meta=%d nullable=%d is_null=%d */\n",
+			position, text, typeName, meta, boolInt(column.Nullable), boolInt(value == nil))
+	}
+}
+
+func (w *mysqlbinlogWriter) finish() {
+	if !w.started {
+		writeReplayablePreamble(w.output)
+	}
+	writeReplayableEpilogue(w.output)
+}
+
+// 이벤트 헤더의 시각 (mysqlbinlog와 같이 초 단위, 시는 공백으로 채움)
+func mysqlbinlogTime(event *config.SQLEvent) string {
+	t := event.Timestamp
+	return fmt.Sprintf("#%02d%02d%02d %2d:%02d:%02d", t.Year()%100, int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second())
+}
+
+func mysqlbinlogValueFunc(column *ColumnInfo) func(interface{}) string {
+	return func(value interface{}) string {
+		return mysqlbinlogValue(value, column)
+	}
+}
+
+// mysqlbinlog -v의 값 표기 (숫자는 그대로, 문자열은 따옴표 안에서 제어 문자, 따옴표, 역슬래시를 \xNN으로)
+func mysqlbinlogValue(value interface{}, column *ColumnInfo) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return fmt.Sprintf("%-20s", strconv.FormatFloat(float64(v), 'g', 6, 32))
+	case float64:
+		return fmt.Sprintf("%-20s", strconv.FormatFloat(v, 'g', 6, 64))
+	case string:
+		if column != nil && mysqlbinlogNumeric(column) {
+			return v
+		}
+		return mysqlbinlogQuote([]byte(v))
+	case []byte:
+		return mysqlbinlogQuote(v)
+	case time.Time:
+		return mysqlbinlogQuote([]byte(v.Format("2006-01-02 15:04:05.999999")))
+	default:
+		return mysqlbinlogQuote([]byte(fmt.Sprint(v)))
+	}
+}
+
+// 문자열로 받는 숫자 값 (DECIMAL)은 따옴표 없이
+func mysqlbinlogNumeric(column *ColumnInfo) bool {
+	base, _ := splitColumnType(column.Type)
+	return base == "decimal" || base == "numeric"
+}
+
+func mysqlbinlogQuote(value []byte) string {
+	var sb strings.Builder
+	sb.WriteByte('\'')
+	for _, c := range value {
+		if c > 0x1f && c != '\'' && c != '\\' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "\\x%02x", c)
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
+
+// COLUMN_TYPE을 이름과 괄호 안의 인자로 (int(10) unsigned → int, 10)
+func splitColumnType(columnType string) (string, string) {
+	columnType = strings.ToLower(columnType)
+	base, rest, found := strings.Cut(columnType, "(")
+	if !found {
+		base, _, _ = strings.Cut(columnType, " ")
+		return base, ""
+	}
+	args, _, _ := strings.Cut(rest, ")")
+	if base == "enum" || base == "set" {
+		// 값 목록에 괄호가 들어갈 수 있으므로 마지막 괄호까지
+		args = rest[:strings.LastIndex(rest, ")")]
+	}
+	return base, args
+}
+
+// mysqlbinlog -vv 주석의 타입 이름과 메타데이터 (table map 이벤트에 기록되는 값을 컬럼 정의로 계산)
+func mysqlbinlogColumnType(column *ColumnInfo) (string, int) {
+	base, args := splitColumnType(column.Type)
+	number := func(s string) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(s))
+		return n
+	}
+	switch base {
+	case "tinyint", "bool", "boolean":
+		return "TINYINT", 0
+	case "smallint":
+		return "SHORTINT", 0
+	case "mediumint":
+		return "MEDIUMINT", 0
+	case "int", "integer":
+		return "INT", 0
+	case "bigint":
+		return "LONGINT", 0
+	case "float":
+		return "FLOAT", 4
+	case "double", "real":
+		return "DOUBLE", 8
+	case "decimal", "numeric":
+		precision, scale, _ := strings.Cut(args, ",")
+		p, s := number(precision), number(scale)
+		if p == 0 {
+			p = 10
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", p, s), p<<8 | s
+	case "varchar", "varbinary":
+		length := int(column.OctetLength)
+		return fmt.Sprintf("VARSTRING(%d)", length), length
+	case "char", "binary":
+		// 실제 타입과 길이를 2바이트에 나누어 기록 (길이가 255를 넘으면 상위 비트를 타입 바이트에)
+		const stringType = 254
+		length := int(column.OctetLength)
+		return fmt.Sprintf("STRING(%d)", length), (stringType^((length&0x300)>>4))<<8 | length&0xff
+	case "enum":
+		size := 1
+		if countEnumValues(args) > 255 {
+			size = 2
+		}
+		return fmt.Sprintf("ENUM(%d bytes)", size), 247<<8 | size
+	case "set":
+		size := (countEnumValues(args) + 7) / 8
+		if size > 4 {
+			size = 8
+		}
+		return fmt.Sprintf("SET(%d bytes)", size), 248<<8 | size
+	case "tinyblob", "tinytext":
+		return "TINYBLOB/TINYTEXT", 1
+	case "blob", "text":
+		return "BLOB/TEXT", 2
+	case "mediumblob", "mediumtext":
+		return "MEDIUMBLOB/MEDIUMTEXT", 3
+	case "longblob", "longtext":
+		return "LONGBLOB/LONGTEXT", 4
+	case "json":
+		return "JSON", 4
+	case "date":
+		return "DATE", 0
+	case "datetime":
+		return fmt.Sprintf("DATETIME(%d)", number(args)), number(args)
+	case "timestamp":
+		return fmt.Sprintf("TIMESTAMP(%d)", number(args)), number(args)
+	case "time":
+		return fmt.Sprintf("TIME(%d)", number(args)), number(args)
+	case "year":
+		return "YEAR", 0
+	case "bit":
+		bits := number(args)
+		if bits == 0 {
+			bits = 1
+		}
+		return fmt.Sprintf("BIT(%d)", bits), bits/8<<8 | bits%8
+	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		return "GEOMETRY", 4
+	default:
+		return strings.ToUpper(base), 0
+	}
+}
+
+// ENUM, SET 정의의 값 수 ('a','b,c' → 2, 따옴표 안의 쉼표는 세지 않음)
+func countEnumValues(args string) int {
+	count := 0
+	quoted := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == '\\' && quoted:
+			i++
+		case args[i] == '\'' && quoted && i+1 < len(args) && args[i+1] == '\'':
+			i++
+		case args[i] == '\'':
+			if !quoted {
+				count++
+			}
+			quoted = !quoted
+		}
+	}
+	return count
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,210 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 추출하는 대로 쓰는 결과 출력 (--format ndjson)
+// 파일마다 추출이 끝나면 파일 순서대로 바로 써서 구간 전체의 이벤트를 메모리에 모으지 않음
+// 대신 구간 전체가 필요한 처리(시간순 정렬, 구간 안의 DDL로 컬럼 구성 보정, 이벤트 요약 보고서)는 하지 않음
+type eventStream struct {
+	ba     *BinlogAnalyzer
+	file   io.WriteCloser    // --output 파일 (stdout이나 클러스터 섹션이면 nil)
+	output *firstErrorWriter // 번호가 붙은 파일로 나누지 않을 때의 출력 (쓰기 오류 확인용)
+	writer eventWriter
+	hook   *eventHook // --hook (없으면 nil)
+
+	order   map[string]int            // 대상 파일 이름 → 순서
+	pending map[int][]config.SQLEvent // 앞 파일이 끝나기를 기다리는 결과 (실패한 파일은 nil)
+	next    int                       // 다음에 쓸 파일 순서
+
+	seen       map[string]bool // 이미 쓴 이벤트의 중복 제거 키
+	extracted  int
+	written    int
+	duplicates int
+}
+
+// 결과를 모으지 않고 바로 쓰는지 (싱크, 분할 출력, 서버 모드 작업은 결과 전체를 받음)
+func (ba *BinlogAnalyzer) streaming() bool {
+	return ba.outputFormat() == FormatNDJSON && ba.Config.Sink == "" && ba.Config.SplitBy == "" &&
+		ba.onResults == nil && !ba.discardOutput
+}
+
+// 결과 전체가 있어야 하는 옵션은 --format ndjson과 함께 쓸 수 없음
+func validateStream(cfg config.Config) error {
+	if cfg.Format != FormatNDJSON || cfg.Sink != "" || cfg.Follow {
+		return nil
+	}
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{"--split-by", cfg.SplitBy != ""},
+		{"--timeline", cfg.Timeline != ""},
+		{"--capacity-report", cfg.CapacityReport != ""},
+		{"--account-summary", cfg.AccountSummary},
+		{"--warn-rows", cfg.WarnRows > 0},
+		{"--hot-rows-top", cfg.HotRowsTop > 0},
+		{"--conflict-window", cfg.ConflictWindow > 0},
+		{"--schema-drift", cfg.SchemaDrift},
+	}
+	for _, option := range options {
+		if option.set {
+			return fmt.Errorf("--format ndjson은 결과를 모으지 않고 바로 쓰므로 %s와 함께 사용할 수 없습니다", option.name)
+		}
+	}
+	return nil
+}
+
+// 출력 대상을 열고 훅을 시작 (파일 순서는 targetFiles 순서)
+func (ba *BinlogAnalyzer) newEventStream(ctx context.Context, targetFiles []config.BinlogFile) (*eventStream, error) {
+	var output io.Writer = os.Stdout
+	var file io.WriteCloser
+	if ba.results != nil {
+		output = ba.results
+	} else if ba.Config.OutputFile != "" && !rotating(ba.Config) {
+		var err error
+		if file, err = createOutputFile(ba.Config, ba.Config.OutputFile); err != nil {
+			return nil, err
+		}
+		output = file
+	}
+
+	s := &eventStream{
+		ba:      ba,
+		file:    file,
+		order:   make(map[string]int, len(targetFiles)),
+		pending: make(map[int][]config.SQLEvent),
+		seen:    make(map[string]bool),
+	}
+	if ba.results == nil && rotating(ba.Config) {
+		// 번호가 붙은 파일은 첫 이벤트를 쓸 때 열림
+		s.writer = ba.newRotatingWriter(nil)
+	} else {
+		s.output = &firstErrorWriter{Writer: output}
+		s.writer = ba.newEventWriter(s.output)
+	}
+	for i, target := range targetFiles {
+		s.order[target.Name] = i
+	}
+	if ba.Config.Hook != "" {
+		hook, err := startEventHook(ctx, ba.Config.Hook)
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+		s.hook = hook
+	}
+	return s, nil
+}
+
+// 파일 하나의 결과 (필터 적용 후, 실패한 파일은 nil)
+// 앞 파일이 모두 끝났으면 바로 쓰고, 아니면 앞 파일이 끝날 때까지 보관
+func (s *eventStream) add(file string, events []config.SQLEvent) error {
+	s.pending[s.order[file]] = events
+	for {
+		events, ok := s.pending[s.next]
+		if !ok {
+			return nil
+		}
+		delete(s.pending, s.next)
+		s.next++
+		if err := s.write(events); err != nil {
+			return err
+		}
+	}
+}
+
+// 중복을 빼고 훅을 거쳐 출력 (중복이면 먼저 쓴 앞 파일의 이벤트를 남김)
+func (s *eventStream) write(events []config.SQLEvent) error {
+	s.extracted += len(events)
+	kept := events[:0]
+	for i := range events {
+		key := duplicateKey(&events[i])
+		if s.seen[key] {
+			s.duplicates++
+			continue
+		}
+		s.seen[key] = true
+		kept = append(kept, events[i])
+	}
+	if s.hook != nil {
+		var err error
+		if kept, err = s.hook.Filter(kept); err != nil {
+			return err
+		}
+	}
+	for i := range kept {
+		s.writer.writeEvent(&kept[i])
+	}
+	if s.output != nil && s.output.err != nil {
+		return fmt.Errorf("결과 기록 실패: %v", s.output.err)
+	}
+	s.written += len(kept)
+	s.ba.run.countEvents(kept)
+	return nil
+}
+
+// 출력을 마무리하고 요약 출력
+func (s *eventStream) finish() error {
+	ba := s.ba
+	s.writer.finish()
+	if s.output != nil && s.output.err != nil {
+		s.close()
+		return fmt.Errorf("결과 기록 실패: %v", s.output.err)
+	}
+	if err := s.close(); err != nil {
+		return err
+	}
+	rotated, _ := s.writer.(*rotatingWriter)
+	if rotated != nil && rotated.err != nil {
+		return rotated.err
+	}
+	ba.run.extracted = s.extracted
+	ba.run.duplicates = s.duplicates
+
+	logrus.Infof(T("Analysis complete: %d SQL events"), s.written)
+	if ba.Config.OutputFile != "" && rotated == nil {
+		logrus.Infof(T("Results saved to %s"), ba.Config.OutputFile)
+	}
+
+	messages := ba.messageOutput()
+	unique := s.extracted - s.duplicates
+	if s.hook != nil && ba.Config.Verbose >= config.VerboseFiles {
+		fmt.Fprintf(messages, T("이벤트 훅 적용: %d개 중 %d개 유지\n"), unique, s.written)
+	}
+	fmt.Fprintf(messages, T("\n>> 총 %d개의 고유한 SQL 이벤트를 발견했습니다.\n"), s.written)
+	if s.duplicates > 0 {
+		fmt.Fprintf(messages, T(">> 중복 제거: %d개 → %d개 (총 %d개 중복 이벤트 제거)\n"), s.extracted, unique, s.duplicates)
+	} else {
+		fmt.Fprintf(messages, T(">> 중복 제거: %d개 → %d개 (중복 없음)\n"), s.extracted, unique)
+	}
+	if gtids := ba.gtids.String(); gtids != "" {
+		fmt.Fprintf(messages, T(">> 구간의 GTID 집합: %s\n"), gtids)
+	}
+	ba.lags.write(messages)
+	ba.retention.write(messages)
+	return nil
+}
+
+// 훅을 끝내고 출력 파일 닫기 (여러 번 불러도 됨)
+func (s *eventStream) close() error {
+	var err error
+	if s.hook != nil {
+		err = s.hook.Close()
+	}
+	if s.file != nil {
+		if closeErr := s.file.Close(); err == nil {
+			err = closeErr
+		}
+		s.file = nil
+	}
+	return err
+}
@@ -0,0 +1,390 @@
+package src
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 결과 파일이 이미 있으면 --append나 --overwrite 없이는 분석 전에 중단 (이전 결과를 실수로 덮어쓰지 않도록)
+func validateOutputFile(cfg config.Config) error {
+	if cfg.AppendOutput && cfg.OverwriteOutput {
+		return fmt.Errorf("--append와 --overwrite는 함께 사용할 수 없습니다")
+	}
+	if err := validateCompress(cfg); err != nil {
+		return err
+	}
+	if err := validateRotate(cfg); err != nil {
+		return err
+	}
+	if cfg.OutputFile == "" || cfg.SplitBy != "" || cfg.AppendOutput || cfg.OverwriteOutput {
+		return nil
+	}
+	name := cfg.OutputFile
+	if rotating(cfg) {
+		// 나누어 쓰는 파일은 첫 파일로 확인 (이후 파일이 이미 있으면 그 파일을 쓸 때 오류)
+		name = rotateFileName(cfg.OutputFile, 1)
+	}
+	if _, err := os.Stat(name); err == nil {
+		return outputExistsError(name)
+	}
+	return nil
+}
+
+func outputExistsError(name string) error {
+	return fmt.Errorf("결과 파일이 이미 있습니다: %s (이어 쓰려면 --append, 덮어쓰려면 --overwrite)", name)
+}
+
+// 결과 파일 열기 (--append면 끝에 이어 쓰고, --overwrite면 비우고, 둘 다 아니면 새 파일만 생성)
+func createOutputFile(cfg config.Config, name string) (io.WriteCloser, error) {
+	flag := os.O_WRONLY | os.O_CREATE
+	switch {
+	case cfg.AppendOutput:
+		flag |= os.O_APPEND
+	case cfg.OverwriteOutput:
+		flag |= os.O_TRUNC
+	default:
+		flag |= os.O_EXCL
+	}
+	file, err := os.OpenFile(name, flag, 0o666)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, outputExistsError(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %v", err)
+	}
+	return compressOutput(cfg, file)
+}
+
+// 결과 출력
+func (ba *BinlogAnalyzer) outputResults(events []config.SQLEvent) error {
+	if ba.Config.SplitBy != "" {
+		return ba.outputSplitResults(events)
+	}
+	if rotating(ba.Config) && ba.results == nil {
+		return ba.outputRotatedResults(events)
+	}
+
+	var output io.Writer = os.Stdout
+	var file io.WriteCloser
+	if ba.results != nil {
+		output = ba.results
+	} else if ba.Config.OutputFile != "" {
+		var err error
+		if file, err = createOutputFile(ba.Config, ba.Config.OutputFile); err != nil {
+			return err
+		}
+		output = file
+	}
+
+	green := "\033[32m"
+	reset := "\033[0m"
+
+	// 재실행용 출력과 기계 판독용 형식은 다른 프로그램으로 바로 전달되므로 색상 코드와 헤더를 넣지 않음
+	// 색상은 결과를 ANSI를 처리하는 터미널(stdout)로 출력할 때만 (NO_COLOR로 끌 수 있음)
+	colored := ba.readableFormat() && !ba.Config.Replayable && output == os.Stdout && detectTerminal(os.Stdout).colors()
+	if colored {
+		fmt.Fprint(output, green)
+	}
+	err := ba.writeResults(output, events)
+	if colored {
+		fmt.Fprint(output, reset)
+	}
+	if file != nil {
+		// 압축 파일은 닫을 때 gzip 트레일러, zstd 프레임 끝을 쓰므로 닫기 오류도 확인
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("결과 파일 기록 실패: %v", err)
+	}
+
+	logrus.Infof(T("Analysis complete: %d SQL events"), len(events))
+	if ba.Config.OutputFile != "" {
+		logrus.Infof(T("Results saved to %s"), ba.Config.OutputFile)
+	}
+
+	return nil
+}
+
+// 결과 헤더(텍스트/세로 형식)와 이벤트 출력 (첫 쓰기 오류 반환)
+func (ba *BinlogAnalyzer) writeResults(output io.Writer, events []config.SQLEvent) error {
+	checked := &firstErrorWriter{Writer: output}
+	ba.writeResultsHeader(checked, len(events))
+	writer := ba.newEventWriter(checked)
+	for i := range events {
+		writer.writeEvent(&events[i])
+	}
+	writer.finish()
+	return checked.err
+}
+
+// 첫 쓰기 오류를 기억하는 출력 (형식별 출력기는 쓰기마다 오류를 확인하지 않음)
+// 오류가 난 뒤의 쓰기는 버림
+type firstErrorWriter struct {
+	io.Writer
+	err error
+}
+
+func (w *firstErrorWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.Writer.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// 결과 헤더 (텍스트/세로 형식만)
+func (ba *BinlogAnalyzer) writeResultsHeader(output io.Writer, total int) {
+	if ba.readableFormat() {
+		fmt.Fprintln(output, T("# Binary Log Analysis Results"))
+		fmt.Fprintf(output, T("# Time Range: %s ~ %s")+"\n",
+			ba.Config.StartTime.Format("2006-01-02 15:04:05.999999"),
+			ba.Config.EndTime.Format("2006-01-02 15:04:05.999999"))
+		if ba.rowsQueryLogging != "" {
+			fmt.Fprintf(output, T("# binlog_rows_query_log_events: %s")+"\n", ba.rowsQueryLogging)
+		}
+		fmt.Fprintf(output, T("# Total Events: %d")+"\n", total)
+		if gtids := ba.gtids.String(); gtids != "" {
+			// 필터와 무관하게 구간 안에서 실행된 모든 트랜잭션
+			fmt.Fprintf(output, T("# GTID Set: %s")+"\n", gtids)
+		}
+		for _, warning := range ba.warnings {
+			fmt.Fprintf(output, T("# WARNING: %s")+"\n", warning)
+		}
+		ba.writeSchemaSnapshot(output)
+		ba.writeUnresolvedHeader(output)
+		fmt.Fprintf(output, "\n")
+	}
+}
+
+// 출력 형식 (--format)
+const (
+	FormatText     = "text"     // mysqlbinlog와 비슷한 텍스트 (기본값)
+	FormatVertical = "vertical" // 이벤트마다 "필드: 값" 블록 (mysql 클라이언트의 \G)
+	FormatDebezium = "debezium" // Debezium 변경 envelope (JSON lines)
+	FormatMaxwell  = "maxwell"  // Maxwell 데몬 JSON (JSON lines)
+	FormatCanal    = "canal"    // Canal flat message (JSON lines)
+
+	FormatAudit = "audit" // 정규화된 감사 레코드 (JSON lines, SIEM 수집용)
+
+	FormatJSON = "json" // 이벤트 객체의 JSON 배열
+
+	FormatNDJSON = "ndjson" // 이벤트 객체의 JSON lines (파일마다 추출하는 대로 출력)
+
+	FormatCSV = "csv" // 이벤트마다 한 행 (스프레드시트, 적재용)
+
+	FormatParquet = "parquet" // 이벤트마다 한 행의 Parquet 파일 (Athena 등 데이터 레이크용)
+
+	FormatMysqlbinlog = "mysqlbinlog" // mysqlbinlog -vv --base64-output=decode-rows와 같은 모양 (기존 파싱 스크립트용)
+)
+
+func validateFormat(cfg config.Config) error {
+	if err := validateCSVColumns(cfg); err != nil {
+		return err
+	}
+	if err := validateOutputTemplate(cfg); err != nil {
+		return err
+	}
+	switch cfg.Format {
+	case "", FormatText:
+		return nil
+	case FormatVertical, FormatJSON, FormatNDJSON, FormatCSV, FormatParquet, FormatMysqlbinlog, FormatDebezium, FormatMaxwell, FormatCanal, FormatAudit:
+	default:
+		return fmt.Errorf("지원하지 않는 출력 형식: %s (text, vertical, json, ndjson, csv, parquet, mysqlbinlog, debezium, maxwell, canal, audit 중 선택)", cfg.Format)
+	}
+	if cfg.Format == FormatParquet {
+		// 파일 끝의 메타데이터를 써야 읽을 수 있으므로 이어 쓰거나 끝없이 추적할 수 없음
+		if cfg.Follow {
+			return fmt.Errorf("--format parquet은 --follow와 함께 사용할 수 없습니다")
+		}
+		if cfg.AppendOutput {
+			return fmt.Errorf("--format parquet은 --append와 함께 사용할 수 없습니다")
+		}
+	}
+	if cfg.Replayable {
+		return fmt.Errorf("--replayable은 --format text에서만 사용할 수 있습니다")
+	}
+	return nil
+}
+
+func (ba *BinlogAnalyzer) outputFormat() string {
+	if ba.Config.Format == "" {
+		return FormatText
+	}
+	return ba.Config.Format
+}
+
+// 사람이 읽는 형식인지 (결과 헤더, 색상, stdout의 요약 메시지)
+// --output-template의 출력은 사용자가 정한 형식이므로 헤더와 색상을 넣지 않음
+func (ba *BinlogAnalyzer) readableFormat() bool {
+	format := ba.outputFormat()
+	return format == FormatText && ba.Config.OutputTemplate == "" || format == FormatVertical
+}
+
+// 이벤트 단위 출력기
+type eventWriter interface {
+	writeEvent(event *config.SQLEvent)
+	finish() // 출력 마무리 (형식별 종료 구문, 요약)
+}
+
+// 출력 형식에 맞는 이벤트 출력기 생성
+func (ba *BinlogAnalyzer) newEventWriter(output io.Writer) eventWriter {
+	if ba.Config.OutputTemplate != "" {
+		return newTemplateWriter(output, ba.Config.OutputTemplate, NewSQLExtractor(ba.Config, ba.schema))
+	}
+	switch ba.outputFormat() {
+	case FormatJSON:
+		return &jsonWriter{output: output}
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(output)}
+	case FormatParquet:
+		return newParquetWriter(output)
+	case FormatMysqlbinlog:
+		return &mysqlbinlogWriter{output: output, renderer: NewSQLExtractor(ba.Config, ba.schema)}
+	case FormatCSV:
+		return newCSVWriter(output, ba.Config.CSVColumns, NewSQLExtractor(ba.Config, ba.schema))
+	case FormatDebezium:
+		return newDebeziumWriter(output, ba.Config.Host)
+	case FormatMaxwell:
+		return newMaxwellWriter(output)
+	case FormatCanal:
+		return newCanalWriter(output, NewSQLExtractor(ba.Config, ba.schema))
+	case FormatAudit:
+		return newComplianceWriter(output, NewSQLExtractor(ba.Config, ba.schema), ba.Config.Host)
+	case FormatVertical:
+		return &verticalWriter{output: output, renderer: NewSQLExtractor(ba.Config, ba.schema), warnRows: ba.Config.WarnRows}
+	default:
+		return &textWriter{
+			output:     output,
+			renderer:   NewSQLExtractor(ba.Config, ba.schema),
+			replayable: ba.Config.Replayable,
+			warnRows:   ba.Config.WarnRows,
+		}
+	}
+}
+
+// 텍스트 출력기 (일반/재실행용 형식)
+type textWriter struct {
+	output     io.Writer
+	renderer   *SQLExtractor
+	replayable bool
+	started    bool
+	warnRows   int // --warn-rows
+
+	// mysqlbinlog와 동일하게 데이터베이스가 바뀔 때만 use 출력
+	state replayState
+}
+
+// 이벤트 하나 출력
+func (w *textWriter) writeEvent(event *config.SQLEvent) {
+	output := w.output
+	if w.replayable && !w.started {
+		writeReplayablePreamble(output)
+	}
+	w.started = true
+
+	fmt.Fprintf(output, "# at %d\n", event.StartPosition)
+	fmt.Fprintf(output, "#%s server id %d  end_log_pos %d\n",
+		event.Timestamp.Format("060102 15:04:05.999999"), event.ServerId, event.Position)
+	fmt.Fprintf(output, T("# Binary Log File: %s")+"\n", event.Filename)
+	if event.EventType == "QUERY" {
+		fmt.Fprintf(output, T("# Event Size: %d bytes")+"\n", event.EventSize)
+	} else {
+		fmt.Fprintf(output, T("# Event Size: %d bytes  Rows: %d")+"\n", event.EventSize, event.RowCount)
+	}
+	fmt.Fprintf(output, "# exec_time: %ds\n", event.ExecTime)
+	if event.ErrorCode != 0 {
+		fmt.Fprintf(output, "# error_code: %s\n", describeErrorCode(event.ErrorCode))
+	}
+	if exceedsWarnRows(event, w.warnRows) {
+		fmt.Fprintf(output, T("# WARNING: %d rows changed (more than --warn-rows %d)")+"\n", event.RowCount, w.warnRows)
+	}
+	if event.CapturedAt != nil {
+		fmt.Fprintf(output, T("# Capture Latency: %s")+"\n", captureLatency(event))
+	}
+
+	if columnsUnresolved(event) {
+		fmt.Fprintln(output, T("# Columns: unknown, shown as col_N (see Unresolved Table Metadata)"))
+	}
+	if pk := w.renderer.formatPrimaryKeys(event); pk != "" {
+		fmt.Fprintf(output, T("# PK: %s")+"\n", pk)
+	}
+
+	if w.replayable {
+		writeReplayableEvent(output, w.renderer, event, &w.state)
+		return
+	}
+
+	// 스키마가 비어 있는 이벤트는 이전 데이터베이스를 그대로 유지
+	if event.Database != "" && event.Database != w.state.database {
+		fmt.Fprintf(output, "use %s;\n", quoteIdentifier(event.Database))
+		w.state.database = event.Database
+	}
+
+	// row 이벤트는 원본 SQL이 아닌 재구성된 pseudo-SQL임을 명시
+	if event.EventType != "QUERY" {
+		if event.OriginalSQL != "" {
+			for _, line := range strings.Split(strings.TrimSpace(event.OriginalSQL), "\n") {
+				fmt.Fprintf(output, T("# Original SQL: %s")+"\n", line)
+			}
+		}
+		fmt.Fprintln(output, T("# Reconstructed pseudo-SQL (from row event):"))
+	}
+
+	fmt.Fprintf(output, "%s;\n\n", event.SQL)
+}
+
+// 출력 마무리 (재실행용 형식의 종료 구문)
+func (w *textWriter) finish() {
+	if w.replayable {
+		if !w.started {
+			writeReplayablePreamble(w.output)
+		}
+		writeReplayableEpilogue(w.output)
+	}
+}
+
+// 결과 헤더에 스키마 스냅샷 기록 (이후 스키마가 변경되어도 결과를 해석할 수 있도록)
+func (ba *BinlogAnalyzer) writeSchemaSnapshot(output io.Writer) {
+	tables := ba.schema.Tables()
+	missing := ba.schema.MissingTables()
+	if len(tables) == 0 && len(missing) == 0 {
+		return
+	}
+
+	fmt.Fprintf(output, T("# Schema Snapshot (information_schema, captured %s UTC):")+"\n",
+		ba.schema.CapturedAt.Format("2006-01-02 15:04:05"))
+	for _, ts := range tables {
+		fmt.Fprintf(output, "#   %s.%s: %s\n", ts.Schema, ts.Table, ts.describe())
+	}
+	for _, name := range missing {
+		fmt.Fprintf(output, T("#   %s: (not found, columns shown as col_N)")+"\n", name)
+	}
+}
+
+// 메시지 형식의 인자 자리 (%s, %-.192s, %lu 등)
+var errorMessageVerb = regexp.MustCompile(`%[-.0-9]*l*[a-z]`)
+
+// 오류 코드와 MySQL 오류 메시지 (인자 자리는 ?로 표시)
+func describeErrorCode(code uint16) string {
+	message, ok := mysql.MySQLErrName[code]
+	if !ok {
+		return fmt.Sprintf("%d", code)
+	}
+	return fmt.Sprintf("%d (%s)", code, errorMessageVerb.ReplaceAllString(message, "?"))
+}
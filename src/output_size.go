@@ -0,0 +1,72 @@
+package src
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// 크기 단위 (1024 기준, 대소문자 무시)
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1 << 40,
+	"tib": 1 << 40,
+}
+
+// ParseByteSize 1GB, 500MiB, 1.5G처럼 단위를 붙인 크기를 바이트 수로 변환 (단위가 없으면 바이트)
+func ParseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if err != nil || !ok || value < 0 {
+		return 0, fmt.Errorf("크기는 숫자에 B, KB, MB, GB, TB 단위를 붙여 지정해야 합니다: %s", s)
+	}
+	return int64(value * float64(unit)), nil
+}
+
+// 대상 파일 크기 합(예상 출력 크기)이 --confirm-over보다 크면 추출 전에 확인 (작은 서버 디스크를 결과로 채우는 일 방지)
+// 터미널이 아니어서 물을 수 없으면 --yes 없이는 중단
+func (ba *BinlogAnalyzer) confirmOutputSize(targetFiles int, targetBytes int64) error {
+	if ba.Config.ConfirmOver <= 0 || targetBytes <= ba.Config.ConfirmOver || ba.Config.AssumeYes {
+		return nil
+	}
+	// 결과를 파일이나 표준 출력으로 쓰지 않으면 확인할 필요 없음
+	if ba.discardOutput || ba.Config.Sink != "" {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf(T("대상 파일 %d개의 크기 합 %s가 --confirm-over %s보다 큽니다. 계속하려면 --yes를 지정하세요"),
+			targetFiles, formatBytes(targetBytes), formatBytes(ba.Config.ConfirmOver))
+	}
+	fmt.Fprintf(os.Stderr, T("\n\n대상 파일 %d개의 크기 합 %s가 --confirm-over %s보다 큽니다 (결과도 비슷한 크기가 될 수 있음). 계속하시겠습니까? [y/N] "),
+		targetFiles, formatBytes(targetBytes), formatBytes(ba.Config.ConfirmOver))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errors.New(T("예상 출력 크기를 확인하고 분석을 중단했습니다"))
+}
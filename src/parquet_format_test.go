@@ -0,0 +1,247 @@
+package src
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"mysqlbinlogo/config"
+)
+
+// 테스트용 이벤트 (i마다 값이 달라지고, 홀수 번째는 optional 컬럼이 비어 있음)
+func parquetTestEvent(i int) *config.SQLEvent {
+	event := &config.SQLEvent{
+		Timestamp:     time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Microsecond),
+		EventType:     "INSERT",
+		Database:      "shop",
+		SQL:           fmt.Sprintf("INSERT INTO `shop`.`orders` VALUES (%d)", i),
+		ServerId:      uint32(100 + i%3),
+		Filename:      "mysql-bin.000001",
+		StartPosition: uint32(4 + i*100),
+		Position:      uint32(104 + i*100),
+		EventSize:     100,
+		RowCount:      1,
+	}
+	if i%2 == 0 {
+		event.Table = "orders"
+		event.OriginalSQL = "INSERT INTO orders VALUES (?)"
+		event.Transaction = fmt.Sprintf("uuid:%d", i+1)
+		capturedAt := event.Timestamp.Add(time.Second)
+		event.CapturedAt = &capturedAt
+	}
+	return event
+}
+
+// 이벤트 하나를 참조 구현이 읽은 행과 같은 형태로 (null은 nil)
+func parquetExpectedRow(e *config.SQLEvent) map[string]interface{} {
+	optional := func(v string) interface{} {
+		if v == "" {
+			return nil
+		}
+		return v
+	}
+	var capturedAt interface{}
+	if e.CapturedAt != nil {
+		capturedAt = e.CapturedAt.UnixMicro()
+	}
+	return map[string]interface{}{
+		"event_time":     e.Timestamp.UnixMicro(),
+		"event_type":     e.EventType,
+		"database_name":  e.Database,
+		"table_name":     optional(e.Table),
+		"sql":            e.SQL,
+		"original_sql":   optional(e.OriginalSQL),
+		"server_id":      int64(e.ServerId),
+		"filename":       e.Filename,
+		"start_position": int64(e.StartPosition),
+		"position":       int64(e.Position),
+		"event_size":     int64(e.EventSize),
+		"exec_time":      int64(e.ExecTime),
+		"error_code":     int64(e.ErrorCode),
+		"row_count":      int64(e.RowCount),
+		"transaction_id": optional(e.Transaction),
+		"captured_at":    capturedAt,
+	}
+}
+
+// parquetWriter로 쓴 파일을 parquet-go로 열어 모든 행을 읽음
+func parquetRoundTrip(t *testing.T, events []*config.SQLEvent) (*parquet.File, []map[string]interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf)
+	for _, event := range events {
+		w.writeEvent(event)
+	}
+	w.finish()
+	if w.err != nil {
+		t.Fatalf("parquetWriter error = %v", w.err)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("parquet.OpenFile() error = %v", err)
+	}
+	columns := file.Schema().Columns()
+
+	var rows []map[string]interface{}
+	for _, group := range file.RowGroups() {
+		reader := group.Rows()
+		batch := make([]parquet.Row, 1000)
+		for {
+			n, err := reader.ReadRows(batch)
+			for _, row := range batch[:n] {
+				values := make(map[string]interface{}, len(columns))
+				for _, value := range row {
+					name := columns[value.Column()][0]
+					switch {
+					case value.IsNull():
+						values[name] = nil
+					case value.Kind() == parquet.Int64:
+						values[name] = value.Int64()
+					default:
+						values[name] = value.String()
+					}
+				}
+				rows = append(rows, values)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("ReadRows() error = %v", err)
+			}
+		}
+		reader.Close()
+	}
+	return file, rows
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    int
+		rowGroups int
+	}{
+		{"no events", 0, 0},
+		{"single event", 1, 1},
+		{"null optional columns", 2, 1},
+		{"many rows", 20001, 1},
+		{"several row groups", parquetRowGroupRows + 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []*config.SQLEvent
+			for i := 0; i < tt.events; i++ {
+				events = append(events, parquetTestEvent(i))
+			}
+			file, rows := parquetRoundTrip(t, events)
+
+			if got := file.NumRows(); got != int64(tt.events) {
+				t.Errorf("NumRows() = %d, want %d", got, tt.events)
+			}
+			if got := len(file.RowGroups()); got != tt.rowGroups {
+				t.Errorf("row groups = %d, want %d", got, tt.rowGroups)
+			}
+			if len(rows) != len(events) {
+				t.Fatalf("read %d rows, want %d", len(rows), len(events))
+			}
+			for i, event := range events {
+				if want := parquetExpectedRow(event); !reflect.DeepEqual(rows[i], want) {
+					t.Fatalf("row %d = %v, want %v", i, rows[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParquetSchema(t *testing.T) {
+	file, _ := parquetRoundTrip(t, []*config.SQLEvent{parquetTestEvent(0)})
+	fields := file.Schema().Fields()
+	if len(fields) != reflect.TypeOf(parquetRow{}).NumField() {
+		t.Fatalf("schema has %d fields, want %d", len(fields), reflect.TypeOf(parquetRow{}).NumField())
+	}
+	tests := []struct {
+		name     string
+		optional bool
+		logical  string
+	}{
+		{"event_time", false, "TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)"},
+		{"event_type", false, "STRING"},
+		{"table_name", true, "STRING"},
+		{"server_id", false, "INT(64,true)"},
+		{"transaction_id", true, "STRING"},
+		{"captured_at", true, "TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var field parquet.Field
+			for _, f := range fields {
+				if f.Name() == tt.name {
+					field = f
+				}
+			}
+			if field == nil {
+				t.Fatalf("no field %s", tt.name)
+			}
+			if field.Optional() != tt.optional {
+				t.Errorf("Optional() = %v, want %v", field.Optional(), tt.optional)
+			}
+			var logical string
+			if lt := field.Type().LogicalType(); lt != nil {
+				logical = lt.String()
+			}
+			if logical != tt.logical {
+				t.Errorf("LogicalType() = %q, want %q", logical, tt.logical)
+			}
+		})
+	}
+}
+
+func TestParquetStatistics(t *testing.T) {
+	var events []*config.SQLEvent
+	for i := 0; i < 10; i++ {
+		events = append(events, parquetTestEvent(i))
+	}
+	file, _ := parquetRoundTrip(t, events)
+	columns := file.Metadata().RowGroups[0].Columns
+
+	int64Stat := func(v int64) []byte { return binary.LittleEndian.AppendUint64(nil, uint64(v)) }
+	tests := []struct {
+		column   string
+		nulls    int64
+		min, max []byte // nil이면 통계 없음
+	}{
+		{"server_id", 0, int64Stat(100), int64Stat(102)},
+		{"start_position", 0, int64Stat(4), int64Stat(904)},
+		{"captured_at", 5, int64Stat(events[0].CapturedAt.UnixMicro()), int64Stat(events[8].CapturedAt.UnixMicro())},
+		{"table_name", 5, []byte("orders"), []byte("orders")},
+		{"sql", 0, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			var found bool
+			for _, chunk := range columns {
+				if chunk.MetaData.PathInSchema[0] != tt.column {
+					continue
+				}
+				found = true
+				stats := chunk.MetaData.Statistics
+				if stats.NullCount != tt.nulls {
+					t.Errorf("NullCount = %d, want %d", stats.NullCount, tt.nulls)
+				}
+				if !bytes.Equal(stats.MinValue, tt.min) || !bytes.Equal(stats.MaxValue, tt.max) {
+					t.Errorf("MinValue/MaxValue = %v/%v, want %v/%v", stats.MinValue, stats.MaxValue, tt.min, tt.max)
+				}
+			}
+			if !found {
+				t.Fatalf("no column chunk %s", tt.column)
+			}
+		})
+	}
+}
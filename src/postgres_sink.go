@@ -0,0 +1,112 @@
+package src
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"mysqlbinlogo/config"
+)
+
+// PostgreSQL 감사 테이블 컬럼 (INSERT 순서)
+var postgresColumns = []string{
+	"event_time", "event_type", "database_name", "table_name", "sql", "original_sql",
+	"server_id", "filename", "position", "event_size", "row_count", "captured_at",
+}
+
+// PostgreSQL 감사 테이블 싱크 (--sink postgres)
+type postgresSink struct {
+	db    *sql.DB
+	table string // 따옴표 처리된 테이블 이름
+}
+
+func newPostgresSink(ctx context.Context, cfg config.Config) (*postgresSink, error) {
+	db, err := sql.Open("postgres", cfg.PGDSN)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("PostgreSQL 연결 실패: %v", err)
+	}
+
+	sink := &postgresSink{db: db, table: quotePostgresTable(cfg.PGTable)}
+	if err := sink.createTable(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("테이블 %s 생성 실패: %v", cfg.PGTable, err)
+	}
+	return sink, nil
+}
+
+// schema.table 형태의 이름을 부분별로 따옴표 처리
+func quotePostgresTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// 감사 테이블이 없으면 생성 (같은 이벤트는 한 번만 저장되도록 파일/위치에 유니크 키)
+func (s *postgresSink) createTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	id            bigserial PRIMARY KEY,
+	event_time    timestamptz NOT NULL,
+	event_type    text NOT NULL,
+	database_name text NOT NULL,
+	table_name    text,
+	sql           text NOT NULL,
+	original_sql  text,
+	server_id     bigint NOT NULL,
+	filename      text NOT NULL,
+	position      bigint NOT NULL,
+	event_size    bigint NOT NULL,
+	row_count     integer NOT NULL,
+	captured_at   timestamptz,
+	inserted_at   timestamptz NOT NULL DEFAULT now(),
+	UNIQUE (filename, position)
+)`)
+	return err
+}
+
+// 여러 행을 한 번의 INSERT로 저장 (이미 저장된 이벤트는 건너뜀)
+func (s *postgresSink) Write(ctx context.Context, events []config.SQLEvent) error {
+	var query strings.Builder
+	query.WriteString("INSERT INTO " + s.table + " (" + strings.Join(postgresColumns, ", ") + ") VALUES ")
+
+	args := make([]interface{}, 0, len(events)*len(postgresColumns))
+	for i := range events {
+		event := &events[i]
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for j := range postgresColumns {
+			if j > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "$%d", len(args)+j+1)
+		}
+		query.WriteString(")")
+
+		args = append(args,
+			event.Timestamp, event.EventType, event.Database, nullString(event.Table), event.SQL, nullString(event.OriginalSQL),
+			int64(event.ServerId), event.Filename, int64(event.Position), int64(event.EventSize), event.RowCount,
+			event.CapturedAt)
+	}
+	query.WriteString(" ON CONFLICT (filename, position) DO NOTHING")
+
+	_, err := s.db.ExecContext(ctx, query.String(), args...)
+	return err
+}
+
+func (s *postgresSink) Close() error {
+	return s.db.Close()
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
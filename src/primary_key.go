@@ -0,0 +1,66 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// PK 주석에 표시할 최대 행 수
+const maxPrimaryKeysShown = 5
+
+// 이벤트가 변경한 각 행의 PK 값 (예: "id=1", 복합 키는 "a=1, b=2")
+// UPDATE는 before 이미지 기준이며, PK를 알 수 없으면 nil
+func (se *SQLExtractor) primaryKeyValues(event *config.SQLEvent) []string {
+	indexes := se.primaryKeyIndexes(event)
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	step := 1
+	if event.EventType == "UPDATE" {
+		step = 2
+	}
+
+	var keys []string
+	for i := 0; i < len(event.Rows); i += step {
+		row := event.Rows[i]
+		parts := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			if idx < len(row) && columnLogged(event, i, idx) {
+				parts = append(parts, fmt.Sprintf("%s=%s", columnName(event.Columns, idx), se.formatValue(row[idx])))
+			}
+		}
+		keys = append(keys, strings.Join(parts, ", "))
+	}
+	return keys
+}
+
+// PK 주석 내용 (행이 많으면 일부만 표시)
+func (se *SQLExtractor) formatPrimaryKeys(event *config.SQLEvent) string {
+	keys := se.primaryKeyValues(event)
+	if len(keys) == 0 {
+		return ""
+	}
+
+	composite := strings.Contains(keys[0], ", ")
+	shown := keys
+	if len(shown) > maxPrimaryKeysShown {
+		shown = shown[:maxPrimaryKeysShown]
+	}
+
+	parts := make([]string, len(shown))
+	for i, key := range shown {
+		if composite {
+			key = "(" + key + ")"
+		}
+		parts[i] = key
+	}
+
+	result := strings.Join(parts, "; ")
+	if len(keys) > len(shown) {
+		result += fmt.Sprintf(" (+%d more)", len(keys)-len(shown))
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// 진행률 출력 형식
+const (
+	ProgressFormatBar  = "bar"  // 터미널 진행률 바 (기본값)
+	ProgressFormatJSON = "json" // stderr로 JSON lines 출력 (자동화/웹 UI용)
+)
+
+// 진행 단계
+const (
+	progressStageConnect  = "connect"
+	progressStageFind     = "find_files"
+	progressStageExtract  = "extract"
+	progressStageFinalize = "finalize"
+	progressStageDone     = "done"
+)
+
+// JSON 진행률 기록 주기
+const progressInterval = time.Second
+
+func validateProgressFormat(format string) error {
+	switch format {
+	case "", ProgressFormatBar, ProgressFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("지원하지 않는 진행률 형식: %s (bar, json 중 선택)", format)
+	}
+}
+
+// JSON 진행률 기록 한 줄
+type progressRecord struct {
+	Time       time.Time `json:"time"`
+	Stage      string    `json:"stage"`
+	FilesDone  int       `json:"files_done"`
+	FilesTotal int       `json:"files_total"`
+	Events     int       `json:"events"`
+	Bytes      int64     `json:"bytes"`
+	BytesTotal int64     `json:"bytes_total"`
+	ETASeconds *float64  `json:"eta_seconds"` // 추정할 수 없으면 null
+}
+
+// 진행 상황을 주기적으로 기록 (--progress-format json, 서버 모드 작업 상태)
+// nil이면 모든 메서드가 아무 일도 하지 않으므로 필요할 때만 생성
+type progressReporter struct {
+	mu      sync.Mutex
+	report  func(progressRecord)
+	record  progressRecord
+	started time.Time // 추출 단계 시작 시각 (ETA 계산용)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// JSON lines로 기록하는 reporter
+func newProgressReporter(w io.Writer) *progressReporter {
+	encoder := json.NewEncoder(w)
+	return startProgressReporter(func(record progressRecord) {
+		encoder.Encode(record)
+	})
+}
+
+// 사람이 읽는 한 줄씩 기록하는 reporter (진행률 바를 그릴 수 없는 파일, 파이프, CI 로그용)
+// 매 주기 대신 단계가 바뀌거나 처리한 파일 수가 늘었을 때만 기록
+func newPlainProgressReporter(w io.Writer) *progressReporter {
+	var last progressRecord
+	return startProgressReporter(func(record progressRecord) {
+		if record.Stage == "" || record.Stage == progressStageDone ||
+			(record.Stage == last.Stage && record.FilesDone == last.FilesDone) {
+			return
+		}
+		last = record
+		fmt.Fprintln(w, T("진행: ")+progressLine(record))
+	})
+}
+
+// 진행 기록 한 줄 설명
+func progressLine(record progressRecord) string {
+	switch record.Stage {
+	case progressStageConnect:
+		return T("MySQL 연결 중...")
+	case progressStageFind:
+		return T("바이너리 로그 파일 검색 중...")
+	case progressStageFinalize:
+		return fmt.Sprintf(T("결과 정리 중... (총 %d개 이벤트)"), record.Events)
+	}
+
+	percent := 0.0
+	if record.BytesTotal > 0 {
+		percent = float64(record.Bytes) * 100 / float64(record.BytesTotal)
+	}
+	line := fmt.Sprintf(T("파일 %d/%d, %s / %s (%.0f%%), 이벤트 %d개"), record.FilesDone, record.FilesTotal,
+		formatBytes(record.Bytes), formatBytes(record.BytesTotal), percent, record.Events)
+	if record.ETASeconds != nil && record.FilesDone < record.FilesTotal {
+		line += fmt.Sprintf(T(", 남은 시간 약 %s"), time.Duration(*record.ETASeconds*float64(time.Second)).Round(time.Second))
+	}
+	return line
+}
+
+// 기록마다 report를 호출하는 reporter
+func startProgressReporter(report func(progressRecord)) *progressReporter {
+	p := &progressReporter{
+		report: report,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *progressReporter) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.emit()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// 단계 변경 (변경 즉시 한 줄 기록)
+func (p *progressReporter) Stage(stage string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.record.Stage = stage
+	if stage == progressStageExtract {
+		p.started = time.Now()
+	}
+	p.mu.Unlock()
+	p.emit()
+}
+
+// 처리할 파일 목록 설정
+func (p *progressReporter) Files(files int, bytes int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.record.FilesTotal = files
+	p.record.BytesTotal = bytes
+	p.mu.Unlock()
+}
+
+// 파일 하나 처리 완료
+func (p *progressReporter) FileDone(size int64, events int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.record.FilesDone++
+	p.record.Bytes += size
+	p.record.Events += events
+	p.mu.Unlock()
+}
+
+// 마지막 기록 후 종료
+func (p *progressReporter) Close() {
+	if p == nil {
+		return
+	}
+	p.Stage(progressStageDone)
+	close(p.stop)
+	<-p.done
+}
+
+func (p *progressReporter) emit() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	record := p.record
+	record.Time = time.Now().UTC()
+	record.ETASeconds = p.eta()
+	p.report(record)
+}
+
+// 처리한 바이트 비율로 남은 시간 추정
+func (p *progressReporter) eta() *float64 {
+	var seconds float64
+	switch {
+	case p.record.Stage == progressStageFinalize || p.record.Stage == progressStageDone:
+		// 추출 이후에는 남은 작업이 거의 없음
+	case p.record.Stage != progressStageExtract || p.record.Bytes == 0:
+		return nil
+	default:
+		elapsed := time.Since(p.started).Seconds()
+		remaining := p.record.BytesTotal - p.record.Bytes
+		seconds = elapsed * float64(remaining) / float64(p.record.Bytes)
+	}
+	return &seconds
+}
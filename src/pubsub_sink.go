@@ -0,0 +1,86 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+
+	"mysqlbinlogo/config"
+)
+
+// Google Pub/Sub 발행 싱크 (--sink pubsub)
+// 같은 테이블의 이벤트는 같은 ordering key로 발행하여 구독자가 순서대로 받도록 함
+type pubSubSink struct {
+	client *pubsub.Client
+	topic  *pubsub.Topic
+}
+
+func newPubSubSink(ctx context.Context, cfg config.Config) (*pubSubSink, error) {
+	project := cfg.PubSubProject
+	if project == "" {
+		project = pubsub.DetectProjectID
+	}
+	client, err := pubsub.NewClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := client.Topic(cfg.PubSubTopic)
+	topic.EnableMessageOrdering = true
+
+	// 토픽이 없으면 첫 발행에서야 실패하므로 미리 확인
+	exists, err := topic.Exists(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("토픽 %s 확인 실패: %v", topic, err)
+	}
+	if !exists {
+		client.Close()
+		return nil, fmt.Errorf("토픽 %s가 없습니다", topic)
+	}
+	return &pubSubSink{client: client, topic: topic}, nil
+}
+
+// 이벤트를 JSON 메시지로 발행하고 모두 확인될 때까지 대기
+func (s *pubSubSink) Write(ctx context.Context, events []config.SQLEvent) error {
+	results := make([]*pubsub.PublishResult, len(events))
+	keys := make([]string, len(events))
+	for i := range events {
+		event := &events[i]
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		keys[i] = eventTopicKey(event)
+		results[i] = s.topic.Publish(ctx, &pubsub.Message{
+			Data:        data,
+			OrderingKey: keys[i],
+			Attributes: map[string]string{
+				"database":   event.Database,
+				"table":      event.Table,
+				"event_type": event.EventType,
+				"position":   eventKey(event),
+			},
+		})
+	}
+
+	var firstErr error
+	for i, result := range results {
+		if _, err := result.Get(ctx); err != nil {
+			// 실패한 ordering key는 이후 발행이 거부되므로 다음 전송을 위해 재개
+			s.topic.ResumePublish(keys[i])
+			if firstErr == nil {
+				firstErr = fmt.Errorf("메시지 발행 실패 (%s): %v", keys[i], err)
+			}
+		}
+	}
+	return firstErr
+}
+
+func (s *pubSubSink) Close() error {
+	s.topic.Stop()
+	return s.client.Close()
+}
@@ -0,0 +1,140 @@
+package src
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// Pushgateway 그룹의 job 이름과 전송 타임아웃
+const (
+	pushgatewayJob     = "mysqlbinlogo"
+	pushgatewayTimeout = 10 * time.Second
+)
+
+func validatePushgateway(cfg config.Config) error {
+	if cfg.PushgatewayURL == "" {
+		return nil
+	}
+	if cfg.Follow {
+		return fmt.Errorf("--pushgateway-url은 --follow와 함께 사용할 수 없습니다")
+	}
+	u, err := url.Parse(cfg.PushgatewayURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("--pushgateway-url은 http:// 또는 https:// URL이어야 합니다: %s", cfg.PushgatewayURL)
+	}
+	return nil
+}
+
+// Pushgateway 그룹의 cluster 레이블 (지정하지 않으면 host:port)
+func pushgatewayCluster(cfg config.Config) string {
+	if cfg.PushgatewayCluster != "" {
+		return cfg.PushgatewayCluster
+	}
+	return fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+}
+
+// 실행 종료 시 지표를 Pushgateway로 전송 (실패해도 분석 결과에는 영향 없이 경고만 출력)
+func (ba *BinlogAnalyzer) pushMetrics(runErr error) {
+	if ba.Config.PushgatewayURL == "" {
+		return
+	}
+
+	var body bytes.Buffer
+	ba.run.writeMetrics(&body, len(ba.warnings), runErr)
+	if err := pushToGateway(ba.Config, body.Bytes()); err != nil {
+		logrus.Warnf("Pushgateway 전송 실패: %v", err)
+		return
+	}
+	logrus.Infof(T("Metrics pushed to %s"), ba.Config.PushgatewayURL)
+}
+
+// 실행 지표 (Prometheus text exposition format)
+func (s *runStats) writeMetrics(w io.Writer, warnings int, runErr error) {
+	success := 1
+	if runErr != nil {
+		success = 0
+	}
+	gauge(w, "mysqlbinlogo_run_success", "1 if the run finished without an error", success)
+	gauge(w, "mysqlbinlogo_run_duration_seconds", "Wall time of the run", time.Since(s.started).Seconds())
+	gauge(w, "mysqlbinlogo_run_finished_timestamp_seconds", "Unix time the run finished", float64(time.Now().UnixNano())/1e9)
+	gauge(w, "mysqlbinlogo_files_scanned", "Binary log files in the analyzed window", len(s.files))
+	gauge(w, "mysqlbinlogo_files_failed", "Files that failed and are missing from the result", s.failedFiles())
+	gauge(w, "mysqlbinlogo_warnings", "Reasons the result may be incomplete", warnings)
+
+	groups := make([]eventGroup, 0, len(s.events))
+	for group := range s.events {
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b eventGroup) int {
+		return strings.Compare(a.eventType+"\x00"+a.database+"\x00"+a.table, b.eventType+"\x00"+b.database+"\x00"+b.table)
+	})
+
+	fmt.Fprintln(w, "# HELP mysqlbinlogo_events Result events per type and table")
+	fmt.Fprintln(w, "# TYPE mysqlbinlogo_events gauge")
+	for _, group := range groups {
+		fmt.Fprintf(w, "mysqlbinlogo_events%s %d\n", group.labels(), s.events[group])
+	}
+	fmt.Fprintln(w, "# HELP mysqlbinlogo_rows Rows changed by result row events per type and table")
+	fmt.Fprintln(w, "# TYPE mysqlbinlogo_rows gauge")
+	for _, group := range groups {
+		if group.eventType != "QUERY" {
+			fmt.Fprintf(w, "mysqlbinlogo_rows%s %d\n", group.labels(), s.rows[group])
+		}
+	}
+}
+
+func gauge(w io.Writer, name, help string, value interface{}) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %v\n", name, help, name, name, value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (g eventGroup) labels() string {
+	return fmt.Sprintf(`{type="%s",database="%s",table="%s"}`,
+		labelEscaper.Replace(g.eventType), labelEscaper.Replace(g.database), labelEscaper.Replace(g.table))
+}
+
+// 그룹(job, cluster, 분석 구간)의 지표를 통째로 교체 (PUT)
+func pushToGateway(cfg config.Config, body []byte) error {
+	endpoint := strings.TrimSuffix(cfg.PushgatewayURL, "/") + "/metrics/job/" + url.PathEscape(pushgatewayJob) +
+		groupingLabel("cluster", pushgatewayCluster(cfg)) +
+		groupingLabel("window_start", cfg.StartTime.UTC().Format(time.RFC3339)) +
+		groupingLabel("window_end", cfg.EndTime.UTC().Format(time.RFC3339))
+
+	request, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	client := &http.Client{Timeout: pushgatewayTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// URL 경로의 그룹 레이블 (/가 들어간 값은 Pushgateway 규칙대로 base64로 인코딩)
+func groupingLabel(name, value string) string {
+	if strings.Contains(value, "/") {
+		return "/" + name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return "/" + name + "/" + url.PathEscape(value)
+}
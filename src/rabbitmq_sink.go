@@ -0,0 +1,97 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"mysqlbinlogo/config"
+)
+
+// RabbitMQ exchange 발행 싱크 (--sink rabbitmq)
+// routing key는 db.table (쿼리 이벤트는 데이터베이스)이므로 topic exchange에서 "shop.*" 같은 패턴으로 구독 가능
+type rabbitMQSink struct {
+	conn       *amqp.Connection
+	channel    *amqp.Channel
+	exchange   string
+	persistent bool
+}
+
+func newRabbitMQSink(ctx context.Context, cfg config.Config) (*rabbitMQSink, error) {
+	conn, err := amqp.Dial(cfg.AMQPURL)
+	if err != nil {
+		return nil, fmt.Errorf("RabbitMQ 연결 실패: %v", err)
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// 이미 있는 exchange는 종류가 같으면 그대로 사용 (다르면 오류)
+	if err := channel.ExchangeDeclare(cfg.AMQPExchange, cfg.AMQPExchangeType, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("exchange %s 선언 실패: %v", cfg.AMQPExchange, err)
+	}
+
+	// 브로커가 메시지를 받았는지 확인하기 위해 publisher confirm 사용
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &rabbitMQSink{
+		conn:       conn,
+		channel:    channel,
+		exchange:   cfg.AMQPExchange,
+		persistent: cfg.AMQPPersistent,
+	}, nil
+}
+
+// 이벤트를 JSON 메시지로 발행하고 브로커 확인까지 대기
+func (s *rabbitMQSink) Write(ctx context.Context, events []config.SQLEvent) error {
+	deliveryMode := amqp.Transient
+	if s.persistent {
+		deliveryMode = amqp.Persistent
+	}
+
+	confirms := make([]*amqp.DeferredConfirmation, 0, len(events))
+	for i := range events {
+		event := &events[i]
+		body, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		confirm, err := s.channel.PublishWithDeferredConfirmWithContext(ctx, s.exchange, eventTopicKey(event), false, false, amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: deliveryMode,
+			MessageId:    eventKey(event),
+			Timestamp:    event.Timestamp,
+			Type:         event.EventType,
+			Body:         body,
+		})
+		if err != nil {
+			return fmt.Errorf("메시지 발행 실패: %v", err)
+		}
+		confirms = append(confirms, confirm)
+	}
+
+	for _, confirm := range confirms {
+		acked, err := confirm.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+		if !acked {
+			return fmt.Errorf("브로커가 메시지를 거부했습니다 (nack)")
+		}
+	}
+	return nil
+}
+
+func (s *rabbitMQSink) Close() error {
+	s.channel.Close()
+	return s.conn.Close()
+}
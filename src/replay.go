@@ -0,0 +1,335 @@
+package src
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 실행 오류 처리 방식 (--on-error)
+const (
+	ReplayOnErrorStop = "stop" // 현재 트랜잭션을 롤백하고 중단 (기본값)
+	ReplayOnErrorSkip = "skip" // 실패한 이벤트를 기록하고 계속 진행
+)
+
+// Replayer 분석으로 추출한 SQL을 대상 데이터베이스에 실행 (replay 서브커맨드)
+type Replayer struct {
+	Config            config.Config // 분석할 원본 서버와 시간 범위, 필터
+	TargetDSN         string        // 실행 대상 (go-sql-driver DSN)
+	BatchTransactions int           // 한 번에 커밋하는 원본 트랜잭션 수
+	DryRun            bool          // 실행하지 않고 실행할 SQL만 출력
+	OnError           string
+	Output            io.Writer // --dry-run 출력 대상 (없으면 stdout)
+
+	CheckConflicts bool   // 실행 전 대상 행을 before 이미지와 비교하여 충돌한 이벤트는 건너뜀
+	ConflictReport string // 충돌 보고서 파일 (JSON lines)
+}
+
+// 실행 결과 요약
+type replayStats struct {
+	events       int
+	statements   int
+	transactions int // 원본 트랜잭션 수
+	commits      int
+	skipped      int
+	conflicts    int // 충돌로 건너뛴 이벤트 수
+}
+
+// 대상에 SQL을 실행하는 세션 (--dry-run이면 출력만)
+type replaySession struct {
+	db     *sql.DB
+	conn   *sql.Conn // USE, SET TIMESTAMP가 이어지도록 연결 하나만 사용 (--dry-run에서는 충돌 확인용)
+	dryRun bool
+	output io.Writer
+
+	conflicts *json.Encoder // 충돌 보고서 (--check-conflicts)
+}
+
+func (s *replaySession) exec(ctx context.Context, query string) error {
+	if s.dryRun {
+		_, err := fmt.Fprintf(s.output, "%s;\n", query)
+		return err
+	}
+	_, err := s.conn.ExecContext(ctx, query)
+	return err
+}
+
+func validateReplay(r *Replayer) error {
+	switch r.OnError {
+	case ReplayOnErrorStop, ReplayOnErrorSkip:
+	default:
+		return fmt.Errorf("지원하지 않는 오류 처리 방식: %s (stop, skip 중 선택)", r.OnError)
+	}
+	if r.BatchTransactions < 1 {
+		return fmt.Errorf("--batch-transactions는 1 이상이어야 합니다")
+	}
+	if r.TargetDSN == "" && (!r.DryRun || r.CheckConflicts) {
+		return fmt.Errorf("--target-dsn을 지정해야 합니다 (--check-conflicts 없는 --dry-run 제외)")
+	}
+	if r.CheckConflicts && r.ConflictReport == "" {
+		return fmt.Errorf("--check-conflicts는 --conflict-report가 필요합니다")
+	}
+	return nil
+}
+
+// Run 분석 후 결과 이벤트를 순서대로 실행 (ctx 취소 시 진행 중인 트랜잭션 롤백)
+func (r *Replayer) Run(ctx context.Context) error {
+	if err := validateReplay(r); err != nil {
+		return err
+	}
+
+	// 대상 연결을 먼저 확인하여 분석을 마친 뒤에야 실패하는 일이 없도록 함
+	session := &replaySession{dryRun: r.DryRun, output: r.Output}
+	if session.output == nil {
+		session.output = os.Stdout
+	}
+	if r.TargetDSN != "" {
+		if err := session.open(ctx, r.TargetDSN); err != nil {
+			return err
+		}
+		defer session.close()
+	}
+	if r.CheckConflicts {
+		report, err := os.Create(r.ConflictReport)
+		if err != nil {
+			return fmt.Errorf("충돌 보고서 파일 생성 실패: %v", err)
+		}
+		defer report.Close()
+		session.conflicts = json.NewEncoder(report)
+	}
+
+	cfg := r.Config
+	cfg.Sink = ""
+	cfg.SQLiteFile = ""
+	cfg.Format = ""
+	cfg.CSVColumns = nil
+	cfg.OutputTemplate = ""
+	cfg.OutputFile = ""
+	cfg.Compress = ""
+	cfg.OutputRotateSize = 0
+	cfg.OutputRotateEvents = 0
+	cfg.SplitBy = ""
+	cfg.Timeline = ""
+	cfg.CapacityReport = ""
+	cfg.PushgatewayURL = ""
+	cfg.SummaryJSON = ""
+	cfg.HistoryDB = ""
+
+	var events []config.SQLEvent
+	analyzer := &BinlogAnalyzer{
+		Config:        cfg,
+		messages:      os.Stderr,
+		discardOutput: true,
+		onResults: func(results []config.SQLEvent) error {
+			events = results
+			return nil
+		},
+	}
+	if err := analyzer.Analyze(ctx); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	// 결과는 시간순이므로 원본 트랜잭션이 이어지도록 binlog 순서로 다시 정렬
+	slices.SortFunc(events, func(a, b config.SQLEvent) int {
+		return analyzer.compareBinlogOrder(&a, &b)
+	})
+
+	stats, err := r.apply(ctx, session, NewSQLExtractor(cfg, analyzer.schema), events)
+	mode := "실행"
+	if r.DryRun {
+		mode = "출력 (dry-run)"
+	}
+	logrus.Infof("replay %s: 이벤트 %d개, SQL %d개, 트랜잭션 %d개 (커밋 %d회), 건너뜀 %d개, 충돌 %d개",
+		mode, stats.events, stats.statements, stats.transactions, stats.commits, stats.skipped, stats.conflicts)
+	if stats.conflicts > 0 {
+		logrus.Warnf("충돌한 이벤트 %d개를 실행하지 않았습니다: %s", stats.conflicts, r.ConflictReport)
+	}
+	return err
+}
+
+func (s *replaySession) open(ctx context.Context, dsn string) error {
+	driverCfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return fmt.Errorf("--target-dsn 형식 오류: %v", err)
+	}
+	connector, err := mysql.NewConnector(driverCfg)
+	if err != nil {
+		return err
+	}
+	s.db = sql.OpenDB(connector)
+
+	s.conn, err = s.db.Conn(ctx)
+	if err != nil {
+		s.db.Close()
+		return fmt.Errorf("대상 데이터베이스 연결 실패: %v", err)
+	}
+	return nil
+}
+
+func (s *replaySession) close() {
+	s.conn.Close()
+	s.db.Close()
+}
+
+// 원본 트랜잭션 경계를 지켜 실행 (BatchTransactions개의 트랜잭션을 한 번에 커밋)
+func (r *Replayer) apply(ctx context.Context, session *replaySession, renderer *SQLExtractor, events []config.SQLEvent) (replayStats, error) {
+	var stats replayStats
+
+	// AUTO_INCREMENT 컬럼의 0 값 유지 (대상의 sql_mode에 추가하여 STRICT_TRANS_TABLES 등은 그대로 둠)
+	if err := session.exec(ctx, "SET SESSION sql_mode=CONCAT_WS(',', NULLIF(@@sql_mode, ''), 'NO_AUTO_VALUE_ON_ZERO')"); err != nil {
+		return stats, fmt.Errorf("세션 설정 실패: %v", err)
+	}
+
+	var state replayState
+	inTransaction := false
+	currentTransaction := ""
+	pending := 0 // 현재 배치에서 끝난 원본 트랜잭션 수
+	rollback := func() {
+		if inTransaction {
+			// 취소된 뒤에도 롤백되도록 ctx와 분리
+			session.exec(context.WithoutCancel(ctx), "ROLLBACK")
+			inTransaction = false
+		}
+	}
+
+	for i := range events {
+		event := &events[i]
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return stats, err
+		}
+
+		// 원본 트랜잭션이 바뀌는 곳에서만 커밋
+		transaction := event.Transaction
+		if transaction == "" {
+			transaction = eventKey(event)
+		}
+		if transaction != currentTransaction {
+			if currentTransaction != "" {
+				stats.transactions++
+				pending++
+			}
+			currentTransaction = transaction
+			if inTransaction && pending >= r.BatchTransactions {
+				if err := session.exec(ctx, "COMMIT"); err != nil {
+					return stats, fmt.Errorf("커밋 실패: %v", err)
+				}
+				inTransaction = false
+				stats.commits++
+				pending = 0
+			}
+		}
+
+		statements, err := renderer.formatReplayableSQL(event)
+		if err != nil {
+			if err := r.handleError(event, err, &stats); err != nil {
+				rollback()
+				return stats, err
+			}
+			continue
+		}
+
+		if !inTransaction {
+			if err := session.exec(ctx, "BEGIN"); err != nil {
+				return stats, err
+			}
+			inTransaction = true
+		}
+
+		// 대상 행이 before 이미지와 다르면 실행하지 않고 보고서에 기록
+		if r.CheckConflicts {
+			conflicts, err := r.checkConflicts(ctx, session, renderer, event)
+			if err != nil {
+				if err := r.handleError(event, err, &stats); err != nil {
+					rollback()
+					return stats, err
+				}
+				continue
+			}
+			if len(conflicts) > 0 {
+				stats.conflicts++
+				for _, conflict := range conflicts {
+					if err := session.conflicts.Encode(conflict); err != nil {
+						rollback()
+						return stats, fmt.Errorf("충돌 보고서 기록 실패: %v", err)
+					}
+				}
+				continue
+			}
+		}
+
+		// skip이면 실패한 이벤트의 앞선 문장만 되돌리도록 이벤트마다 savepoint 사용
+		if r.OnError == ReplayOnErrorSkip {
+			if err := session.exec(ctx, "SAVEPOINT replay_event"); err != nil {
+				return stats, err
+			}
+		}
+		if err := r.applyEvent(ctx, session, event, statements, &state); err != nil {
+			if err := r.handleError(event, err, &stats); err != nil {
+				rollback()
+				return stats, err
+			}
+			// DDL의 암묵적 커밋 뒤에는 savepoint가 없으므로 실패해도 무시
+			session.exec(ctx, "ROLLBACK TO SAVEPOINT replay_event")
+			continue
+		}
+		stats.events++
+		stats.statements += len(statements)
+	}
+
+	if currentTransaction != "" {
+		stats.transactions++
+	}
+	if inTransaction {
+		if err := session.exec(ctx, "COMMIT"); err != nil {
+			return stats, fmt.Errorf("커밋 실패: %v", err)
+		}
+		stats.commits++
+	}
+	return stats, nil
+}
+
+// 이벤트 하나 실행 (데이터베이스가 바뀌면 USE, 원래 시각으로 SET TIMESTAMP, --session-context면 원본 세션 변수)
+func (r *Replayer) applyEvent(ctx context.Context, session *replaySession, event *config.SQLEvent, statements []string, state *replayState) error {
+	if event.Database != "" && event.Database != state.database {
+		if err := session.exec(ctx, "USE "+quoteIdentifier(event.Database)); err != nil {
+			return err
+		}
+		state.database = event.Database
+	}
+	if err := session.exec(ctx, "SET TIMESTAMP="+replayTimestamp(event.Timestamp)); err != nil {
+		return err
+	}
+	if r.Config.EmitSessionContext {
+		statements = append(state.sessionStatements(event.Session), statements...)
+	}
+	for _, statement := range statements {
+		if err := session.exec(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --on-error에 따라 중단할 오류를 반환 (skip이면 기록만 하고 nil)
+func (r *Replayer) handleError(event *config.SQLEvent, err error, stats *replayStats) error {
+	location := fmt.Sprintf("%s:%d %s", event.Filename, event.Position, event.EventType)
+	if r.OnError == ReplayOnErrorSkip {
+		stats.skipped++
+		logrus.Warnf("이벤트 건너뜀 (%s): %v", location, err)
+		return nil
+	}
+	return fmt.Errorf("이벤트 실행 실패 (%s): %v", location, err)
+}
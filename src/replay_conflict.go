@@ -0,0 +1,139 @@
+package src
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// 충돌 종류
+const (
+	conflictMissing  = "missing"  // UPDATE/DELETE 대상 행이 없음
+	conflictDiverged = "diverged" // 대상 행의 값이 before 이미지와 다름
+	conflictExists   = "exists"   // INSERT할 PK의 행이 이미 있음
+)
+
+// 충돌 보고서의 한 줄 (--conflict-report, JSON lines)
+type replayConflict struct {
+	Position  string                   `json:"position"` // filename:position
+	EventType string                   `json:"event_type"`
+	Table     string                   `json:"table"`
+	Row       int                      `json:"row"` // 이벤트 안에서의 행 번호
+	Kind      string                   `json:"kind"`
+	Key       string                   `json:"key"`               // 행을 찾은 WHERE 조건
+	Columns   map[string]conflictValue `json:"columns,omitempty"` // diverged인 컬럼
+}
+
+type conflictValue struct {
+	Expected interface{} `json:"expected"` // before 이미지의 값
+	Actual   *string     `json:"actual"`   // 대상의 현재 값 (NULL이면 null)
+}
+
+// 대상의 현재 행을 before 이미지와 비교 (PK로 조회, 비교는 서버의 <=> 연산으로 타입 변환을 맡김)
+func (r *Replayer) checkConflicts(ctx context.Context, session *replaySession, renderer *SQLExtractor, event *config.SQLEvent) ([]replayConflict, error) {
+	if event.Columns == nil {
+		return nil, nil
+	}
+
+	step := 1
+	switch event.EventType {
+	case "INSERT", "DELETE":
+	case "UPDATE":
+		step = 2
+	default:
+		return nil, nil
+	}
+
+	var conflicts []replayConflict
+	for i, row := 0, 0; i < len(event.Rows); i, row = i+step, row+1 {
+		conflict := replayConflict{
+			Position:  fmt.Sprintf("%s:%d", event.Filename, event.Position),
+			EventType: event.EventType,
+			Table:     qualifiedTableName(event),
+			Row:       row,
+			Key:       renderer.rowCondition(event, i),
+		}
+
+		var err error
+		if event.EventType == "INSERT" {
+			err = r.checkInsertRow(ctx, session, renderer, event, &conflict)
+		} else {
+			err = r.checkBeforeImage(ctx, session, renderer, event, i, &conflict)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("충돌 확인 실패 (%s): %v", conflict.Table, err)
+		}
+		if conflict.Kind != "" {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+// INSERT할 행의 PK가 이미 있는지 확인 (PK를 모르면 확인하지 않음)
+func (r *Replayer) checkInsertRow(ctx context.Context, session *replaySession, renderer *SQLExtractor, event *config.SQLEvent, conflict *replayConflict) error {
+	if len(renderer.primaryKeyIndexes(event)) == 0 {
+		return nil
+	}
+
+	var found int
+	err := session.conn.QueryRowContext(ctx, fmt.Sprintf("SELECT 1 FROM %s WHERE %s LIMIT 1",
+		quotedTableName(event), conflict.Key)).Scan(&found)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+	conflict.Kind = conflictExists
+	return nil
+}
+
+// UPDATE/DELETE 대상 행이 before 이미지와 같은지 확인 (before 이미지에 기록된 컬럼만 비교)
+func (r *Replayer) checkBeforeImage(ctx context.Context, session *replaySession, renderer *SQLExtractor, event *config.SQLEvent, index int, conflict *replayConflict) error {
+	image := event.Rows[index]
+	columns := loggedColumns(event, index)
+
+	// 컬럼마다 현재 값과 일치 여부를 함께 조회
+	selects := make([]string, 0, len(columns)*2)
+	for _, j := range columns {
+		column := quoteIdentifier(columnName(event.Columns, j))
+		selects = append(selects, column, fmt.Sprintf("%s <=> %s", column, renderer.formatLiteral(image[j])))
+	}
+
+	actual := make([]sql.NullString, len(columns))
+	equal := make([]bool, len(columns))
+	dest := make([]interface{}, 0, len(columns)*2)
+	for k := range columns {
+		dest = append(dest, &actual[k], &equal[k])
+	}
+
+	err := session.conn.QueryRowContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE %s LIMIT 1",
+		strings.Join(selects, ", "), quotedTableName(event), conflict.Key)).Scan(dest...)
+	switch {
+	case err == sql.ErrNoRows:
+		conflict.Kind = conflictMissing
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for k, j := range columns {
+		if equal[k] {
+			continue
+		}
+		if conflict.Columns == nil {
+			conflict.Kind = conflictDiverged
+			conflict.Columns = make(map[string]conflictValue)
+		}
+		diff := conflictValue{Expected: jsonValue(image[j])}
+		if actual[k].Valid {
+			diff.Actual = &actual[k].String
+		}
+		conflict.Columns[columnName(event.Columns, j)] = diff
+	}
+	return nil
+}
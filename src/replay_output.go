@@ -0,0 +1,74 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// mysqlbinlog 출력과 동일한 세션 초기화 구문
+func writeReplayablePreamble(output io.Writer) {
+	fmt.Fprintf(output, "/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=1*/;\n")
+	fmt.Fprintf(output, "/*!50003 SET @OLD_COMPLETION_TYPE=@@COMPLETION_TYPE,COMPLETION_TYPE=0*/;\n")
+	fmt.Fprintf(output, "DELIMITER /*!*/;\n")
+	fmt.Fprintf(output, "SET @@session.sql_mode='NO_AUTO_VALUE_ON_ZERO'/*!*/;\n")
+	fmt.Fprintf(output, "/*!\\C utf8mb4 *//*!*/;\n")
+	fmt.Fprintf(output, "SET NAMES utf8mb4/*!*/;\n\n")
+}
+
+// SET TIMESTAMP 값 (mysqlbinlog처럼 마이크로초가 있으면 소수점 6자리, NOW(6) 등이 원본과 같은 값이 됨)
+func replayTimestamp(t time.Time) string {
+	if micro := t.Nanosecond() / 1000; micro != 0 {
+		return fmt.Sprintf("%d.%06d", t.Unix(), micro)
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// 재실행 출력에서 이벤트 사이에 이어지는 세션 상태 (바뀔 때만 다시 설정)
+type replayState struct {
+	database string
+	session  config.SessionContext // 마지막으로 설정한 원본 세션 변수 (--session-context)
+}
+
+// 이벤트 하나를 재실행 가능한 형태로 출력
+func writeReplayableEvent(output io.Writer, renderer *SQLExtractor, event *config.SQLEvent, state *replayState) {
+	statements, err := renderer.formatReplayableSQL(event)
+	if err != nil {
+		// 재실행할 수 없는 이벤트는 주석으로만 남김
+		fmt.Fprintf(output, "# Skipped (not replayable): %v\n", err)
+		for _, line := range strings.Split(event.SQL, "\n") {
+			fmt.Fprintf(output, "# %s\n", line)
+		}
+		fmt.Fprintln(output)
+		return
+	}
+
+	if event.Database != "" && event.Database != state.database {
+		fmt.Fprintf(output, "use %s/*!*/;\n", quoteIdentifier(event.Database))
+		state.database = event.Database
+	}
+	fmt.Fprintf(output, "SET TIMESTAMP=%s/*!*/;\n", replayTimestamp(event.Timestamp))
+	if renderer.config.EmitSessionContext {
+		for _, statement := range state.sessionStatements(event.Session) {
+			fmt.Fprintf(output, "%s/*!*/;\n", statement)
+		}
+	}
+
+	for _, statement := range statements {
+		fmt.Fprintf(output, "%s\n/*!*/;\n", statement)
+	}
+	fmt.Fprintln(output)
+}
+
+// 출력 종료 구문 (미완료 트랜잭션 롤백 및 세션 설정 복원)
+func writeReplayableEpilogue(output io.Writer) {
+	fmt.Fprintf(output, "DELIMITER ;\n")
+	fmt.Fprintf(output, "# End of log file\n")
+	fmt.Fprintf(output, "ROLLBACK /* added by mysqlbinlogo */;\n")
+	fmt.Fprintf(output, "/*!50003 SET COMPLETION_TYPE=@OLD_COMPLETION_TYPE*/;\n")
+	fmt.Fprintf(output, "/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=0*/;\n")
+}
@@ -0,0 +1,27 @@
+package src
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want string
+	}{
+		{"whole seconds", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), "1705312800"},
+		{"microseconds", time.Date(2024, 1, 15, 10, 0, 0, 123456000, time.UTC), "1705312800.123456"},
+		{"leading zeros", time.Date(2024, 1, 15, 10, 0, 0, 5000, time.UTC), "1705312800.000005"},
+		{"below a microsecond", time.Date(2024, 1, 15, 10, 0, 0, 999, time.UTC), "1705312800"},
+		{"other time zone", time.Date(2024, 1, 15, 19, 0, 0, 500000000, time.FixedZone("KST", 9*3600)), "1705312800.500000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replayTimestamp(tt.time); got != tt.want {
+				t.Errorf("replayTimestamp() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// 재실행 가능한 SQL 생성 (row 이벤트는 모든 행을 완전한 SQL로 변환)
+func (se *SQLExtractor) formatReplayableSQL(event *config.SQLEvent) ([]string, error) {
+	switch event.EventType {
+	case "INSERT":
+		if event.Columns == nil && len(event.Rows) > 0 && imageMinimal(event, 0) {
+			return nil, fmt.Errorf("%s의 컬럼 이름을 알 수 없어 일부 컬럼만 기록된 행을 INSERT할 수 없습니다", qualifiedTableName(event))
+		}
+		return []string{se.replayableInsert(event)}, nil
+	case "UPDATE", "DELETE":
+		if event.Columns == nil {
+			return nil, fmt.Errorf("%s의 컬럼 이름을 알 수 없어 WHERE 조건을 만들 수 없습니다", qualifiedTableName(event))
+		}
+		if event.EventType == "UPDATE" {
+			return se.replayableUpdate(event), nil
+		}
+		return se.replayableDelete(event), nil
+	default:
+		return []string{event.SQL}, nil
+	}
+}
+
+// 다중 행 INSERT (기록되지 않은 컬럼과 생성 컬럼은 빼서 서버가 채우도록 함, 빠진 컬럼은 이벤트 안에서 모든 행이 같음)
+func (se *SQLExtractor) replayableInsert(event *config.SQLEvent) string {
+	var indexes []int
+	if len(event.Rows) > 0 {
+		columns := se.snapshotColumns(event)
+		for _, j := range loggedColumns(event, 0) {
+			if columns == nil || !columns[j].Generated {
+				indexes = append(indexes, j)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(quotedTableName(event))
+	if event.Columns != nil {
+		names := make([]string, len(indexes))
+		for k, j := range indexes {
+			names[k] = columnName(event.Columns, j)
+		}
+		sb.WriteString(" (")
+		sb.WriteString(quoteIdentifiers(names))
+		sb.WriteString(")")
+	}
+	sb.WriteString(" VALUES ")
+
+	for i, row := range event.Rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		values := make([]string, 0, len(indexes))
+		for _, j := range indexes {
+			if j < len(row) {
+				values = append(values, se.formatLiteral(row[j]))
+			}
+		}
+		sb.WriteString("(")
+		sb.WriteString(strings.Join(values, ", "))
+		sb.WriteString(")")
+	}
+
+	return sb.String()
+}
+
+// 행마다 UPDATE (before 이미지로 대상 행 지정, 생성 컬럼은 서버가 다시 계산하므로 SET에서 제외)
+func (se *SQLExtractor) replayableUpdate(event *config.SQLEvent) []string {
+	columns := se.snapshotColumns(event)
+	var statements []string
+	for i := 0; i+1 < len(event.Rows); i += 2 {
+		after := event.Rows[i+1]
+
+		assignments := make([]string, 0, len(after))
+		for j, val := range after {
+			if !columnLogged(event, i+1, j) || (columns != nil && columns[j].Generated) {
+				continue
+			}
+			column := quoteIdentifier(columnName(event.Columns, j))
+			if update, ok := val.(jsonPartialUpdate); ok {
+				assignments = append(assignments, fmt.Sprintf("%s=%s", column, update.expression(column, se.formatLiteral)))
+			} else {
+				assignments = append(assignments, fmt.Sprintf("%s=%s", column, se.formatLiteral(val)))
+			}
+		}
+
+		statements = append(statements, fmt.Sprintf("UPDATE %s SET %s WHERE %s LIMIT 1",
+			quotedTableName(event), strings.Join(assignments, ", "), se.rowCondition(event, i)))
+	}
+	return statements
+}
+
+// 행마다 DELETE
+func (se *SQLExtractor) replayableDelete(event *config.SQLEvent) []string {
+	statements := make([]string, 0, len(event.Rows))
+	for i := range event.Rows {
+		statements = append(statements, fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT 1",
+			quotedTableName(event), se.rowCondition(event, i)))
+	}
+	return statements
+}
+
+// image번째 행 이미지를 식별하는 WHERE 조건 (PK를 알면 PK, 모르면 기록된 전체 컬럼)
+func (se *SQLExtractor) rowCondition(event *config.SQLEvent, image int) string {
+	row := event.Rows[image]
+	indexes := se.primaryKeyIndexes(event)
+	if len(indexes) == 0 {
+		indexes = make([]int, len(row))
+		for i := range row {
+			indexes[i] = i
+		}
+	}
+
+	conditions := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		if i >= len(row) || !columnLogged(event, image, i) {
+			continue
+		}
+		column := quoteIdentifier(columnName(event.Columns, i))
+		if row[i] == nil {
+			conditions = append(conditions, column+" IS NULL")
+		} else {
+			conditions = append(conditions, fmt.Sprintf("%s=%s", column, se.formatLiteral(row[i])))
+		}
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// 이벤트 컬럼 구성이 스냅샷과 일치할 때 PK 컬럼 위치 반환
+func (se *SQLExtractor) primaryKeyIndexes(event *config.SQLEvent) []int {
+	var indexes []int
+	for i, col := range se.snapshotColumns(event) {
+		if col.PrimaryKey {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// 이벤트 컬럼 구성이 스냅샷과 일치할 때 스냅샷의 컬럼 정보 (위치가 이벤트의 컬럼과 같음)
+func (se *SQLExtractor) snapshotColumns(event *config.SQLEvent) []ColumnInfo {
+	ts := se.schema.Table(event.Database, event.Table)
+	if ts == nil || event.Columns == nil || len(ts.Columns) != len(event.Columns) {
+		return nil
+	}
+	for i, col := range ts.Columns {
+		if !strings.EqualFold(col.Name, event.Columns[i]) {
+			// DDL 히스토리로 구성이 달라진 경우 스냅샷의 컬럼 정보를 쓸 수 없음
+			return nil
+		}
+	}
+	return ts.Columns
+}
+
+// `schema`.`table` 형태의 테이블 이름
+func quotedTableName(event *config.SQLEvent) string {
+	if event.Database != "" {
+		return quoteIdentifier(event.Database) + "." + quoteIdentifier(event.Table)
+	}
+	return quoteIdentifier(event.Table)
+}
+
+// 컬럼 목록을 백틱으로 감싸 연결
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,136 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// 복제 지연 차트 크기
+const (
+	lagChartBuckets  = 20 // 분석 구간을 나누는 시간 칸 수
+	lagChartBarWidth = 40
+)
+
+// 트랜잭션별 복제 지연 (immediate_commit_timestamp - original_commit_timestamp)을 시간 칸별로 집계
+// 여러 워커의 추출기가 함께 기록하므로 잠금 사용
+type replicationLag struct {
+	mu      sync.Mutex
+	start   time.Time
+	end     time.Time
+	buckets [lagChartBuckets]lagBucket
+
+	n           int
+	total       time.Duration
+	max         time.Duration
+	maxID       string    // 지연이 가장 큰 트랜잭션
+	maxAt       time.Time // 그 트랜잭션의 커밋 시각
+	noTimestamp int       // 커밋 타임스탬프가 없는 트랜잭션 수 (MySQL 8.0.1 미만, MariaDB)
+}
+
+type lagBucket struct {
+	n     int
+	total time.Duration
+	max   time.Duration
+}
+
+func newReplicationLag(start, end time.Time) *replicationLag {
+	return &replicationLag{start: start, end: end}
+}
+
+// GTID 이벤트의 지연 추가 (--replication-lag가 없으면 nil)
+func (l *replicationLag) add(e mysql.BinlogGTIDEvent, id string) {
+	if l == nil {
+		return
+	}
+
+	var gtid *replication.GTIDEvent
+	switch e := e.(type) {
+	case *replication.GTIDEvent:
+		gtid = e
+	case *replication.GtidTaggedLogEvent:
+		gtid = &e.GTIDEvent
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if gtid == nil || gtid.ImmediateCommitTimestamp == 0 {
+		l.noTimestamp++
+		return
+	}
+
+	at := gtid.ImmediateCommitTime()
+	lag := at.Sub(gtid.OriginalCommitTime())
+	if lag < 0 {
+		// 원본과 복제본의 시계 차이
+		lag = 0
+	}
+
+	l.n++
+	l.total += lag
+	if l.n == 1 || lag > l.max {
+		l.max, l.maxID, l.maxAt = lag, id, at
+	}
+	bucket := &l.buckets[l.bucketIndex(at)]
+	bucket.n++
+	bucket.total += lag
+	bucket.max = max(bucket.max, lag)
+}
+
+func (l *replicationLag) bucketIndex(at time.Time) int {
+	span := l.end.Sub(l.start)
+	if span <= 0 {
+		return 0
+	}
+	index := int(int64(at.Sub(l.start)) * lagChartBuckets / int64(span))
+	return min(max(index, 0), lagChartBuckets-1)
+}
+
+// 시간 칸별 평균/최대 지연 차트 출력 (막대 길이는 칸의 최대 지연)
+func (l *replicationLag) write(w io.Writer) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.n == 0 {
+		if l.noTimestamp > 0 {
+			fmt.Fprintf(w, ">> 복제 지연: 커밋 타임스탬프가 없습니다 (MySQL 8.0.1 이상 필요, 트랜잭션 %d개)\n", l.noTimestamp)
+		} else {
+			fmt.Fprintln(w, ">> 복제 지연: 구간에 트랜잭션이 없습니다")
+		}
+		return
+	}
+
+	fmt.Fprintf(w, ">> 복제 지연 (트랜잭션 %d개): 평균 %s, 최대 %s (%s, %s)\n",
+		l.n, formatLag(l.total/time.Duration(l.n)), formatLag(l.max), l.maxID, l.maxAt.UTC().Format("2006-01-02 15:04:05.999999"))
+	if l.max == 0 {
+		fmt.Fprintln(w, "   모든 트랜잭션이 이 서버에서 커밋되었습니다 (복제로 받은 트랜잭션 없음)")
+		return
+	}
+
+	span := l.end.Sub(l.start) / lagChartBuckets
+	for i, bucket := range l.buckets {
+		at := l.start.Add(span * time.Duration(i)).UTC().Format("2006-01-02 15:04:05")
+		if bucket.n == 0 {
+			fmt.Fprintf(w, "   %s  -\n", at)
+			continue
+		}
+		bar := int(int64(bucket.max) * lagChartBarWidth / int64(l.max))
+		fmt.Fprintf(w, "   %s  avg %9s  max %9s  %s\n", at,
+			formatLag(bucket.total/time.Duration(bucket.n)), formatLag(bucket.max), strings.Repeat("█", bar))
+	}
+	if l.noTimestamp > 0 {
+		fmt.Fprintf(w, "   커밋 타임스탬프가 없는 트랜잭션 %d개 제외\n", l.noTimestamp)
+	}
+}
+
+func formatLag(lag time.Duration) string {
+	return fmt.Sprintf("%.3fs", lag.Seconds())
+}
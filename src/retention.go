@@ -0,0 +1,175 @@
+package src
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// binlog 보존 기간 권장 기준
+const (
+	retentionMargin   = 1.2  // 구간 시작부터 지금까지의 기간에 더하는 여유
+	retentionMinHours = 24   // 복제본이나 CDC가 하루 동안 멈춰도 따라잡을 수 있도록
+	retentionMaxHours = 2160 // Aurora MySQL의 binlog retention hours 최대값 (90일)
+	retentionMinSpan  = time.Minute
+)
+
+// 분석 대상 파일의 쓰기량과 로테이션 주기로 계산한 binlog 보존 기간 권장 (--retention-advice)
+type retentionAdvice struct {
+	start    time.Time // 분석 구간 시작
+	now      time.Time
+	earliest time.Time // 남아 있는 가장 오래된 이벤트 (구간 시작이 purge되었을 때만)
+
+	files    int           // 시간 범위를 아는 대상 파일 수
+	bytes    int64         // 그 파일들의 크기 합
+	span     time.Duration // 그 파일들이 기록된 기간의 합
+	peak     float64       // 파일 하나의 최대 쓰기량 (bytes/시간)
+	peakFile string
+
+	totalFiles int   // 서버에 남아 있는 binlog 파일 수
+	totalBytes int64 // 서버에 남아 있는 binlog 크기 합
+
+	setting retentionSetting
+}
+
+// 서버의 현재 binlog 보존 설정
+type retentionSetting struct {
+	rds   bool   // mysql.rds_show_configuration으로 확인 (RDS, Aurora)
+	name  string // 설정 이름
+	value string // 설정 값 (NULL 가능, 확인 실패 시 빈 문자열)
+	hours float64
+}
+
+// 대상 파일의 시간 범위와 서버의 binlog 목록, 보존 설정으로 권장 계산
+func (ba *BinlogAnalyzer) adviseRetention(binlogFiles, targetFiles []config.BinlogFile) *retentionAdvice {
+	if !ba.Config.RetentionAdvice {
+		return nil
+	}
+
+	advice := &retentionAdvice{start: ba.Config.StartTime, now: time.Now().UTC(), earliest: ba.earliest}
+	for _, file := range targetFiles {
+		if file.StartTime.IsZero() || file.EndTime.IsZero() || !file.EndTime.After(file.StartTime) {
+			continue
+		}
+		span := file.EndTime.Sub(file.StartTime)
+		advice.files++
+		advice.bytes += file.Size
+		advice.span += span
+		if rate := float64(file.Size) / max(span, retentionMinSpan).Hours(); rate > advice.peak {
+			advice.peak, advice.peakFile = rate, file.Name
+		}
+	}
+	for _, file := range binlogFiles {
+		advice.totalFiles++
+		advice.totalBytes += file.Size
+	}
+	advice.setting = ba.retentionSetting()
+	return advice
+}
+
+// 현재 보존 설정 (RDS/Aurora는 binlog retention hours, 그 밖에는 binlog_expire_logs_seconds, expire_logs_days)
+func (ba *BinlogAnalyzer) retentionSetting() retentionSetting {
+	if rows, err := ba.conn.Query("CALL mysql.rds_show_configuration"); err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			var value, description sql.NullString
+			if rows.Scan(&name, &value, &description) != nil || name != "binlog retention hours" {
+				continue
+			}
+			setting := retentionSetting{rds: true, name: name, value: "NULL"}
+			if value.Valid {
+				setting.value = value.String
+				setting.hours, _ = strconv.ParseFloat(value.String, 64)
+			}
+			return setting
+		}
+	}
+
+	var seconds int64
+	if err := ba.conn.QueryRow("SELECT @@GLOBAL.binlog_expire_logs_seconds").Scan(&seconds); err == nil {
+		return retentionSetting{name: "binlog_expire_logs_seconds", value: strconv.FormatInt(seconds, 10), hours: float64(seconds) / 3600}
+	}
+	var days float64
+	if err := ba.conn.QueryRow("SELECT @@GLOBAL.expire_logs_days").Scan(&days); err == nil {
+		return retentionSetting{name: "expire_logs_days", value: strconv.FormatFloat(days, 'f', -1, 64), hours: days * 24}
+	}
+	return retentionSetting{}
+}
+
+// 평균 쓰기량 (bytes/시간)
+func (a *retentionAdvice) rate() float64 {
+	return float64(a.bytes) / a.span.Hours()
+}
+
+// 권장 보존 기간 (구간 시작부터 지금까지 + 여유, 최소 하루)
+func (a *retentionAdvice) recommendedHours() int {
+	needed := a.now.Sub(a.start).Hours() * retentionMargin
+	return min(max(int(math.Ceil(needed)), retentionMinHours), retentionMaxHours)
+}
+
+// 권장 보존 기간, 쓰기량, 로테이션 주기와 예상 저장 공간 출력 (--retention-advice가 없으면 nil)
+func (a *retentionAdvice) write(w io.Writer) {
+	if a == nil {
+		return
+	}
+	if a.files == 0 || a.span <= 0 {
+		fmt.Fprintln(w, T(">> binlog 보존 기간 권장: 대상 파일의 시간 범위를 확인하지 못해 계산할 수 없습니다"))
+		return
+	}
+
+	rate := a.rate()
+	hours := a.recommendedHours()
+	fmt.Fprintf(w, T(">> binlog 보존 기간 권장 (대상 파일 %d개, %.1f시간 기준):\n"), a.files, a.span.Hours())
+	fmt.Fprintf(w, T("   쓰기량: 평균 %s/시간, 최대 %s/시간 (%s)\n"), formatBytes(int64(rate)), formatBytes(int64(a.peak)), a.peakFile)
+	fmt.Fprintf(w, T("   파일 로테이션: 시간당 %.1f개 (평균 %s마다), 평균 파일 크기 %s\n"),
+		float64(a.files)/a.span.Hours(), (a.span / time.Duration(a.files)).Round(time.Second), formatBytes(a.bytes/int64(a.files)))
+	fmt.Fprintf(w, T("   현재 binlog: 파일 %d개, %s\n"), a.totalFiles, formatBytes(a.totalBytes))
+
+	switch {
+	case a.setting.name == "":
+		fmt.Fprintln(w, T("   현재 보존 설정: 확인 실패"))
+	case a.setting.rds && a.setting.value == "NULL":
+		fmt.Fprintf(w, T("   현재 보존 설정: %s = NULL (복제본이나 CDC가 읽고 나면 곧바로 삭제)\n"), a.setting.name)
+	case a.setting.hours == 0:
+		fmt.Fprintf(w, T("   현재 보존 설정: %s = %s (자동 삭제 안 함)\n"), a.setting.name, a.setting.value)
+	default:
+		fmt.Fprintf(w, T("   현재 보존 설정: %s = %s (%.0f시간, 예상 저장 공간 %s)\n"),
+			a.setting.name, a.setting.value, a.setting.hours, formatBytes(int64(rate*a.setting.hours)))
+	}
+	if !a.earliest.IsZero() {
+		fmt.Fprintf(w, T("   구간 시작 시각의 binlog는 이미 삭제되었습니다 (가장 오래된 이벤트: %s UTC)\n"), a.earliest.Format("2006-01-02 15:04:05"))
+	}
+
+	fmt.Fprintf(w, T("   권장 보존 기간: %d시간 (구간 시작부터 지금까지 %.1f시간 + 여유 %.0f%%, 최소 %d시간, 최대 %d시간)\n"),
+		hours, a.now.Sub(a.start).Hours(), (retentionMargin-1)*100, retentionMinHours, retentionMaxHours)
+	fmt.Fprintf(w, T("   예상 저장 공간: %s (최대 쓰기량 기준 %s)\n"), formatBytes(int64(rate*float64(hours))), formatBytes(int64(a.peak*float64(hours))))
+	if a.setting.rds || a.setting.name == "" {
+		fmt.Fprintf(w, T("   적용: CALL mysql.rds_set_configuration('binlog retention hours', %d);\n"), hours)
+	} else {
+		fmt.Fprintf(w, T("   적용: SET PERSIST binlog_expire_logs_seconds = %d;\n"), hours*3600)
+	}
+}
+
+// 바이트 수를 읽기 쉬운 단위로 (1024 단위)
+func formatBytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB"}
+	unit := ""
+	for _, unit = range units {
+		value /= 1024
+		if value < 1024 {
+			break
+		}
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + " " + unit
+}
@@ -0,0 +1,156 @@
+package src
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+func validateRotate(cfg config.Config) error {
+	if cfg.OutputRotateSize < 0 || cfg.OutputRotateEvents < 0 {
+		return fmt.Errorf("--output-rotate-size, --output-rotate-events는 0 이상이어야 합니다")
+	}
+	if !rotating(cfg) {
+		return nil
+	}
+	if cfg.OutputFile == "" {
+		return fmt.Errorf("--output-rotate-size, --output-rotate-events는 번호를 붙일 --output이 필요합니다")
+	}
+	if cfg.SplitBy != "" {
+		return fmt.Errorf("--output-rotate-size, --output-rotate-events는 --split-by와 함께 사용할 수 없습니다")
+	}
+	if cfg.AppendOutput {
+		return fmt.Errorf("--output-rotate-size, --output-rotate-events는 --append와 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+// 결과를 여러 파일로 나누어 쓰는지 (--output-rotate-size, --output-rotate-events)
+func rotating(cfg config.Config) bool {
+	return cfg.OutputRotateSize > 0 || cfg.OutputRotateEvents > 0
+}
+
+// 나누어 쓰는 파일 이름 (--output의 확장자 앞에 번호, results.sql → results.001.sql)
+func rotateFileName(output string, index int) string {
+	return splitFileName(output, ".", fmt.Sprintf("%03d", index))
+}
+
+// 파일에 쓴 바이트 수 (압축 전)와 첫 쓰기 오류
+type countingWriter struct {
+	io.Writer
+	written int64
+	err     error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.Writer.Write(p)
+	w.written += int64(n)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// 크기나 이벤트 수가 한도에 이르면 번호가 붙은 다음 파일로 넘어가는 출력기
+// 파일마다 선택한 형식의 출력기를 새로 만들어 각 파일이 따로 읽을 수 있는 완전한 결과가 됨 (JSON 배열, CSV 헤더, Parquet 메타데이터)
+// 이벤트 중간에서는 나누지 않으므로 파일은 한도보다 마지막 이벤트만큼 클 수 있음
+type rotatingWriter struct {
+	ba     *BinlogAnalyzer
+	header func(io.Writer) // 파일마다 이벤트 앞에 쓸 결과 헤더 (없으면 nil)
+
+	index  int // 마지막으로 연 파일 번호
+	file   io.WriteCloser
+	output *countingWriter
+	writer eventWriter
+	events int // 현재 파일의 이벤트 수
+
+	err error // 파일을 열거나 쓰거나 닫지 못한 첫 오류 (이후 이벤트는 버림)
+}
+
+func (ba *BinlogAnalyzer) newRotatingWriter(header func(io.Writer)) *rotatingWriter {
+	return &rotatingWriter{ba: ba, header: header}
+}
+
+func (w *rotatingWriter) open() {
+	w.index++
+	name := rotateFileName(w.ba.Config.OutputFile, w.index)
+	file, err := createOutputFile(w.ba.Config, name)
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.file = file
+	w.output = &countingWriter{Writer: file}
+	w.events = 0
+	if w.header != nil {
+		w.header(w.output)
+	}
+	w.writer = w.ba.newEventWriter(w.output)
+}
+
+func (w *rotatingWriter) close() {
+	w.writer.finish()
+	if w.output.err != nil && w.err == nil {
+		w.err = w.output.err
+	}
+	if err := w.file.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.err == nil {
+		logrus.Infof(T("Results saved to %s (%d events)"), rotateFileName(w.ba.Config.OutputFile, w.index), w.events)
+	}
+	w.file, w.writer = nil, nil
+}
+
+func (w *rotatingWriter) writeEvent(event *config.SQLEvent) {
+	if w.err != nil {
+		return
+	}
+	if w.writer == nil {
+		if w.open(); w.err != nil {
+			return
+		}
+	}
+	w.writer.writeEvent(event)
+	w.events++
+	if w.output.err != nil {
+		w.err = w.output.err
+		w.close()
+		return
+	}
+
+	cfg := w.ba.Config
+	if cfg.OutputRotateEvents > 0 && w.events >= cfg.OutputRotateEvents ||
+		cfg.OutputRotateSize > 0 && w.output.written >= cfg.OutputRotateSize {
+		w.close()
+	}
+}
+
+// 열린 파일을 마무리 (이벤트가 없었으면 빈 결과의 첫 파일을 씀)
+func (w *rotatingWriter) finish() {
+	if w.writer == nil && w.index == 0 && w.err == nil {
+		w.open()
+	}
+	if w.writer != nil {
+		w.close()
+	}
+}
+
+// 결과를 번호가 붙은 여러 파일로 나누어 출력 (파일마다 같은 결과 헤더, # Total Events는 전체 이벤트 수)
+func (ba *BinlogAnalyzer) outputRotatedResults(events []config.SQLEvent) error {
+	logrus.Infof(T("Analysis complete: %d SQL events"), len(events))
+	writer := ba.newRotatingWriter(func(output io.Writer) {
+		ba.writeResultsHeader(output, len(events))
+	})
+	for i := range events {
+		writer.writeEvent(&events[i])
+	}
+	writer.finish()
+	return writer.err
+}
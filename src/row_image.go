@@ -0,0 +1,40 @@
+package src
+
+import (
+	"fmt"
+	"slices"
+
+	"mysqlbinlogo/config"
+)
+
+// row 이미지에 기록된 컬럼인지 (binlog_row_image=MINIMAL/NOBLOB에서 빠진 컬럼은 false, 값은 NULL이 아니라 알 수 없음)
+func columnLogged(event *config.SQLEvent, image, column int) bool {
+	if image >= len(event.SkippedColumns) {
+		return true
+	}
+	return !slices.Contains(event.SkippedColumns[image], column)
+}
+
+// 기록되지 않은 컬럼이 있으면 pseudo-SQL 끝에 붙이는 표시
+func notLoggedComment(event *config.SQLEvent, image int) string {
+	if image >= len(event.SkippedColumns) || len(event.SkippedColumns[image]) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" /* %d columns not logged (binlog_row_image) */", len(event.SkippedColumns[image]))
+}
+
+// 기록된 컬럼의 위치 (모두 기록되었으면 0..n-1)
+func loggedColumns(event *config.SQLEvent, image int) []int {
+	var indexes []int
+	for i := range event.Rows[image] {
+		if columnLogged(event, image, i) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// 기록되지 않은 컬럼이 있는 이미지인지
+func imageMinimal(event *config.SQLEvent, image int) bool {
+	return image < len(event.SkippedColumns) && len(event.SkippedColumns[image]) > 0
+}
@@ -0,0 +1,155 @@
+package src
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// --where 'db.table.col = value' 형태의 row 조건
+type rowPredicate struct {
+	table    string // schema.table
+	column   string
+	operator string
+	value    string
+	isNull   bool // 값이 NULL인 비교 (= NULL, != NULL)
+}
+
+// 지원하는 비교 연산자 (긴 연산자를 먼저 확인)
+var predicateOperators = []string{"<=", ">=", "!=", "<>", "=", "<", ">"}
+
+// 조건식 파싱
+func parseRowPredicate(expr string) (rowPredicate, error) {
+	var pred rowPredicate
+
+	opIndex := -1
+	for i := 0; i < len(expr) && opIndex < 0; i++ {
+		for _, op := range predicateOperators {
+			if strings.HasPrefix(expr[i:], op) {
+				opIndex = i
+				pred.operator = op
+				break
+			}
+		}
+	}
+	if opIndex < 0 {
+		return pred, fmt.Errorf("--where 조건에 비교 연산자가 없습니다: %q (예: shop.orders.customer_id = 12345)", expr)
+	}
+
+	target := strings.TrimSpace(expr[:opIndex])
+	parts := strings.Split(target, ".")
+	if len(parts) != 3 {
+		return pred, fmt.Errorf("--where 대상은 db.table.column 형식이어야 합니다: %q", target)
+	}
+	for i := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(parts[i]), "`")
+	}
+	pred.table = parts[0] + "." + parts[1]
+	pred.column = parts[2]
+
+	value := strings.TrimSpace(expr[opIndex+len(pred.operator):])
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	} else if strings.EqualFold(value, "NULL") {
+		pred.isNull = true
+	}
+	if pred.operator == "<>" {
+		pred.operator = "!="
+	}
+	if pred.isNull && pred.operator != "=" && pred.operator != "!=" {
+		return pred, fmt.Errorf("NULL은 = 또는 != 로만 비교할 수 있습니다: %q", expr)
+	}
+	pred.value = value
+
+	return pred, nil
+}
+
+// 이벤트의 row 이미지 중 하나라도 조건을 만족하는지 확인
+func (p rowPredicate) matchEvent(event *config.SQLEvent) bool {
+	index := -1
+	for i, name := range event.Columns {
+		if strings.EqualFold(name, p.column) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		// 컬럼 이름을 모를 때는 col_N 형태로도 지정 가능
+		if n, ok := strings.CutPrefix(strings.ToLower(p.column), "col_"); ok {
+			if num, err := strconv.Atoi(n); err == nil {
+				index = num - 1
+			}
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	for i, row := range event.Rows {
+		if index < len(row) && columnLogged(event, i, index) && p.matchValue(row[index]) {
+			return true
+		}
+	}
+	return false
+}
+
+// 값 하나에 대한 비교
+func (p rowPredicate) matchValue(val interface{}) bool {
+	if p.isNull || val == nil {
+		equal := p.isNull && val == nil
+		if p.operator == "!=" {
+			return !equal && p.isNull
+		}
+		return equal
+	}
+
+	actual := predicateString(val)
+
+	// 양쪽 모두 숫자면 숫자로 비교
+	var cmp int
+	a, errA := strconv.ParseFloat(actual, 64)
+	b, errB := strconv.ParseFloat(p.value, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(actual, p.value)
+	}
+
+	switch p.operator {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// 비교용 문자열 변환
+func predicateString(val interface{}) string {
+	switch v := val.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
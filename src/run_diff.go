@@ -0,0 +1,259 @@
+package src
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// diff 보고서에 표시할 fingerprint 수 (새로 생긴 것, 사라진 것 각각)
+const diffFingerprintLimit = 20
+
+// TimeWindow 비교할 분석 구간 (UTC)
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// RunDiff 두 실행의 유형/테이블별 이벤트와 문장 fingerprint 비교 (diff 서브커맨드, 배포 전후 비교용)
+type RunDiff struct {
+	Config config.Config // 구간 비교의 접속 정보와 필터, 실행 이력 저장소 (--history-db)
+	Output io.Writer     // 보고서 출력 대상 (없으면 stdout)
+}
+
+func (d *RunDiff) output() io.Writer {
+	if d.Output != nil {
+		return d.Output
+	}
+	return os.Stdout
+}
+
+// CompareRuns 실행 이력 저장소의 두 실행 비교
+func (d *RunDiff) CompareRuns(before, after int64) error {
+	if d.Config.HistoryDB == "" {
+		return fmt.Errorf("실행 번호로 비교하려면 --history-db를 지정해야 합니다")
+	}
+	history, err := openRunHistory(d.Config.HistoryDB)
+	if err != nil {
+		return err
+	}
+	defer history.Close()
+
+	beforeRun, err := history.load(before)
+	if err != nil {
+		return err
+	}
+	afterRun, err := history.load(after)
+	if err != nil {
+		return err
+	}
+	writeRunDiff(d.output(), beforeRun, afterRun)
+	return nil
+}
+
+// CompareWindows 두 구간을 차례로 분석하여 비교 (--history-db가 있으면 두 실행도 기록)
+func (d *RunDiff) CompareWindows(ctx context.Context, before, after TimeWindow) error {
+	beforeRun, err := d.analyzeWindow(ctx, before)
+	if err != nil {
+		return err
+	}
+	afterRun, err := d.analyzeWindow(ctx, after)
+	if err != nil {
+		return err
+	}
+	writeRunDiff(d.output(), beforeRun, afterRun)
+	return nil
+}
+
+func (d *RunDiff) analyzeWindow(ctx context.Context, window TimeWindow) (*historyRun, error) {
+	cfg := d.Config
+	cfg.StartTime = window.Start
+	cfg.EndTime = window.End
+	cfg.Sink = ""
+	cfg.SQLiteFile = ""
+	cfg.Format = ""
+	cfg.CSVColumns = nil
+	cfg.OutputTemplate = ""
+	cfg.OutputFile = ""
+	cfg.Compress = ""
+	cfg.OutputRotateSize = 0
+	cfg.OutputRotateEvents = 0
+	cfg.SplitBy = ""
+	cfg.Timeline = ""
+	cfg.CapacityReport = ""
+	cfg.PushgatewayURL = ""
+	cfg.SummaryJSON = ""
+	cfg.Incremental = false // 비교하는 구간은 --before, --after로 정함
+	cfg.StateFile = ""
+
+	analyzer := &BinlogAnalyzer{Config: cfg, messages: os.Stderr, discardOutput: true}
+	err := analyzer.Analyze(ctx)
+	status := runStatusOK
+	if errors.Is(err, ErrRangeNotCovered) {
+		// 구간 앞부분이 빠져도 비교는 하되 보고서에 표시
+		logrus.Warnf("%v", err)
+		status = runStatusIncomplete
+	} else if err != nil {
+		return nil, err
+	}
+
+	run := &historyRun{
+		Host:      cfg.Host,
+		Port:      cfg.Port,
+		StartTime: window.Start,
+		EndTime:   window.End,
+		Status:    status,
+		Files:     len(analyzer.run.files),
+		stats:     analyzer.run,
+	}
+	for _, count := range analyzer.run.events {
+		run.Events += count
+	}
+	return run, nil
+}
+
+// 유형/테이블 하나의 변화
+type groupDelta struct {
+	group                     eventGroup
+	beforeEvents, afterEvents int
+	beforeRows, afterRows     int
+}
+
+func writeRunDiff(w io.Writer, before, after *historyRun) {
+	fmt.Fprintln(w, T("# 실행 비교"))
+	writeDiffRun(w, T("이전"), before)
+	writeDiffRun(w, T("이후"), after)
+	fmt.Fprintln(w)
+
+	// 이벤트 수나 변경 행 수가 달라진 유형/테이블 (변화가 큰 순)
+	var deltas []groupDelta
+	for _, group := range unionKeys(before.stats.events, after.stats.events) {
+		delta := groupDelta{
+			group:        group,
+			beforeEvents: before.stats.events[group],
+			afterEvents:  after.stats.events[group],
+			beforeRows:   before.stats.rows[group],
+			afterRows:    after.stats.rows[group],
+		}
+		if delta.beforeEvents != delta.afterEvents || delta.beforeRows != delta.afterRows {
+			deltas = append(deltas, delta)
+		}
+	}
+	slices.SortFunc(deltas, func(a, b groupDelta) int {
+		return cmp.Or(
+			cmp.Compare(absInt(b.afterEvents-b.beforeEvents), absInt(a.afterEvents-a.beforeEvents)),
+			cmp.Compare(groupName(a.group), groupName(b.group)),
+			cmp.Compare(a.group.eventType, b.group.eventType),
+		)
+	})
+
+	fmt.Fprintf(w, T(">> 유형/테이블별 변화 (%d개):\n"), len(deltas))
+	width := 0
+	for _, delta := range deltas {
+		width = max(width, len(groupName(delta.group)))
+	}
+	for _, delta := range deltas {
+		name := groupName(delta.group)
+		fmt.Fprintf(w, "   %-6s  %s  ", delta.group.eventType, name+strings.Repeat(" ", width-len(name)))
+		fmt.Fprintf(w, T("이벤트 %d → %d (%+d)"), delta.beforeEvents, delta.afterEvents, delta.afterEvents-delta.beforeEvents)
+		if delta.group.eventType != "QUERY" {
+			fmt.Fprintf(w, T(", 변경 행 %d → %d (%+d)"), delta.beforeRows, delta.afterRows, delta.afterRows-delta.beforeRows)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w)
+	writeFingerprintDiff(w, T(">> 새 문장 fingerprint (이후에만 있음, %d개):\n"), after.stats.fingerprints, before.stats.fingerprints)
+	fmt.Fprintln(w)
+	writeFingerprintDiff(w, T(">> 사라진 문장 fingerprint (이전에만 있음, %d개):\n"), before.stats.fingerprints, after.stats.fingerprints)
+}
+
+func writeDiffRun(w io.Writer, label string, run *historyRun) {
+	id := "-"
+	if run.ID > 0 {
+		id = fmt.Sprintf("#%d", run.ID)
+	}
+	fmt.Fprintf(w, T("#   %s: %s  %s:%d  %s ~ %s UTC (%s, 파일 %d개, 이벤트 %d개)\n"), label, id, run.Host, run.Port,
+		run.StartTime.Format("2006-01-02 15:04:05.999999"), run.EndTime.Format("2006-01-02 15:04:05.999999"),
+		run.Status, run.Files, run.Events)
+}
+
+// only에만 있는 fingerprint를 이벤트 수가 많은 순으로 출력
+func writeFingerprintDiff(w io.Writer, title string, only, other map[string]int) {
+	var fingerprints []string
+	for fingerprint := range only {
+		if _, ok := other[fingerprint]; !ok {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	slices.SortFunc(fingerprints, func(a, b string) int {
+		return cmp.Or(cmp.Compare(only[b], only[a]), cmp.Compare(a, b))
+	})
+
+	fmt.Fprintf(w, title, len(fingerprints))
+	for _, fingerprint := range fingerprints[:min(len(fingerprints), diffFingerprintLimit)] {
+		fmt.Fprintf(w, "   %6d  %s\n", only[fingerprint], fingerprint)
+	}
+	if len(fingerprints) > diffFingerprintLimit {
+		fmt.Fprintf(w, T("   외 %d개\n"), len(fingerprints)-diffFingerprintLimit)
+	}
+}
+
+// ListRuns 실행 이력 저장소의 최근 실행 목록 (history 서브커맨드)
+func ListRuns(w io.Writer, path string, limit int) error {
+	history, err := openRunHistory(path)
+	if err != nil {
+		return err
+	}
+	defer history.Close()
+
+	runs, err := history.list(limit)
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		fmt.Fprintf(w, T("#%-5d %s  %s:%d  %s ~ %s UTC  %-10s  파일 %d개, 이벤트 %d개\n"), run.ID,
+			run.StartedAt.Format("2006-01-02 15:04:05"), run.Host, run.Port,
+			run.StartTime.Format("2006-01-02 15:04:05.999999"), run.EndTime.Format("2006-01-02 15:04:05.999999"),
+			run.Status, run.Files, run.Events)
+	}
+	return nil
+}
+
+// 유형/테이블의 표시 이름 (QUERY 이벤트는 데이터베이스만)
+func groupName(group eventGroup) string {
+	if group.table == "" {
+		return group.database
+	}
+	return group.database + "." + group.table
+}
+
+func unionKeys[K comparable](a, b map[K]int) []K {
+	keys := make([]K, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
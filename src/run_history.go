@@ -0,0 +1,232 @@
+package src
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+
+	"mysqlbinlogo/config"
+)
+
+// 실행 이력 저장소 스키마 (--history-db, 시각은 RFC 3339 UTC 문자열)
+const runHistorySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at  TEXT NOT NULL,
+	finished_at TEXT NOT NULL,
+	host        TEXT NOT NULL,
+	port        INTEGER NOT NULL,
+	start_time  TEXT NOT NULL,
+	end_time    TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	error       TEXT NOT NULL DEFAULT '',
+	files       INTEGER NOT NULL,
+	events      INTEGER NOT NULL,
+	summary     TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS run_events (
+	run_id        INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	event_type    TEXT NOT NULL,
+	database_name TEXT NOT NULL,
+	table_name    TEXT NOT NULL,
+	events        INTEGER NOT NULL,
+	rows          INTEGER NOT NULL,
+	PRIMARY KEY (run_id, event_type, database_name, table_name)
+);
+CREATE TABLE IF NOT EXISTS run_fingerprints (
+	run_id      INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	fingerprint TEXT NOT NULL,
+	events      INTEGER NOT NULL,
+	PRIMARY KEY (run_id, fingerprint)
+);`
+
+// 실행 이력 저장소 (SQLite 파일 하나)
+type runHistory struct {
+	db   *sql.DB
+	path string
+}
+
+// 저장된 실행 하나 (diff에서 비교하는 단위)
+type historyRun struct {
+	ID        int64 // 저장하지 않은 구간 분석이면 0
+	StartedAt time.Time
+	Host      string
+	Port      int
+	StartTime time.Time
+	EndTime   time.Time
+	Status    string
+	Files     int
+	Events    int
+	stats     *runStats // 유형/테이블별 이벤트와 fingerprint (목록 조회에서는 nil)
+}
+
+func validateRunHistory(cfg config.Config) error {
+	if cfg.HistoryDB != "" && cfg.Follow {
+		return fmt.Errorf("--history-db는 --follow와 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+func openRunHistory(path string) (*runHistory, error) {
+	// 여러 클러스터 분석(--clusters)이 동시에 기록하므로 잠금은 잠시 기다림
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(10000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(runHistorySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("실행 이력 저장소 %s 초기화 실패: %v", path, err)
+	}
+	return &runHistory{db: db, path: path}, nil
+}
+
+func (h *runHistory) Close() error {
+	return h.db.Close()
+}
+
+// 실행 종료 시 요약과 집계를 저장 (분석이 실패해도 기록)
+func (ba *BinlogAnalyzer) recordHistory(runErr error) error {
+	if ba.Config.HistoryDB == "" {
+		return nil
+	}
+
+	history, err := openRunHistory(ba.Config.HistoryDB)
+	if err != nil {
+		return err
+	}
+	defer history.Close()
+
+	id, err := history.save(ba.runSummary(runErr), ba.run)
+	if err != nil {
+		return fmt.Errorf("실행 이력 저장 실패: %v", err)
+	}
+	logrus.Infof(T("Run #%d recorded in %s"), id, ba.Config.HistoryDB)
+	return nil
+}
+
+func (h *runHistory) save(summary runSummary, stats *runStats) (int64, error) {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO runs (started_at, finished_at, host, port, start_time, end_time, status, error, files, events, summary)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		formatHistoryTime(summary.StartedAt), formatHistoryTime(summary.FinishedAt),
+		summary.Parameters.Host, summary.Parameters.Port,
+		formatHistoryTime(summary.Parameters.StartTime), formatHistoryTime(summary.Parameters.EndTime),
+		summary.Status, summary.Error, len(summary.Files), summary.Events.Unique, string(data))
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for group, count := range stats.events {
+		if _, err := tx.Exec(`INSERT INTO run_events (run_id, event_type, database_name, table_name, events, rows) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, group.eventType, group.database, group.table, count, stats.rows[group]); err != nil {
+			return 0, err
+		}
+	}
+	for fingerprint, count := range stats.fingerprints {
+		if _, err := tx.Exec(`INSERT INTO run_fingerprints (run_id, fingerprint, events) VALUES (?, ?, ?)`,
+			id, fingerprint, count); err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+const historyRunColumns = `id, started_at, host, port, start_time, end_time, status, files, events`
+
+func scanHistoryRun(row interface{ Scan(...interface{}) error }) (*historyRun, error) {
+	var run historyRun
+	var startedAt, startTime, endTime string
+	if err := row.Scan(&run.ID, &startedAt, &run.Host, &run.Port, &startTime, &endTime, &run.Status, &run.Files, &run.Events); err != nil {
+		return nil, err
+	}
+	run.StartedAt, _ = time.Parse(time.RFC3339Nano, startedAt)
+	run.StartTime, _ = time.Parse(time.RFC3339Nano, startTime)
+	run.EndTime, _ = time.Parse(time.RFC3339Nano, endTime)
+	return &run, nil
+}
+
+// 실행 하나와 유형/테이블별 이벤트, fingerprint 조회
+func (h *runHistory) load(id int64) (*historyRun, error) {
+	run, err := scanHistoryRun(h.db.QueryRow(`SELECT `+historyRunColumns+` FROM runs WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%s에 실행 #%d이 없습니다", h.path, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	run.stats = newRunStats()
+	rows, err := h.db.Query(`SELECT event_type, database_name, table_name, events, rows FROM run_events WHERE run_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var group eventGroup
+		var events, changed int
+		if err := rows.Scan(&group.eventType, &group.database, &group.table, &events, &changed); err != nil {
+			return nil, err
+		}
+		run.stats.events[group] = events
+		run.stats.rows[group] = changed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fingerprints, err := h.db.Query(`SELECT fingerprint, events FROM run_fingerprints WHERE run_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer fingerprints.Close()
+	for fingerprints.Next() {
+		var fingerprint string
+		var events int
+		if err := fingerprints.Scan(&fingerprint, &events); err != nil {
+			return nil, err
+		}
+		run.stats.fingerprints[fingerprint] = events
+	}
+	return run, fingerprints.Err()
+}
+
+// 최근 실행 목록 (최신순)
+func (h *runHistory) list(limit int) ([]*historyRun, error) {
+	rows, err := h.db.Query(`SELECT `+historyRunColumns+` FROM runs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*historyRun
+	for rows.Next() {
+		run, err := scanHistoryRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func formatHistoryTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,113 @@
+package src
+
+import (
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// 파일 처리 상태 (--summary-json)
+const (
+	fileStatusDone    = "done"
+	fileStatusFailed  = "failed"
+	fileStatusPending = "not_processed" // 분석이 중단되어 처리하지 못함
+	fileStatusPartial = "partial"       // --deadline으로 읽다가 멈춤 (읽은 이벤트는 결과에 포함)
+)
+
+// 결과 이벤트 집계 기준 (QUERY 이벤트는 테이블 없음)
+type eventGroup struct {
+	eventType string
+	database  string
+	table     string
+}
+
+// 분석 대상 파일 하나의 처리 결과
+type fileStats struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Status string `json:"status"` // done, failed, not_processed, partial
+	Events int    `json:"events"` // 필터 적용 후 추출한 이벤트 수 (중복 제거 전)
+	Error  string `json:"error,omitempty"`
+}
+
+// 분석 실행 한 번의 통계 (--pushgateway-url, --summary-json, --history-db)
+type runStats struct {
+	started    time.Time
+	files      []fileStats        // 분석 대상 파일 (처리 순서)
+	extracted  int                // 중복 제거 전 이벤트 수
+	duplicates int                // 제거한 중복 이벤트 수
+	events     map[eventGroup]int // 결과 이벤트 수
+	rows       map[eventGroup]int // 결과 row 이벤트의 변경 행 수
+
+	fingerprints map[string]int // 문장 fingerprint별 이벤트 수 (--history-db, diff)
+}
+
+func newRunStats() *runStats {
+	return &runStats{
+		started: time.Now(),
+		events:  make(map[eventGroup]int),
+		rows:    make(map[eventGroup]int),
+
+		fingerprints: make(map[string]int),
+	}
+}
+
+// 분석 대상 파일 기록 (모두 처리 전 상태)
+func (s *runStats) setFiles(files []config.BinlogFile) {
+	s.files = make([]fileStats, len(files))
+	for i, file := range files {
+		s.files[i] = fileStats{Name: file.Name, Size: file.Size, Status: fileStatusPending}
+	}
+}
+
+// 파일 하나의 처리 결과 기록
+func (s *runStats) fileDone(name string, events int, err error) {
+	for i := range s.files {
+		if s.files[i].Name != name {
+			continue
+		}
+		if err != nil {
+			s.files[i].Status = fileStatusFailed
+			s.files[i].Error = err.Error()
+		} else {
+			s.files[i].Status = fileStatusDone
+			s.files[i].Events = events
+		}
+		return
+	}
+}
+
+// 끝까지 읽지 못한 파일 기록 (--deadline)
+func (s *runStats) filePartial(name string, events int) {
+	for i := range s.files {
+		if s.files[i].Name == name {
+			s.files[i].Status = fileStatusPartial
+			s.files[i].Events = events
+			return
+		}
+	}
+}
+
+// 처리에 실패해 결과에서 빠진 파일 수
+func (s *runStats) failedFiles() int {
+	failed := 0
+	for _, file := range s.files {
+		if file.Status == fileStatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// 결과 이벤트를 유형, 테이블별로 집계
+func (s *runStats) countEvents(events []config.SQLEvent) {
+	for i := range events {
+		event := &events[i]
+		group := eventGroup{eventType: event.EventType, database: event.Database, table: event.Table}
+		s.events[group]++
+		if event.EventType != "QUERY" {
+			s.rows[group] += event.RowCount
+		}
+		s.fingerprints[eventFingerprint(event)]++
+	}
+}
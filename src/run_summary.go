@@ -0,0 +1,173 @@
+package src
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 실행 결과 (--summary-json의 status, 종료 코드 0, 2, 1에 해당)
+const (
+	runStatusOK         = "ok"
+	runStatusIncomplete = "incomplete" // 구간 앞부분의 binary log가 purge됨
+	runStatusFailed     = "failed"
+)
+
+// 자동화 도구가 확인할 수 있는 실행 요약 (--summary-json)
+type runSummary struct {
+	Status          string        `json:"status"` // ok, incomplete, failed
+	Error           string        `json:"error,omitempty"`
+	StartedAt       time.Time     `json:"started_at"`
+	FinishedAt      time.Time     `json:"finished_at"`
+	DurationSeconds float64       `json:"duration_seconds"`
+	Parameters      runParameters `json:"parameters"`
+	Files           []fileStats   `json:"files"`
+	FilesFailed     int           `json:"files_failed"`
+	Events          eventSummary  `json:"events"`
+	GTIDSet         string        `json:"gtid_set,omitempty"`
+	Warnings        []string      `json:"warnings"`
+
+	// 구간 앞부분이 빠졌을 때 남아 있는 가장 오래된 이벤트 시각
+	EarliestAvailable *time.Time `json:"earliest_available,omitempty"`
+}
+
+// 실행 옵션 (비밀번호 등 인증 정보 제외)
+type runParameters struct {
+	Host              string    `json:"host"`
+	Port              int       `json:"port"`
+	User              string    `json:"user"`
+	StartTime         time.Time `json:"start_time"`
+	EndTime           time.Time `json:"end_time"`
+	Output            string    `json:"output,omitempty"`
+	Format            string    `json:"format,omitempty"`
+	Sink              string    `json:"sink,omitempty"`
+	Backend           string    `json:"backend"`
+	Workers           int       `json:"workers"`
+	ExcludeTableRegex string    `json:"exclude_table_regex,omitempty"`
+	Where             []string  `json:"where,omitempty"`
+	FilterExpr        string    `json:"filter_expr,omitempty"`
+	MinExecTime       string    `json:"min_exec_time,omitempty"`
+	OnlyErrors        bool      `json:"only_errors,omitempty"`
+}
+
+type eventSummary struct {
+	Extracted  int              `json:"extracted"` // 필터 적용 후, 중복 제거 전
+	Duplicates int              `json:"duplicates"`
+	Unique     int              `json:"unique"`
+	ByType     map[string]int   `json:"by_type"`
+	ByTable    []tableEventStat `json:"by_table"`
+}
+
+type tableEventStat struct {
+	Type     string `json:"type"`
+	Database string `json:"database"`
+	Table    string `json:"table,omitempty"`
+	Events   int    `json:"events"`
+	Rows     int    `json:"rows,omitempty"`
+}
+
+func validateRunSummary(cfg config.Config) error {
+	if cfg.SummaryJSON != "" && cfg.Follow {
+		return fmt.Errorf("--summary-json은 --follow와 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+// 실행 요약 파일 저장 (분석이 실패해도 기록)
+func (ba *BinlogAnalyzer) writeRunSummary(runErr error) error {
+	if ba.Config.SummaryJSON == "" {
+		return nil
+	}
+
+	summary := ba.runSummary(runErr)
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("실행 요약 변환 실패: %v", err)
+	}
+	if err := os.WriteFile(ba.Config.SummaryJSON, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("실행 요약 파일 저장 실패: %v", err)
+	}
+	logrus.Infof(T("Run summary saved to %s"), ba.Config.SummaryJSON)
+	return nil
+}
+
+// 실행 결과를 포함한 요약 (--summary-json, --history-db)
+func (ba *BinlogAnalyzer) runSummary(runErr error) runSummary {
+	summary := ba.run.summary(ba.Config)
+	switch {
+	case runErr == nil:
+		summary.Status = runStatusOK
+	case IsIncomplete(runErr):
+		summary.Status = runStatusIncomplete
+		summary.Error = runErr.Error()
+	default:
+		summary.Status = runStatusFailed
+		summary.Error = runErr.Error()
+	}
+	summary.GTIDSet = ba.gtids.String()
+	summary.Warnings = append([]string{}, ba.warnings...)
+	if !ba.earliest.IsZero() {
+		earliest := ba.earliest.UTC()
+		summary.EarliestAvailable = &earliest
+	}
+	return summary
+}
+
+func (s *runStats) summary(cfg config.Config) runSummary {
+	finished := time.Now()
+	summary := runSummary{
+		StartedAt:       s.started.UTC(),
+		FinishedAt:      finished.UTC(),
+		DurationSeconds: finished.Sub(s.started).Seconds(),
+		Parameters: runParameters{
+			Host:              cfg.Host,
+			Port:              cfg.Port,
+			User:              cfg.User,
+			StartTime:         cfg.StartTime.UTC(),
+			EndTime:           cfg.EndTime.UTC(),
+			Output:            cfg.OutputFile,
+			Format:            cfg.Format,
+			Sink:              cfg.Sink,
+			Backend:           cfg.Backend,
+			Workers:           cfg.Workers,
+			ExcludeTableRegex: cfg.ExcludeTableRegex,
+			Where:             cfg.Where,
+			FilterExpr:        cfg.FilterExpr,
+			OnlyErrors:        cfg.OnlyErrors,
+		},
+		Files:       append([]fileStats{}, s.files...),
+		FilesFailed: s.failedFiles(),
+		Events: eventSummary{
+			Extracted:  s.extracted,
+			Duplicates: s.duplicates,
+			ByType:     make(map[string]int),
+			ByTable:    []tableEventStat{},
+		},
+	}
+	if cfg.MinExecTime > 0 {
+		summary.Parameters.MinExecTime = cfg.MinExecTime.String()
+	}
+
+	for group, count := range s.events {
+		summary.Events.Unique += count
+		summary.Events.ByType[group.eventType] += count
+		summary.Events.ByTable = append(summary.Events.ByTable, tableEventStat{
+			Type:     group.eventType,
+			Database: group.database,
+			Table:    group.table,
+			Events:   count,
+			Rows:     s.rows[group],
+		})
+	}
+	slices.SortFunc(summary.Events.ByTable, func(a, b tableEventStat) int {
+		return cmp.Or(cmp.Compare(a.Database, b.Database), cmp.Compare(a.Table, b.Table), cmp.Compare(a.Type, b.Type))
+	})
+	return summary
+}
@@ -0,0 +1,114 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// 현재 스키마와 달라 그대로 재실행할 수 없는 이벤트 (테이블과 이유별 집계)
+type driftTable struct {
+	name   string
+	reason string
+	events int
+	first  *config.SQLEvent
+	last   *config.SQLEvent
+}
+
+// 이벤트가 참조하는 테이블, 컬럼을 현재 information_schema와 비교하여 달라진 것을 집계 (--schema-drift)
+// row 이벤트는 이벤트 시점의 컬럼 구성과 현재 컬럼, 쿼리 이벤트는 참조하는 테이블이 지금 있는지 확인
+func (ba *BinlogAnalyzer) checkSchemaDrift(events []config.SQLEvent) []*driftTable {
+	var drifted []*driftTable
+	index := make(map[string]*driftTable)
+	add := func(event *config.SQLEvent, name, reason string) {
+		key := name + "\x00" + reason
+		table := index[key]
+		if table == nil {
+			table = &driftTable{name: name, reason: reason, first: event}
+			index[key] = table
+			drifted = append(drifted, table)
+		}
+		table.events++
+		table.last = event
+	}
+
+	for i := range events {
+		event := &events[i]
+		if event.EventType != "QUERY" {
+			// 컬럼 이름을 알 수 없는 이벤트는 테이블 메타데이터 요약에서 따로 보고
+			if event.Columns == nil {
+				continue
+			}
+			ts := ba.schema.Table(event.Database, event.Table)
+			if ts == nil {
+				add(event, qualifiedTableName(event), T("현재 테이블이 없음 (삭제, 이름 변경 또는 권한 없음)"))
+			} else if reason := columnDrift(event.Columns, ts.ColumnNames()); reason != "" {
+				add(event, qualifiedTableName(event), reason)
+			}
+			continue
+		}
+
+		// CREATE는 테이블이 없어도 재실행할 수 있음
+		if tokens := tokenizeSQL(event.SQL); len(tokens) == 0 || strings.EqualFold(tokens[0], "CREATE") {
+			continue
+		}
+		for _, name := range queryTables(event.Database, event.SQL) {
+			database, table, _ := strings.Cut(name, ".")
+			if ba.schema.Table(database, table) == nil {
+				add(event, name, T("현재 테이블이 없음 (삭제, 이름 변경 또는 권한 없음)"))
+			}
+		}
+	}
+	return drifted
+}
+
+// 이벤트의 컬럼과 현재 컬럼의 차이 (같으면 빈 문자열)
+// 재실행용 SQL은 컬럼 이름을 나열하므로 순서만 바뀐 것은 차이로 보지 않음
+func columnDrift(eventColumns, currentColumns []string) string {
+	current := make(map[string]bool, len(currentColumns))
+	for _, column := range currentColumns {
+		current[strings.ToLower(column)] = true
+	}
+	logged := make(map[string]bool, len(eventColumns))
+	var removed, added []string
+	for _, column := range eventColumns {
+		logged[strings.ToLower(column)] = true
+		if !current[strings.ToLower(column)] {
+			removed = append(removed, column)
+		}
+	}
+	for _, column := range currentColumns {
+		if !logged[strings.ToLower(column)] {
+			added = append(added, column)
+		}
+	}
+
+	var parts []string
+	if len(removed) > 0 {
+		parts = append(parts, fmt.Sprintf(T("없어진 컬럼: %s"), strings.Join(removed, ", ")))
+	}
+	if len(added) > 0 {
+		parts = append(parts, fmt.Sprintf(T("추가된 컬럼: %s"), strings.Join(added, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// 현재 스키마와 달라진 테이블 요약 (--schema-drift)
+func writeSchemaDrift(w io.Writer, drifted []*driftTable) {
+	if len(drifted) == 0 {
+		fmt.Fprintln(w, T(">> 스키마 변경 확인: 이벤트가 참조하는 테이블과 컬럼이 모두 현재 스키마와 같습니다"))
+		return
+	}
+
+	total := 0
+	for _, table := range drifted {
+		total += table.events
+	}
+	fmt.Fprintf(w, T(">> 스키마 변경 확인: 현재 스키마와 달라 그대로 재실행할 수 없는 이벤트 %d개\n"), total)
+	for _, table := range drifted {
+		fmt.Fprintf(w, T("   %s: %d개 (%s:%d ~ %s:%d) - %s\n"), table.name, table.events,
+			table.first.Filename, table.first.StartPosition, table.last.Filename, table.last.StartPosition, table.reason)
+	}
+}
@@ -0,0 +1,609 @@
+package src
+
+import (
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// DDL 변경 종류
+const (
+	ddlCreateTable = "create"
+	ddlDropTable   = "drop"
+	ddlRenameTable = "rename"
+	ddlAlterTable  = "alter"
+)
+
+// ALTER TABLE 절 종류
+const (
+	alterAddColumn    = "add"
+	alterDropColumn   = "drop"
+	alterChangeColumn = "change" // CHANGE/RENAME COLUMN (이름 변경)
+	alterMoveColumn   = "move"   // MODIFY ... FIRST/AFTER (위치만 변경)
+)
+
+// 테이블 하나에 대한 DDL 변경 내용
+type ddlChange struct {
+	kind     string
+	table    string   // schema.table
+	newTable string   // RENAME 대상
+	columns  []string // CREATE TABLE 컬럼 (LIKE/SELECT 등으로 알 수 없으면 nil)
+	ops      []alterOp
+}
+
+// ALTER TABLE의 컬럼 변경 절
+type alterOp struct {
+	kind     string
+	column   string
+	newName  string
+	first    bool
+	after    string
+	position bool // FIRST/AFTER 지정 여부
+}
+
+// DDL을 반영한 테이블 컬럼 구성 추적 (Debezium 방식)
+// 현재 스키마 스냅샷에서 구간 내 DDL을 역으로 적용해 구간 시작 시점의 구성을 만든 뒤,
+// 이벤트 순서대로 DDL을 다시 적용하면서 각 row 이벤트에 그 시점의 컬럼 이름을 붙인다.
+type SchemaHistory struct {
+	snapshot *SchemaSnapshot
+	tables   map[string][]string // key: schema.table, nil이면 구성을 알 수 없음
+}
+
+// 새 스키마 히스토리 생성
+func NewSchemaHistory(snapshot *SchemaSnapshot) *SchemaHistory {
+	return &SchemaHistory{
+		snapshot: snapshot,
+		tables:   make(map[string][]string),
+	}
+}
+
+// 정렬된 이벤트에 DDL 히스토리를 적용하여 row 이벤트의 컬럼 이름과 SQL을 갱신
+// DDL이 없는 테이블은 추출 시점의 스냅샷 기준 결과를 그대로 유지한다.
+func (sh *SchemaHistory) Apply(events []config.SQLEvent, renderer *SQLExtractor) int {
+	// 1. 구간 내 DDL 수집
+	var changes [][]ddlChange
+	affected := make(map[string]bool)
+	for _, event := range events {
+		if event.EventType != "QUERY" {
+			changes = append(changes, nil)
+			continue
+		}
+		parsed := parseDDL(event.Database, event.SQL)
+		changes = append(changes, parsed)
+		for _, change := range parsed {
+			affected[change.table] = true
+			if change.newTable != "" {
+				affected[change.newTable] = true
+			}
+		}
+	}
+	if len(affected) == 0 {
+		return 0
+	}
+
+	// 2. 현재 스키마에서 시작하여 DDL을 역순으로 되돌려 구간 시작 시점 구성 계산
+	for name := range affected {
+		sh.tables[name] = sh.currentColumns(name)
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		for j := len(changes[i]) - 1; j >= 0; j-- {
+			sh.revert(changes[i][j])
+		}
+	}
+
+	// 3. 이벤트 순서대로 DDL을 적용하면서 row 이벤트 컬럼 갱신
+	updated := 0
+	for i := range events {
+		for _, change := range changes[i] {
+			sh.apply(change)
+		}
+
+		event := &events[i]
+		if event.EventType == "QUERY" || len(event.Rows) == 0 {
+			continue
+		}
+		name := event.Database + "." + event.Table
+		if !affected[name] || event.ColumnsFromBinlog {
+			continue
+		}
+
+		columns := sh.tables[name]
+		if len(columns) != len(event.Rows[0]) {
+			// 구성을 알 수 없거나 실제 row와 맞지 않으면 col_N으로 출력
+			columns = nil
+		}
+		event.Columns = columns
+		event.SQL = renderer.formatRowsSQL(event)
+		updated++
+	}
+
+	return updated
+}
+
+// 현재 스키마 스냅샷의 컬럼 목록
+func (sh *SchemaHistory) currentColumns(name string) []string {
+	schema, table, _ := strings.Cut(name, ".")
+	if ts := sh.snapshot.Table(schema, table); ts != nil {
+		return ts.ColumnNames()
+	}
+	return nil
+}
+
+// DDL을 정방향으로 적용
+func (sh *SchemaHistory) apply(change ddlChange) {
+	switch change.kind {
+	case ddlCreateTable:
+		sh.tables[change.table] = change.columns
+	case ddlDropTable:
+		sh.tables[change.table] = nil
+	case ddlRenameTable:
+		sh.tables[change.newTable] = sh.tables[change.table]
+		sh.tables[change.table] = nil
+	case ddlAlterTable:
+		columns := sh.tables[change.table]
+		if columns == nil {
+			return
+		}
+		for _, op := range change.ops {
+			columns = applyAlterOp(columns, op)
+			if columns == nil {
+				break
+			}
+		}
+		sh.tables[change.table] = columns
+	}
+}
+
+// DDL을 역방향으로 되돌림 (되돌릴 수 없는 변경은 구성을 알 수 없음으로 처리)
+func (sh *SchemaHistory) revert(change ddlChange) {
+	switch change.kind {
+	case ddlCreateTable, ddlDropTable:
+		// CREATE 이전에는 테이블이 없고, DROP 이전 구성은 스냅샷으로 알 수 없음
+		sh.tables[change.table] = nil
+	case ddlRenameTable:
+		sh.tables[change.table] = sh.tables[change.newTable]
+		sh.tables[change.newTable] = nil
+	case ddlAlterTable:
+		columns := sh.tables[change.table]
+		if columns == nil {
+			return
+		}
+		for i := len(change.ops) - 1; i >= 0 && columns != nil; i-- {
+			op := change.ops[i]
+			switch op.kind {
+			case alterAddColumn:
+				columns = removeColumn(columns, op.column)
+			case alterChangeColumn:
+				if op.position {
+					columns = nil
+				} else {
+					columns = renameColumn(columns, op.newName, op.column)
+				}
+			default:
+				// DROP/위치 변경은 이전 위치를 알 수 없음
+				columns = nil
+			}
+		}
+		sh.tables[change.table] = columns
+	}
+}
+
+// ALTER 절 하나를 컬럼 목록에 적용
+func applyAlterOp(columns []string, op alterOp) []string {
+	switch op.kind {
+	case alterAddColumn:
+		return insertColumn(columns, op.column, op)
+	case alterDropColumn:
+		return removeColumn(columns, op.column)
+	case alterChangeColumn:
+		columns = renameColumn(columns, op.column, op.newName)
+		if op.position && columns != nil {
+			columns = insertColumn(removeColumn(columns, op.newName), op.newName, op)
+		}
+		return columns
+	case alterMoveColumn:
+		if columns = removeColumn(columns, op.column); columns == nil {
+			return nil
+		}
+		return insertColumn(columns, op.column, op)
+	}
+	return columns
+}
+
+// 컬럼 위치 (대소문자 무시)
+func columnIndex(columns []string, name string) int {
+	for i, col := range columns {
+		if strings.EqualFold(col, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FIRST/AFTER를 반영하여 컬럼 추가
+func insertColumn(columns []string, name string, op alterOp) []string {
+	pos := len(columns)
+	if op.first {
+		pos = 0
+	} else if op.after != "" {
+		idx := columnIndex(columns, op.after)
+		if idx < 0 {
+			return nil
+		}
+		pos = idx + 1
+	}
+
+	result := make([]string, 0, len(columns)+1)
+	result = append(result, columns[:pos]...)
+	result = append(result, name)
+	return append(result, columns[pos:]...)
+}
+
+// 컬럼 제거 (없으면 구성을 알 수 없음)
+func removeColumn(columns []string, name string) []string {
+	idx := columnIndex(columns, name)
+	if idx < 0 {
+		return nil
+	}
+	result := make([]string, 0, len(columns)-1)
+	result = append(result, columns[:idx]...)
+	return append(result, columns[idx+1:]...)
+}
+
+// 컬럼 이름 변경 (없으면 구성을 알 수 없음)
+func renameColumn(columns []string, from, to string) []string {
+	idx := columnIndex(columns, from)
+	if idx < 0 {
+		return nil
+	}
+	result := append([]string(nil), columns...)
+	result[idx] = to
+	return result
+}
+
+// DDL 문 파싱 (테이블 구성에 영향이 없는 문장이면 nil)
+func parseDDL(defaultSchema, query string) []ddlChange {
+	tokens := tokenizeSQL(query)
+	if len(tokens) < 3 {
+		return nil
+	}
+
+	switch strings.ToUpper(tokens[0]) {
+	case "CREATE":
+		return parseCreateTable(defaultSchema, tokens[1:])
+	case "DROP":
+		return parseDropTable(defaultSchema, tokens[1:])
+	case "RENAME":
+		return parseRenameTable(defaultSchema, tokens[1:])
+	case "ALTER":
+		return parseAlterTable(defaultSchema, tokens[1:])
+	}
+	return nil
+}
+
+// CREATE [TEMPORARY] TABLE [IF NOT EXISTS] name (...)
+func parseCreateTable(defaultSchema string, tokens []string) []ddlChange {
+	tokens = skipKeywords(tokens, "TEMPORARY")
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0], "TABLE") {
+		return nil
+	}
+	tokens = skipKeywords(tokens[1:], "IF", "NOT", "EXISTS")
+
+	name, rest := parseTableName(defaultSchema, tokens)
+	if name == "" {
+		return nil
+	}
+
+	change := ddlChange{kind: ddlCreateTable, table: name}
+	if len(rest) > 0 && rest[0] == "(" {
+		body, _ := splitParenthesized(rest)
+		for _, def := range splitTopLevel(body, ",") {
+			if len(def) == 0 || isIndexDefinition(def[0]) {
+				continue
+			}
+			change.columns = append(change.columns, def[0])
+		}
+	}
+	return []ddlChange{change}
+}
+
+// DROP [TEMPORARY] TABLE [IF EXISTS] a, b
+func parseDropTable(defaultSchema string, tokens []string) []ddlChange {
+	tokens = skipKeywords(tokens, "TEMPORARY")
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0], "TABLE") && !strings.EqualFold(tokens[0], "TABLES") {
+		return nil
+	}
+	tokens = skipKeywords(tokens[1:], "IF", "EXISTS")
+
+	var changes []ddlChange
+	for _, part := range splitTopLevel(tokens, ",") {
+		if name, _ := parseTableName(defaultSchema, part); name != "" {
+			changes = append(changes, ddlChange{kind: ddlDropTable, table: name})
+		}
+	}
+	return changes
+}
+
+// RENAME TABLE a TO b, c TO d
+func parseRenameTable(defaultSchema string, tokens []string) []ddlChange {
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0], "TABLE") {
+		return nil
+	}
+
+	var changes []ddlChange
+	for _, part := range splitTopLevel(tokens[1:], ",") {
+		from, rest := parseTableName(defaultSchema, part)
+		if from == "" || len(rest) < 2 || !strings.EqualFold(rest[0], "TO") {
+			continue
+		}
+		if to, _ := parseTableName(defaultSchema, rest[1:]); to != "" {
+			changes = append(changes, ddlChange{kind: ddlRenameTable, table: from, newTable: to})
+		}
+	}
+	return changes
+}
+
+// ALTER TABLE name 절1, 절2, ...
+func parseAlterTable(defaultSchema string, tokens []string) []ddlChange {
+	tokens = skipKeywords(tokens, "ONLINE", "OFFLINE", "IGNORE")
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0], "TABLE") {
+		return nil
+	}
+
+	name, rest := parseTableName(defaultSchema, tokens[1:])
+	if name == "" {
+		return nil
+	}
+
+	change := ddlChange{kind: ddlAlterTable, table: name}
+	var renameTo string
+	for _, clause := range splitTopLevel(rest, ",") {
+		if len(clause) == 0 {
+			continue
+		}
+		keyword := strings.ToUpper(clause[0])
+		args := clause[1:]
+
+		switch keyword {
+		case "ADD":
+			args = skipKeywords(args, "COLUMN")
+			if len(args) == 0 || isIndexDefinition(args[0]) {
+				continue
+			}
+			if args[0] == "(" {
+				// ADD COLUMN (a INT, b INT)
+				body, _ := splitParenthesized(args)
+				for _, def := range splitTopLevel(body, ",") {
+					if len(def) > 0 {
+						change.ops = append(change.ops, alterOp{kind: alterAddColumn, column: def[0]})
+					}
+				}
+				continue
+			}
+			change.ops = append(change.ops, withPosition(alterOp{kind: alterAddColumn, column: args[0]}, args[1:]))
+
+		case "DROP":
+			args = skipKeywords(args, "COLUMN")
+			if len(args) == 0 || isIndexDefinition(args[0]) {
+				continue
+			}
+			change.ops = append(change.ops, alterOp{kind: alterDropColumn, column: args[0]})
+
+		case "CHANGE":
+			args = skipKeywords(args, "COLUMN")
+			if len(args) < 2 {
+				continue
+			}
+			change.ops = append(change.ops, withPosition(alterOp{kind: alterChangeColumn, column: args[0], newName: args[1]}, args[2:]))
+
+		case "MODIFY":
+			args = skipKeywords(args, "COLUMN")
+			if len(args) == 0 {
+				continue
+			}
+			op := withPosition(alterOp{kind: alterMoveColumn, column: args[0]}, args[1:])
+			if op.position {
+				change.ops = append(change.ops, op)
+			}
+
+		case "RENAME":
+			if len(args) == 0 {
+				continue
+			}
+			switch strings.ToUpper(args[0]) {
+			case "COLUMN":
+				if len(args) >= 4 && strings.EqualFold(args[2], "TO") {
+					change.ops = append(change.ops, alterOp{kind: alterChangeColumn, column: args[1], newName: args[3]})
+				}
+			case "INDEX", "KEY":
+				// 인덱스 이름 변경은 컬럼 구성과 무관
+			default:
+				args = skipKeywords(args, "TO", "AS")
+				renameTo, _ = parseTableName(defaultSchema, args)
+			}
+		}
+	}
+
+	var changes []ddlChange
+	if len(change.ops) > 0 {
+		changes = append(changes, change)
+	}
+	if renameTo != "" {
+		changes = append(changes, ddlChange{kind: ddlRenameTable, table: name, newTable: renameTo})
+	}
+	return changes
+}
+
+// 컬럼 정의 뒤의 FIRST / AFTER col 위치 지정 확인
+func withPosition(op alterOp, definition []string) alterOp {
+	for i, token := range definition {
+		switch strings.ToUpper(token) {
+		case "FIRST":
+			op.first = true
+			op.position = true
+		case "AFTER":
+			if i+1 < len(definition) {
+				op.after = definition[i+1]
+				op.position = true
+			}
+		}
+	}
+	return op
+}
+
+// 인덱스/제약조건 정의인지 확인
+func isIndexDefinition(token string) bool {
+	switch strings.ToUpper(token) {
+	case "PRIMARY", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN", "FULLTEXT", "SPATIAL", "CHECK", "PARTITION":
+		return true
+	}
+	return false
+}
+
+// 앞쪽의 지정된 키워드들 건너뛰기
+func skipKeywords(tokens []string, keywords ...string) []string {
+	for len(tokens) > 0 {
+		matched := false
+		for _, keyword := range keywords {
+			if strings.EqualFold(tokens[0], keyword) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			break
+		}
+		tokens = tokens[1:]
+	}
+	return tokens
+}
+
+// [schema.]table 파싱
+func parseTableName(defaultSchema string, tokens []string) (string, []string) {
+	if len(tokens) == 0 || tokens[0] == "(" || tokens[0] == "," {
+		return "", tokens
+	}
+	if len(tokens) >= 3 && tokens[1] == "." {
+		return tokens[0] + "." + tokens[2], tokens[3:]
+	}
+	if defaultSchema == "" {
+		return "", tokens[1:]
+	}
+	return defaultSchema + "." + tokens[0], tokens[1:]
+}
+
+// 첫 토큰의 괄호 안쪽 내용과 나머지 반환
+func splitParenthesized(tokens []string) ([]string, []string) {
+	depth := 0
+	for i, token := range tokens {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return tokens[1:i], tokens[i+1:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// 괄호 밖의 구분자 기준으로 토큰 분리
+func splitTopLevel(tokens []string, sep string) [][]string {
+	var parts [][]string
+	var current []string
+	depth := 0
+	for _, token := range tokens {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if token == sep && depth == 0 {
+			parts = append(parts, current)
+			current = nil
+			continue
+		}
+		current = append(current, token)
+	}
+	return append(parts, current)
+}
+
+// 간단한 SQL 토큰 분리 (주석 제거, 백틱/따옴표 처리)
+func tokenizeSQL(query string) []string {
+	var tokens []string
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// /* ... */ 주석
+			j := i + 2
+			for j+1 < len(runes) && !(runes[j] == '*' && runes[j+1] == '/') {
+				j++
+			}
+			i = j + 2
+
+		case r == '#' || (r == '-' && i+1 < len(runes) && runes[i+1] == '-'):
+			// 한 줄 주석
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '`':
+			// 백틱 식별자
+			var sb strings.Builder
+			i++
+			for i < len(runes) {
+				if runes[i] == '`' {
+					if i+1 < len(runes) && runes[i+1] == '`' {
+						sb.WriteRune('`')
+						i += 2
+						continue
+					}
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			i++
+			tokens = append(tokens, sb.String())
+
+		case r == '\'' || r == '"':
+			// 문자열 리터럴
+			quote := r
+			start := i
+			i++
+			for i < len(runes) && runes[i] != quote {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			if i > len(runes) {
+				i = len(runes)
+			}
+			tokens = append(tokens, string(runes[start:i]))
+
+		case r == '(' || r == ')' || r == ',' || r == '.' || r == ';' || r == '=':
+			tokens = append(tokens, string(r))
+			i++
+
+		default:
+			start := i
+			for i < len(runes) && !strings.ContainsRune(" \t\n\r(),.;=`'\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens
+}
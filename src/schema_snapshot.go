@@ -0,0 +1,197 @@
+package src
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 컬럼 정보 (information_schema.COLUMNS 기준)
+type ColumnInfo struct {
+	Name       string
+	Type       string
+	PrimaryKey bool
+
+	Generated bool // 생성 컬럼 (VIRTUAL/STORED GENERATED, 값을 직접 넣을 수 없음)
+	Invisible bool // INVISIBLE 컬럼 (SELECT *에 나오지 않음)
+
+	Nullable    bool  // NULL 허용
+	OctetLength int64 // 문자열 컬럼의 최대 바이트 수 (문자셋 반영, 그 밖의 컬럼은 0)
+}
+
+// 테이블 스키마 정보
+type TableSchema struct {
+	Schema  string
+	Table   string
+	Columns []ColumnInfo
+}
+
+// 컬럼 이름 목록
+func (ts *TableSchema) ColumnNames() []string {
+	names := make([]string, len(ts.Columns))
+	for i, col := range ts.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// 분석 대상 구간에서 발견된 테이블들의 스키마 스냅샷
+// 처음 발견된 시점에 information_schema에서 한 번만 조회하여 이후 스키마가 바뀌어도 동일한 정보로 해석
+type SchemaSnapshot struct {
+	conn       *sql.DB
+	CapturedAt time.Time
+
+	mu     sync.Mutex
+	tables map[string]*TableSchema // key: schema.table (조회 실패 시 nil)
+}
+
+// 새 스키마 스냅샷 생성
+func NewSchemaSnapshot(conn *sql.DB) *SchemaSnapshot {
+	return &SchemaSnapshot{
+		conn:       conn,
+		CapturedAt: time.Now().UTC(),
+		tables:     make(map[string]*TableSchema),
+	}
+}
+
+// 테이블 스키마 조회 (최초 조회 시 information_schema에서 가져와 캐시)
+func (ss *SchemaSnapshot) Table(schema, table string) *TableSchema {
+	if ss == nil || ss.conn == nil || schema == "" || table == "" {
+		return nil
+	}
+
+	key := schema + "." + table
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ts, ok := ss.tables[key]; ok {
+		return ts
+	}
+
+	ts, err := ss.load(schema, table)
+	if err != nil {
+		ts = nil
+	}
+	ss.tables[key] = ts
+	return ts
+}
+
+// information_schema에서 컬럼 정보 조회
+func (ss *SchemaSnapshot) load(schema, table string) (*TableSchema, error) {
+	rows, err := ss.conn.Query(`SELECT COLUMN_NAME, COLUMN_TYPE, COLUMN_KEY, EXTRA, IS_NULLABLE, CHARACTER_OCTET_LENGTH
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION`, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ts := &TableSchema{Schema: schema, Table: table}
+	for rows.Next() {
+		var name, columnType, columnKey, extra, nullable string
+		var octetLength sql.NullInt64
+		if err := rows.Scan(&name, &columnType, &columnKey, &extra, &nullable, &octetLength); err != nil {
+			return nil, err
+		}
+		// EXTRA의 DEFAULT_GENERATED는 기본값 식이 있는 일반 컬럼
+		extra = strings.ToUpper(extra)
+		ts.Columns = append(ts.Columns, ColumnInfo{
+			Name:       name,
+			Type:       columnType,
+			PrimaryKey: columnKey == "PRI",
+
+			Generated: strings.Contains(extra, "VIRTUAL GENERATED") || strings.Contains(extra, "STORED GENERATED"),
+			Invisible: strings.Contains(extra, "INVISIBLE"),
+
+			Nullable:    nullable == "YES",
+			OctetLength: octetLength.Int64,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ts.Columns) == 0 {
+		// 이미 삭제된 테이블
+		return nil, fmt.Errorf("테이블 %s.%s 정보 없음", schema, table)
+	}
+	return ts, nil
+}
+
+// 이미 조회한 테이블 스키마 (조회하지 않았으면 ok = false, 찾지 못한 테이블은 nil, true)
+func (ss *SchemaSnapshot) cached(schema, table string) (ts *TableSchema, ok bool) {
+	if ss == nil {
+		return nil, false
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ts, ok = ss.tables[schema+"."+table]
+	return ts, ok
+}
+
+// 스냅샷에 포함된 테이블 목록 (이름순)
+func (ss *SchemaSnapshot) Tables() []*TableSchema {
+	if ss == nil {
+		return nil
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var tables []*TableSchema
+	for _, ts := range ss.tables {
+		if ts != nil {
+			tables = append(tables, ts)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Table < tables[j].Table
+	})
+	return tables
+}
+
+// 스냅샷에서 찾을 수 없었던 테이블 목록 (삭제되었거나 권한 없음)
+func (ss *SchemaSnapshot) MissingTables() []string {
+	if ss == nil {
+		return nil
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var missing []string
+	for key, ts := range ss.tables {
+		if ts == nil {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// 컬럼 정의를 한 줄로 표현 (예: id int PK, name varchar(50), name_upper varchar(50) GENERATED)
+func (ts *TableSchema) describe() string {
+	parts := make([]string, len(ts.Columns))
+	for i, col := range ts.Columns {
+		parts[i] = fmt.Sprintf("%s %s", col.Name, col.Type)
+		if col.PrimaryKey {
+			parts[i] += " PK"
+		}
+		if col.Generated {
+			parts[i] += " GENERATED"
+		}
+		if col.Invisible {
+			parts[i] += " INVISIBLE"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,527 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 상태 확인 쿼리 타임아웃
+const healthCheckTimeout = 5 * time.Second
+
+// Server 서버 모드 (serve 서브커맨드)
+type Server struct {
+	Config  config.Config            // 기본 접속 대상 (?target= 없이 요청한 경우)
+	Targets map[string]config.Config // 설정 파일의 이름 있는 접속 대상
+	Addr    string                   // 수신 주소 (예: :8080)
+
+	JobDir            string // 분석 작업 상태/결과 보관 디렉터리
+	MaxConcurrentJobs int    // 동시에 실행할 분석 작업 수
+
+	Tokens   []APIToken // API 토큰
+	AuditLog io.Writer  // 감사 로그 (JSON lines, 없으면 서버 로그에 기록)
+
+	InsecureNoAuth bool // 토큰이 없을 때 인증 없이 모든 요청 허용 (--insecure-no-auth)
+
+	jobs  *JobQueue
+	audit *auditLogger
+	mu    sync.Mutex
+	conns map[string]*BinlogAnalyzer // 접속 대상별 상태 확인용 연결 ("" = 기본 대상)
+	feeds map[string]*liveFeed       // 접속 대상별 실시간 이벤트 피드 (/stream)
+}
+
+// 존재하지 않는 접속 대상
+var errUnknownTarget = errors.New("알 수 없는 접속 대상")
+
+// Run HTTP 서버 실행 (ctx 취소 시 종료)
+func (s *Server) Run(ctx context.Context) error {
+	s.conns = make(map[string]*BinlogAnalyzer)
+	s.feeds = make(map[string]*liveFeed)
+	s.audit = newAuditLogger(s.AuditLog)
+	if len(s.Tokens) == 0 {
+		if !s.InsecureNoAuth {
+			return fmt.Errorf("API 토큰이 설정되지 않았습니다 (설정 파일의 tokens에 추가하거나, 인증 없이 실행하려면 --insecure-no-auth 지정)")
+		}
+		logrus.Warnf("API 토큰이 설정되지 않아 인증 없이 모든 요청을 허용합니다 (--insecure-no-auth)")
+	}
+	defer s.closeConnections()
+
+	jobs, err := NewJobQueue(s.JobDir, s.MaxConcurrentJobs, s.analysisConfig)
+	if err != nil {
+		return err
+	}
+	s.jobs = jobs
+
+	// 기본 대상은 미리 연결 (MySQL이 아직 준비되지 않아도 서버는 시작하고 /readyz로 알림)
+	if s.Config.Host != "" {
+		ba, err := s.connection("")
+		if err != nil {
+			return fmt.Errorf("MySQL 연결 설정 실패: %v", err)
+		}
+		if err := ba.conn.Ping(); err != nil {
+			logrus.Warnf("MySQL 연결 실패 (준비될 때까지 /readyz는 503 응답): %v", err)
+		}
+	}
+
+	httpServer := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		// 종료 시 /stream 같은 장시간 연결도 끝나도록 요청 컨텍스트를 ctx에서 파생
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	logrus.Infof("서버 시작: %s (접속 대상 %d개)", s.Addr, len(s.Targets))
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.HandleFunc("GET /targets", s.authorize(RoleViewer, s.handleTargets))
+	mux.HandleFunc("POST /analyses", s.authorize(RoleOperator, s.handleSubmitAnalysis))
+	mux.HandleFunc("GET /analyses", s.authorize(RoleViewer, s.handleListAnalyses))
+	mux.HandleFunc("GET /analyses/{id}", s.authorize(RoleViewer, s.handleGetAnalysis))
+	mux.HandleFunc("GET /analyses/{id}/result", s.authorize(RoleViewer, s.handleAnalysisResult))
+	mux.HandleFunc("GET /analyses/{id}/events", s.authorize(RoleViewer, s.handleAnalysisEvents))
+	mux.HandleFunc("DELETE /analyses/{id}", s.authorize(RoleOperator, s.handleCancelAnalysis))
+	mux.HandleFunc("GET /stream", s.authorize(RoleViewer, s.handleStream))
+	mux.Handle("GET /", uiHandler())
+	return mux
+}
+
+// 접속 대상 설정 (이름이 비어 있으면 기본 대상)
+func (s *Server) targetConfig(name string) (config.Config, error) {
+	if name == "" {
+		if s.Config.Host == "" {
+			return config.Config{}, fmt.Errorf("기본 접속 대상이 없습니다 (?target=으로 지정)")
+		}
+		return s.Config, nil
+	}
+	cfg, ok := s.Targets[strings.ToLower(name)]
+	if !ok {
+		return config.Config{}, fmt.Errorf("%w: %s", errUnknownTarget, name)
+	}
+	return cfg, nil
+}
+
+// 접속 대상의 연결 (처음 요청될 때 연결하고 재사용)
+func (s *Server) connection(name string) (*BinlogAnalyzer, error) {
+	name = strings.ToLower(name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ba, ok := s.conns[name]; ok {
+		return ba, nil
+	}
+
+	cfg, err := s.targetConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ping 실패는 상태 확인에서 다시 확인하므로 연결 객체만 있으면 보관
+	ba := &BinlogAnalyzer{Config: cfg}
+	if err := ba.connect(); err != nil && ba.conn == nil {
+		return nil, err
+	}
+	s.conns[name] = ba
+	return ba, nil
+}
+
+func (s *Server) closeConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ba := range s.conns {
+		ba.conn.Close()
+	}
+}
+
+// 요청의 ?target= 에 해당하는 연결 (없는 대상이면 404 응답 후 nil)
+func (s *Server) requestConnection(w http.ResponseWriter, r *http.Request) (*BinlogAnalyzer, error) {
+	ba, err := s.connection(r.URL.Query().Get("target"))
+	if errors.Is(err, errUnknownTarget) {
+		writeJSONError(w, http.StatusNotFound, err)
+		return nil, err
+	}
+	return ba, err
+}
+
+// 오류 응답
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// 접속 대상 정보 (비밀번호 제외)
+type targetInfo struct {
+	Name string `json:"name"`
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	User string `json:"user"`
+}
+
+// /targets: 설정된 접속 대상 목록
+func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
+	targets := make([]targetInfo, 0, len(s.Targets)+1)
+	if s.Config.Host != "" {
+		targets = append(targets, targetInfo{Host: s.Config.Host, Port: s.Config.Port, User: s.Config.User})
+	}
+	for name, cfg := range s.Targets {
+		targets = append(targets, targetInfo{Name: name, Host: cfg.Host, Port: cfg.Port, User: cfg.User})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(targets)
+}
+
+// 상태 확인 응답
+type healthResponse struct {
+	Status string            `json:"status"` // ok, fail
+	Checks map[string]string `json:"checks"` // 항목별 결과 (ok 또는 오류 메시지)
+}
+
+// /healthz: MySQL 연결 확인 (?target=으로 접속 대상 선택)
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	ba, err := s.requestConnection(w, r)
+	if errors.Is(err, errUnknownTarget) {
+		return
+	}
+
+	checks := map[string]error{"mysql": err}
+	if err == nil {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+		checks["mysql"] = ba.conn.PingContext(ctx)
+	}
+	s.writeHealth(w, checks)
+}
+
+// /readyz: MySQL 연결과 binary log 읽기 권한 확인 (?target=으로 접속 대상 선택)
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ba, err := s.requestConnection(w, r)
+	if errors.Is(err, errUnknownTarget) {
+		return
+	}
+
+	checks := map[string]error{"mysql": err}
+	if err == nil {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+
+		checks["mysql"] = ba.conn.PingContext(ctx)
+		if checks["mysql"] == nil {
+			checks["replication_client"] = ba.checkBinaryLogsAccess(ctx)
+			checks["replication_slave"] = ba.checkReplicationGrant(ctx)
+		}
+	}
+	s.writeHealth(w, checks)
+}
+
+func (s *Server) writeHealth(w http.ResponseWriter, checks map[string]error) {
+	response := healthResponse{Status: "ok", Checks: make(map[string]string, len(checks))}
+	status := http.StatusOK
+	for name, err := range checks {
+		if err != nil {
+			response.Status = "fail"
+			response.Checks[name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		response.Checks[name] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SHOW BINARY LOGS 실행 가능 여부 (REPLICATION CLIENT 권한, binary log 활성화)
+func (ba *BinlogAnalyzer) checkBinaryLogsAccess(ctx context.Context) error {
+	rows, err := ba.conn.QueryContext(ctx, "SHOW BINARY LOGS")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("binary log 파일이 없습니다")
+	}
+	return nil
+}
+
+// 현재 계정에 REPLICATION SLAVE 권한이 있는지 확인 (binary log 덤프에 필요)
+func (ba *BinlogAnalyzer) checkReplicationGrant(ctx context.Context) error {
+	rows, err := ba.conn.QueryContext(ctx, "SHOW GRANTS")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return err
+		}
+		if hasReplicationGrant(grant) {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("REPLICATION SLAVE 권한이 없습니다")
+}
+
+// GRANT 문이 전역 REPLICATION SLAVE(또는 ALL PRIVILEGES)를 포함하는지 확인
+func hasReplicationGrant(grant string) bool {
+	grant = strings.ToUpper(grant)
+	if !strings.Contains(grant, " ON *.* ") {
+		return false
+	}
+	return strings.Contains(grant, "ALL PRIVILEGES") ||
+		strings.Contains(grant, "REPLICATION SLAVE") ||
+		strings.Contains(grant, "REPLICATION REPLICA")
+}
+
+// 분석 요청을 분석 설정으로 변환 (잘못된 요청이면 오류)
+func (s *Server) analysisConfig(req AnalysisRequest) (config.Config, error) {
+	cfg, err := s.targetConfig(req.Target)
+	if err != nil {
+		return cfg, err
+	}
+
+	startTime, err := ParseTime(req.StartTime)
+	if err != nil {
+		return cfg, fmt.Errorf("start_time 형식 오류: %v", err)
+	}
+	endTime, err := ParseTime(req.EndTime)
+	if err != nil {
+		return cfg, fmt.Errorf("end_time 형식 오류: %v", err)
+	}
+	if startTime.After(endTime) {
+		return cfg, fmt.Errorf("시작 시간이 종료 시간보다 늦을 수 없습니다")
+	}
+
+	cfg.StartTime = startTime.UTC()
+	cfg.EndTime = endTime.UTC()
+	cfg.ExcludeTableRegex = req.ExcludeTableRegex
+	cfg.Where = req.Where
+	cfg.FilterExpr = req.FilterExpr
+	cfg.OnlyErrors = req.OnlyErrors
+	if req.MinExecTime != "" {
+		if cfg.MinExecTime, err = time.ParseDuration(req.MinExecTime); err != nil {
+			return cfg, fmt.Errorf("min_exec_time 형식 오류: %v", err)
+		}
+	}
+	cfg.Replayable = req.Replayable
+	if req.Backend != "" {
+		cfg.Backend = req.Backend
+	}
+
+	if err := validateBackend(cfg.Backend); err != nil {
+		return cfg, err
+	}
+	if _, err := NewEventFilter(cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// POST /analyses: 분석 작업 제출
+func (s *Server) handleSubmitAnalysis(w http.ResponseWriter, r *http.Request) {
+	var req AnalysisRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("요청 형식 오류: %v", err))
+		return
+	}
+
+	record := requestAudit(r)
+	record.Target = req.Target
+	record.Request = &req
+
+	job, err := s.jobs.Submit(req, record.User)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	record.Job = job.ID
+	w.Header().Set("Location", "/analyses/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// GET /analyses: 작업 목록
+func (s *Server) handleListAnalyses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.jobs.List())
+}
+
+// GET /analyses/{id}: 작업 상태
+func (s *Server) handleGetAnalysis(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// GET /analyses/{id}/result: 결과 파일 다운로드 (완료된 작업만)
+func (s *Server) handleAnalysisResult(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	if job.Status != JobSucceeded {
+		writeJSONError(w, http.StatusConflict, fmt.Errorf("완료되지 않은 작업입니다 (%s)", job.Status))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="mysqlbinlogo-%s.sql"`, job.ID))
+
+	// 조건에 맞는 이벤트가 없으면 결과 파일이 만들어지지 않으므로 빈 응답
+	file, err := os.Open(s.jobs.ResultPath(job.ID))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+	io.Copy(w, file)
+}
+
+// 이벤트 목록 한 번에 돌려주는 최대 개수 (기본값)
+const defaultEventListLimit = 1000
+
+// 이벤트 목록 응답
+type eventList struct {
+	Total  int               `json:"total"` // 검색어에 맞는 전체 이벤트 수
+	Events []config.SQLEvent `json:"events"`
+}
+
+// GET /analyses/{id}/events: 완료된 작업의 이벤트 목록 (?q= 검색어, ?limit=, ?offset=)
+// 검색어는 SQL, 원본 SQL, 데이터베이스, 테이블, 이벤트 종류에서 대소문자 구분 없이 찾음
+func (s *Server) handleAnalysisEvents(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	if job.Status != JobSucceeded {
+		writeJSONError(w, http.StatusConflict, fmt.Errorf("완료되지 않은 작업입니다 (%s)", job.Status))
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset := defaultEventListLimit, 0
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("limit 형식 오류: %s", v))
+			return
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("offset 형식 오류: %s", v))
+			return
+		}
+	}
+	search := strings.ToLower(query.Get("q"))
+
+	result := eventList{Events: []config.SQLEvent{}}
+	file, err := os.Open(s.jobs.EventsPath(job.ID))
+	if errors.Is(err, os.ErrNotExist) {
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	for {
+		var event config.SQLEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err != io.EOF {
+				writeJSONError(w, http.StatusInternalServerError, err)
+				return
+			}
+			break
+		}
+		if search != "" && !eventContains(&event, search) {
+			continue
+		}
+		if result.Total >= offset && len(result.Events) < limit {
+			result.Events = append(result.Events, event)
+		}
+		result.Total++
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// 이벤트에 검색어(소문자)가 포함되어 있는지 여부
+func eventContains(event *config.SQLEvent, search string) bool {
+	for _, field := range []string{event.SQL, event.OriginalSQL, event.Database, event.Table, event.EventType} {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// DELETE /analyses/{id}: 실행 중이면 취소, 끝난 작업이면 삭제
+func (s *Server) handleCancelAnalysis(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	if job.finished() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
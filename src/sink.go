@@ -0,0 +1,184 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 이벤트 싱크 종류 (--sink)
+const (
+	SinkBigQuery = "bigquery"
+	SinkPostgres = "postgres"
+	SinkPubSub   = "pubsub"
+	SinkRabbitMQ = "rabbitmq"
+
+	SinkCloudWatch = "cloudwatch"
+
+	SinkSQLite = "sqlite"
+)
+
+// 싱크 전송 단위
+const (
+	sinkBatchSize     = 500             // 한 번에 보내는 최대 이벤트 수
+	sinkFlushInterval = 2 * time.Second // 실시간 추적 모드에서 모인 이벤트를 보내는 주기
+	sinkCloseTimeout  = 30 * time.Second
+)
+
+// EventSink 추출한 이벤트를 파일 대신 외부 저장소로 보내는 출력 대상
+type EventSink interface {
+	// Write 이벤트 전송 (최대 sinkBatchSize개씩 호출)
+	Write(ctx context.Context, events []config.SQLEvent) error
+	Close() error
+}
+
+func validateSink(cfg config.Config) error {
+	if cfg.SQLiteFile != "" && cfg.Sink != SinkSQLite {
+		return fmt.Errorf("--output-sqlite는 --sink %s와 함께 사용할 수 없습니다", cfg.Sink)
+	}
+	switch cfg.Sink {
+	case "":
+		return nil
+	case SinkBigQuery:
+		if cfg.BQDataset == "" || cfg.BQTable == "" {
+			return fmt.Errorf("--sink bigquery는 --bq-dataset, --bq-table이 필요합니다")
+		}
+	case SinkPostgres:
+		if cfg.PGDSN == "" || cfg.PGTable == "" {
+			return fmt.Errorf("--sink postgres는 --pg-dsn, --pg-table이 필요합니다")
+		}
+	case SinkPubSub:
+		if cfg.PubSubTopic == "" {
+			return fmt.Errorf("--sink pubsub는 --pubsub-topic이 필요합니다")
+		}
+	case SinkRabbitMQ:
+		if cfg.AMQPURL == "" || cfg.AMQPExchange == "" {
+			return fmt.Errorf("--sink rabbitmq는 --amqp-url, --amqp-exchange가 필요합니다")
+		}
+	case SinkCloudWatch:
+		if cfg.CWLogGroup == "" {
+			return fmt.Errorf("--sink cloudwatch는 --cw-log-group이 필요합니다")
+		}
+	case SinkSQLite:
+		if cfg.SQLiteFile == "" {
+			return fmt.Errorf("--sink sqlite는 --output-sqlite가 필요합니다")
+		}
+	default:
+		return fmt.Errorf("지원하지 않는 싱크: %s (bigquery, postgres, pubsub, rabbitmq, cloudwatch, sqlite 중 선택)", cfg.Sink)
+	}
+	if cfg.Replayable {
+		return fmt.Errorf("--replayable은 --sink와 함께 사용할 수 없습니다")
+	}
+	return nil
+}
+
+// --sink에 해당하는 싱크 생성 (대상 테이블이 없으면 생성)
+func newEventSink(ctx context.Context, cfg config.Config) (EventSink, error) {
+	switch cfg.Sink {
+	case SinkBigQuery:
+		return newBigQuerySink(ctx, cfg)
+	case SinkPostgres:
+		return newPostgresSink(ctx, cfg)
+	case SinkPubSub:
+		return newPubSubSink(ctx, cfg)
+	case SinkRabbitMQ:
+		return newRabbitMQSink(ctx, cfg)
+	case SinkCloudWatch:
+		return newCloudWatchSink(ctx, cfg)
+	case SinkSQLite:
+		return newSQLiteSink(ctx, cfg)
+	default:
+		return nil, validateSink(cfg)
+	}
+}
+
+// 싱크에서 이벤트를 구분하는 키 (중복 전송 제거용)
+func eventKey(event *config.SQLEvent) string {
+	return fmt.Sprintf("%s:%d", event.Filename, event.Position)
+}
+
+// 이벤트를 나누는 키 (Pub/Sub ordering key, RabbitMQ routing key)
+// row 이벤트는 db.table, DDL 등 쿼리 이벤트는 데이터베이스
+func eventTopicKey(event *config.SQLEvent) string {
+	if event.Table != "" {
+		return event.Database + "." + event.Table
+	}
+	return event.Database
+}
+
+// 분석 결과를 싱크로 전송
+func (ba *BinlogAnalyzer) writeToSink(ctx context.Context, events []config.SQLEvent) error {
+	sink, err := newEventSink(ctx, ba.Config)
+	if err != nil {
+		return fmt.Errorf("%s 싱크 생성 실패: %v", ba.Config.Sink, err)
+	}
+
+	for start := 0; start < len(events); start += sinkBatchSize {
+		end := min(start+sinkBatchSize, len(events))
+		if err := sink.Write(ctx, events[start:end]); err != nil {
+			sink.Close()
+			return fmt.Errorf("%s 싱크 전송 실패: %v", ba.Config.Sink, err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		return fmt.Errorf("%s 싱크 종료 실패: %v", ba.Config.Sink, err)
+	}
+
+	logrus.Infof(T("Analysis complete: %d SQL events sent to %s"), len(events), ba.Config.Sink)
+	return nil
+}
+
+// 실시간 추적 모드의 싱크 전송 버퍼 (sinkBatchSize개가 모이거나 sinkFlushInterval마다 전송)
+type sinkBuffer struct {
+	sink    EventSink
+	pending []config.SQLEvent
+	flushed time.Time
+}
+
+func newSinkBuffer(sink EventSink) *sinkBuffer {
+	return &sinkBuffer{sink: sink, flushed: time.Now()}
+}
+
+func (b *sinkBuffer) add(ctx context.Context, event *config.SQLEvent) error {
+	b.pending = append(b.pending, *event)
+	if len(b.pending) >= sinkBatchSize {
+		return b.flush(ctx)
+	}
+	return nil
+}
+
+// 주기가 지났으면 전송
+func (b *sinkBuffer) tick(ctx context.Context) error {
+	if time.Since(b.flushed) < sinkFlushInterval {
+		return nil
+	}
+	return b.flush(ctx)
+}
+
+func (b *sinkBuffer) flush(ctx context.Context) error {
+	b.flushed = time.Now()
+	if len(b.pending) == 0 {
+		return nil
+	}
+	if err := b.sink.Write(ctx, b.pending); err != nil {
+		return err
+	}
+	b.pending = b.pending[:0]
+	return nil
+}
+
+// 남은 이벤트 전송 후 종료 (추적이 취소된 뒤에도 전송되도록 별도 제한 시간 사용)
+func (b *sinkBuffer) close(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sinkCloseTimeout)
+	defer cancel()
+
+	err := b.flush(ctx)
+	if closeErr := b.sink.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
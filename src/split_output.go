@@ -0,0 +1,130 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 결과 파일 분할 기준 (--split-by)
+const (
+	SplitByFile     = "file"     // 이벤트가 기록된 binary log 파일별 (results.mysql-bin.000123.sql)
+	SplitByDatabase = "database" // 이벤트의 데이터베이스별 (results.shop.sql)
+)
+
+// 데이터베이스 없이 기록된 쿼리 이벤트의 분할 파일 이름 부분
+const splitNoDatabase = "_no_database"
+
+func validateSplit(cfg config.Config) error {
+	switch cfg.SplitBy {
+	case "":
+		return nil
+	case SplitByFile, SplitByDatabase:
+	default:
+		return fmt.Errorf("지원하지 않는 분할 기준: %s (file, database 중 선택)", cfg.SplitBy)
+	}
+	if cfg.OutputFile == "" {
+		return fmt.Errorf("--split-by는 분할 파일 이름의 기준이 되는 --output이 필요합니다")
+	}
+	if cfg.Sink != "" {
+		return fmt.Errorf("--split-by는 --sink와 함께 사용할 수 없습니다")
+	}
+	if cfg.Follow {
+		return fmt.Errorf("--split-by는 --follow와 함께 사용할 수 없습니다")
+	}
+	if strings.ContainsAny(cfg.SplitNameSep, `/\`) {
+		return fmt.Errorf("--split-name-sep에는 경로 구분자를 쓸 수 없습니다: %s", cfg.SplitNameSep)
+	}
+	return nil
+}
+
+// 이벤트가 들어갈 분할 파일의 이름 부분
+func splitKey(splitBy string, event *config.SQLEvent) string {
+	switch splitBy {
+	case SplitByFile:
+		return event.Filename
+	case SplitByDatabase:
+		if event.Database == "" {
+			return splitNoDatabase
+		}
+		return event.Database
+	default:
+		return ""
+	}
+}
+
+// 분할 파일 이름 (--output의 확장자 앞에 구분자와 기준 값을 넣음, results.sql → results.mysql-bin.000123.sql)
+// 구분자가 _이면 results_orders.sql
+func splitFileName(output, sep, key string) string {
+	// 파일 이름에 쓸 수 없는 문자 (경로 구분자 등) 치환
+	key = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, key)
+
+	ext := filepath.Ext(output)
+	return strings.TrimSuffix(output, ext) + sep + key + ext
+}
+
+// --split-name-sep (설정하지 않았으면 ".")
+func (ba *BinlogAnalyzer) splitNameSep() string {
+	if ba.Config.SplitNameSep == "" {
+		return "."
+	}
+	return ba.Config.SplitNameSep
+}
+
+// 기준 값별로 나누어 파일마다 결과 출력 (파일 순서는 기준 값이 처음 나온 순서)
+func (ba *BinlogAnalyzer) outputSplitResults(events []config.SQLEvent) error {
+	var keys []string
+	groups := make(map[string][]config.SQLEvent)
+	for i := range events {
+		key := splitKey(ba.Config.SplitBy, &events[i])
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], events[i])
+	}
+
+	// 이미 있는 파일이 하나라도 있으면 일부만 쓰고 멈추지 않도록 미리 확인
+	if !ba.Config.AppendOutput && !ba.Config.OverwriteOutput {
+		for _, key := range keys {
+			name := splitFileName(ba.Config.OutputFile, ba.splitNameSep(), key)
+			if _, err := os.Stat(name); err == nil {
+				return outputExistsError(name)
+			}
+		}
+	}
+
+	logrus.Infof(T("Analysis complete: %d SQL events"), len(events))
+	for _, key := range keys {
+		name := splitFileName(ba.Config.OutputFile, ba.splitNameSep(), key)
+		if err := ba.writeResultFile(name, groups[key]); err != nil {
+			return err
+		}
+		logrus.Infof(T("Results saved to %s (%d events)"), name, len(groups[key]))
+	}
+	return nil
+}
+
+func (ba *BinlogAnalyzer) writeResultFile(name string, events []config.SQLEvent) error {
+	output, err := createOutputFile(ba.Config, name)
+	if err != nil {
+		return err
+	}
+	err = ba.writeResults(output, events)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("결과 파일 %s 기록 실패: %v", name, err)
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package src
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,24 +21,30 @@ import (
 type SQLExtractor struct {
 	config config.Config
 	syncer *replication.BinlogSyncer
+	schema *SchemaSnapshot // 컬럼 이름 해석용 스키마 스냅샷 (없으면 col_N 사용)
+
+	rowsQuery string // 직전 Rows_query 이벤트의 원본 SQL (다음 row 이벤트들에 연결)
+
+	transaction      string    // 진행 중인 트랜잭션 식별자 (GTID 이벤트 또는 BEGIN에서 시작)
+	transactionBegun bool      // BEGIN으로 시작된 트랜잭션인지 (아니면 DDL처럼 쿼리 하나로 끝남)
+	commitTime       time.Time // 진행 중인 트랜잭션의 커밋 시각 (MySQL 8.0.1 이상, 마이크로초 단위)
+	execTime         uint32    // 진행 중인 트랜잭션 BEGIN의 exec_time (row 이벤트에 사용)
+
+	gtids *gtidCoverage   // 구간 안의 GTID를 기록할 곳 (분석 중에만 지정)
+	lags  *replicationLag // 구간 안의 복제 지연을 기록할 곳 (--replication-lag)
+
+	resume *incrementalState // 지난 실행이 끝난 위치 (--incremental, 그 파일은 이 위치부터 읽음)
 }
 
 // 새 SQL 추출기 생성
-func NewSQLExtractor(cfg config.Config) *SQLExtractor {
+func NewSQLExtractor(cfg config.Config, schema *SchemaSnapshot) *SQLExtractor {
 	// 생성 시점에 syncer 초기화
-	syncerCfg := replication.BinlogSyncerConfig{
-		ServerID: 100,
-		Flavor:   "mysql",
-		Host:     cfg.Host,
-		Port:     uint16(cfg.Port),
-		User:     cfg.User,
-		Password: cfg.Password,
-		Logger:   &config.NullLogger{},
-	}
+	syncerCfg := newSyncerConfig(cfg, 100)
 
 	return &SQLExtractor{
 		config: cfg,
 		syncer: replication.NewBinlogSyncer(syncerCfg),
+		schema: schema,
 	}
 }
 
@@ -48,18 +57,24 @@ func (se *SQLExtractor) Close() {
 }
 
 // 지정된 파일들에서 SQL 이벤트 추출
-func (se *SQLExtractor) ExtractSQLEvents(files []config.BinlogFile) ([]config.SQLEvent, error) {
+func (se *SQLExtractor) ExtractSQLEvents(ctx context.Context, files []config.BinlogFile) ([]config.SQLEvent, error) {
 	var allEvents []config.SQLEvent
 
 	for i, file := range files {
-		if se.config.Verbose {
+		if se.config.Verbose >= config.VerboseFiles {
 			logrus.Debugf("파일 분석 중: %s (%d/%d)\n", file.Name, i+1, len(files))
 		}
 
 		// 하나의 syncer로 각 파일 처리
-		events, err := se.ExtractFromSingleFile(file)
+		events, err := se.ExtractFromSingleFile(ctx, file)
+		var partial *partialReadError
+		if errors.As(err, &partial) {
+			// 끝까지 읽지 못한 파일도 읽은 만큼은 포함
+			logrus.Warnf("%v", err)
+			err = nil
+		}
 		if err != nil {
-			if se.config.Verbose {
+			if se.config.Verbose >= config.VerboseFiles {
 				logrus.Debugf("파일 %s 분석 실패: %v (계속 진행)\n", file.Name, err)
 			}
 			continue // 실패한 파일은 건너뛰고 계속
@@ -67,7 +82,7 @@ func (se *SQLExtractor) ExtractSQLEvents(files []config.BinlogFile) ([]config.SQ
 
 		allEvents = append(allEvents, events...)
 
-		if se.config.Verbose {
+		if se.config.Verbose >= config.VerboseFiles {
 			logrus.Debugf("파일 %s에서 %d개 이벤트 추출\n", file.Name, len(events))
 		}
 	}
@@ -75,23 +90,14 @@ func (se *SQLExtractor) ExtractSQLEvents(files []config.BinlogFile) ([]config.SQ
 	return allEvents, nil
 }
 
-// ExtractFromSingleFile 단일 파일에서 SQL 이벤트 추출 (각 파일마다 새로운 syncer 사용)
-func (se *SQLExtractor) ExtractFromSingleFile(file config.BinlogFile) ([]config.SQLEvent, error) {
+// ExtractFromSingleFile 단일 파일에서 SQL 이벤트 추출 (각 파일마다 새로운 syncer 사용, ctx 취소 시 오류 반환)
+func (se *SQLExtractor) ExtractFromSingleFile(parent context.Context, file config.BinlogFile) ([]config.SQLEvent, error) {
 	var events []config.SQLEvent
-
-	// 각 파일마다 새로운 syncer 생성
-	cfg := replication.BinlogSyncerConfig{
-		ServerID: 100,
-		Flavor:   "mysql",
-		Host:     se.config.Host,
-		Port:     uint16(se.config.Port),
-		User:     se.config.User,
-		Password: se.config.Password,
-		Logger:   &config.NullLogger{},
-	}
-	syncer := replication.NewBinlogSyncer(cfg)
+	se.rowsQuery = ""
+	se.endTransaction()
 
 	// 안전한 syncer 종료를 위한 함수
+	var stream binlogStream
 	var syncerClosed bool
 	safeSyncerClose := func() {
 		if !syncerClosed {
@@ -105,40 +111,58 @@ func (se *SQLExtractor) ExtractFromSingleFile(file config.BinlogFile) ([]config.
 				}()
 
 				// syncer가 이미 닫혀있는지 확인
-				if syncer != nil {
+				if stream != nil {
 					// Close() 호출 전에 잠시 대기
 					time.Sleep(10 * time.Millisecond)
-					syncer.Close()
+					stream.Close()
 				}
 			}()
 		}
 	}
 	defer safeSyncerClose()
 
-	// Binary log 스트리밍 시작
-	streamer, err := syncer.StartSync(mysql.Position{Name: file.Name, Pos: 4})
+	// 목록을 가져올 때의 끝 위치까지 이미 읽었으면 (--incremental로 이어 읽는 파일) 새 이벤트가 없음
+	position := se.readFrom(file.Name)
+	if reachedEnd(file, position) {
+		return nil, nil
+	}
+
+	// Binary log 스트리밍 시작 (각 파일마다 새로운 연결 사용)
+	stream, err := openBinlogStream(se.config, 100, mysql.Position{Name: file.Name, Pos: position})
 	if err != nil {
 		return nil, fmt.Errorf("파일 %s 스트리밍 시작 실패: %v", file.Name, err)
 	}
 
 	// 타임아웃 설정 (30초로 단축)
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(parent, 60*time.Second)
 	defer cancel()
 
-	eventCount := 0
-	maxEvents := 1000000 // 최대 이벤트 수 제한
-	totalEvents := 0     // 전체 이벤트 카운트 (디버깅용)
+	eventCount := 0  // 구간 안 이벤트 수 (--max-events-per-file)
+	totalEvents := 0 // 전체 이벤트 카운트 (디버깅용)
+	pastEnd := 0     // 연속된 종료 시간 이후 이벤트 수
 
-	for eventCount < maxEvents {
+	maxEvents := se.config.MaxEventsPerFile
+	for maxEvents <= 0 || eventCount < maxEvents {
 		select {
 		case <-ctx.Done():
-			if se.config.Verbose {
-				fmt.Printf("파일 %s 처리 시간 초과 (60초)\n", file.Name)
+			if parent.Err() != nil {
+				return events, parent.Err()
 			}
-			// 타임아웃 시 안전하게 종료
+			// 파일 끝까지 읽지 못했으므로 결과가 불완전함을 알림
 			safeSyncerClose()
-			return events, nil
+			return events, &partialReadError{file: file.Name, position: position, err: errFileTimeout}
 		default:
+			// 목록을 가져올 때의 파일 끝에 도달하면 종료
+			// 기록 중인 마지막 파일은 ROTATE가 오지 않으므로 다음 이벤트를 기다리지 않음
+			if reachedEnd(file, position) {
+				if se.config.Verbose >= config.VerboseProbe {
+					fmt.Fprintf(os.Stderr, "\n> 파일 %s: 끝 위치 %d 도달 (총 %d개 이벤트 처리, 조건 맞는 %d개)\n",
+						file.Name, position, totalEvents, len(events))
+				}
+				safeSyncerClose()
+				return events, nil
+			}
+
 			// 논블로킹으로 이벤트 가져오기 시도
 			ev, err := func() (*replication.BinlogEvent, error) {
 				defer func() {
@@ -147,55 +171,72 @@ func (se *SQLExtractor) ExtractFromSingleFile(file config.BinlogFile) ([]config.
 						err = fmt.Errorf("syncer panic: %v", r)
 					}
 				}()
-				return streamer.GetEvent(ctx)
+				return stream.GetEvent(ctx)
 			}()
 
 			if err != nil {
-				// 에러 발생 시 조용히 종료
-				if se.config.Verbose {
-					fmt.Printf("파일 %s: 이벤트 읽기 완료 (총 %d개 이벤트 처리, 조건 맞는 %d개)\n",
-						file.Name, totalEvents, len(events))
+				if parent.Err() != nil {
+					return events, parent.Err()
 				}
+				// 파일 끝에 도달하기 전에 연결이 끊기거나 시간이 초과되면 이후 이벤트가 빠졌음을 알림
 				safeSyncerClose()
-				return events, nil
+				if errors.Is(err, context.DeadlineExceeded) {
+					err = errFileTimeout
+				}
+				return events, &partialReadError{file: file.Name, position: position, err: err}
 			}
 
 			totalEvents++
 
-			// 파일 경계 확인 - 현재 이벤트가 다른 파일로 넘어갔는지 확인
-			if ev.Header.LogPos > 0 {
-				// 파일 크기를 초과했거나 다른 파일로 넘어간 경우 종료
-				// LogPos는 이벤트의 끝 위치이므로 파일 크기보다 클 수 있음
-				// 대신 이벤트 크기를 고려하여 판단
-				if ev.Header.LogPos > uint32(file.Size) && ev.Header.EventSize > 0 {
-					// 이벤트 크기가 파일 크기를 초과하는 경우에만 종료
-					if ev.Header.LogPos-ev.Header.EventSize > uint32(file.Size) {
-						if se.config.Verbose {
-							fmt.Printf("파일 %s 경계 도달, SQL 추출 종료 (LogPos: %d, EventSize: %d, FileSize: %d)\n",
-								file.Name, ev.Header.LogPos, ev.Header.EventSize, file.Size)
-						}
-						safeSyncerClose()
-						return events, nil
-					}
+			// 파일 경계 확인 - 목록을 가져온 뒤 기록된 이벤트(시작 위치가 그때의 파일 크기 이상)는 읽지 않음
+			// --incremental은 이 크기를 다음 실행의 시작 위치로 기록하므로 그 위치의 이벤트를 두 실행에서 읽지 않도록 >=
+			if ev.Header.LogPos > 0 && ev.Header.EventSize > 0 && file.Size > 0 && int64(eventStartPosition(ev.Header)) >= file.Size {
+				if se.config.Verbose >= config.VerboseProbe {
+					fmt.Fprintf(os.Stderr, "파일 %s 경계 도달, SQL 추출 종료 (LogPos: %d, EventSize: %d, FileSize: %d)\n",
+						file.Name, ev.Header.LogPos, ev.Header.EventSize, file.Size)
+				}
+				safeSyncerClose()
+				return events, nil
+			}
+			if ev.Header.LogPos > position {
+				position = ev.Header.LogPos
+			}
+
+			// 다음 파일로 넘어가면 파일 처리 완료 (서버가 보내는 가짜 ROTATE는 현재 파일을 가리킴)
+			if rotate, ok := ev.Event.(*replication.RotateEvent); ok && string(rotate.NextLogName) != file.Name {
+				if se.config.Verbose >= config.VerboseProbe {
+					fmt.Fprintf(os.Stderr, "\n> 파일 %s: 다음 파일(%s)로 넘어감 (총 %d개 이벤트 처리, 조건 맞는 %d개)\n",
+						file.Name, rotate.NextLogName, totalEvents, len(events))
 				}
+				safeSyncerClose()
+				return events, nil
 			}
 
 			// 시간 필터링
-			eventTime := time.Unix(int64(ev.Header.Timestamp), 0)
+			eventTime := se.eventTime(ev)
+			if se.config.Verbose >= config.VerboseEvents {
+				traceEvent(file.Name, ev.Header, eventTime)
+			}
 
 			// 시작 시간 이전이면 스킵
-			if eventTime.Before(se.config.StartTime) {
+			if beforeWindow(eventTime, !se.commitTime.IsZero(), se.config.StartTime) {
 				continue
 			}
-			// 종료 시간 이후면 해당 파일 처리 완료
+			// 긴 트랜잭션은 커밋 순서로 기록되어 타임스탬프가 앞뒤로 섞이므로
+			// 종료 시간 이후 이벤트가 --past-end-events개 연속될 때까지 계속 읽음
 			if eventTime.After(se.config.EndTime) {
-				if se.config.Verbose {
-					fmt.Printf("\n> 파일 %s: 종료 시간 초과 (총 %d개 이벤트 처리, 조건 맞는 %d개)\n",
-						file.Name, totalEvents, len(events))
+				pastEnd++
+				if pastEnd >= max(se.config.PastEndEvents, 1) {
+					if se.config.Verbose >= config.VerboseProbe {
+						fmt.Fprintf(os.Stderr, "\n> 파일 %s: 종료 시간 초과 (총 %d개 이벤트 처리, 조건 맞는 %d개)\n",
+							file.Name, totalEvents, len(events))
+					}
+					safeSyncerClose()
+					return events, nil
 				}
-				safeSyncerClose()
-				return events, nil
+				continue
 			}
+			pastEnd = 0
 
 			// SQL 이벤트로 변환
 			sqlEvent := se.convertToSQLEvent(ev, file.Name)
@@ -208,35 +249,119 @@ func (se *SQLExtractor) ExtractFromSingleFile(file config.BinlogFile) ([]config.
 		}
 	}
 
-	if se.config.Verbose {
-		fmt.Printf("파일 %s: 최대 이벤트 수(%d) 도달 (총 %d개 이벤트 처리, 조건 맞는 %d개)\n",
-			file.Name, maxEvents, totalEvents, len(events))
-	}
+	warnEventLimit(file.Name, maxEvents)
 
 	safeSyncerClose()
 	return events, nil
 }
 
+// 파일을 끝까지 읽지 못하고 멈춤 (그때까지 읽은 이벤트는 결과에 포함하고 경고)
+type partialReadError struct {
+	file     string
+	position uint32 // 마지막으로 읽은 이벤트의 끝 위치
+	err      error
+}
+
+func (e *partialReadError) Error() string {
+	return fmt.Sprintf("파일 %s를 %d 위치까지만 읽음: %v", e.file, e.position, e.err)
+}
+
+func (e *partialReadError) Unwrap() error {
+	return e.err
+}
+
+// 파일 하나의 처리 시간 제한 (60초)을 넘음
+var errFileTimeout = errors.New("처리 시간 초과 (60초)")
+
+// 목록을 가져올 때의 파일 크기(SHOW BINARY LOGS)까지 읽었는지 (크기를 모르면 false)
+func reachedEnd(file config.BinlogFile, position uint32) bool {
+	return file.Size > 0 && int64(position) >= file.Size
+}
+
+// 파일의 구간 안 이벤트가 --max-events-per-file을 넘어 나머지를 읽지 않았음을 경고
+func warnEventLimit(filename string, limit int) {
+	logrus.Warnf("파일 %s: 구간 안 이벤트가 --max-events-per-file %d개에 도달하여 이후 이벤트는 결과에서 빠짐", filename, limit)
+}
+
+// 읽은 이벤트 한 줄 추적 (-vvv)
+func traceEvent(filename string, header *replication.EventHeader, eventTime time.Time) {
+	logrus.Debugf("  %s:%d %s %s (%d bytes)", filename, eventStartPosition(header), header.EventType,
+		eventTime.UTC().Format("2006-01-02 15:04:05.999999"), header.EventSize)
+}
+
 // BinlogEvent를 SQLEvent로 변환
 func (se *SQLExtractor) convertToSQLEvent(ev *replication.BinlogEvent, filename string) *config.SQLEvent {
-	timestamp := time.Unix(int64(ev.Header.Timestamp), 0)
+	timestamp := se.eventTime(ev)
 
 	switch e := ev.Event.(type) {
+	case *replication.RowsQueryEvent:
+		// 이후 row 이벤트들의 원본 SQL로 사용
+		se.rowsQuery = string(e.Query)
+		return nil
+
+	case *replication.GTIDEvent:
+		se.startGTIDTransaction(ev.Header, e, filename)
+		return nil
+
+	case *replication.GtidTaggedLogEvent:
+		se.startGTIDTransaction(ev.Header, &e.GTIDEvent, filename)
+		return nil
+
+	case *replication.MariadbGTIDEvent:
+		se.startTransaction(e.GTID.String())
+		se.gtids.add(e)
+		se.lags.add(e, se.transaction)
+		return nil
+
+	case *replication.XIDEvent:
+		// 트랜잭션 종료 시 원본 SQL 초기화
+		se.rowsQuery = ""
+		se.endTransaction()
+		return nil
+
 	case *replication.QueryEvent:
+		se.rowsQuery = ""
 		query := string(e.Query)
+		switch strings.ToUpper(strings.TrimSpace(query)) {
+		case "BEGIN":
+			// GTID 이벤트가 없는 서버에서는 BEGIN 위치로 트랜잭션 구분
+			if se.transaction == "" {
+				se.transaction = transactionPosition(ev.Header, filename)
+			}
+			se.transactionBegun = true
+			se.execTime = e.ExecutionTime
+			return nil
+		case "COMMIT", "ROLLBACK":
+			se.endTransaction()
+			return nil
+		}
+
+		transaction := se.currentTransaction(ev.Header, filename)
+		if !se.transactionBegun {
+			// DDL은 BEGIN 없이 쿼리 하나가 트랜잭션
+			se.endTransaction()
+		}
+
 		// 시스템 쿼리나 의미없는 쿼리 필터링
 		if se.skipQuery(query) {
 			return nil
 		}
 
 		return &config.SQLEvent{
-			Timestamp: timestamp,
-			EventType: "QUERY",
-			Database:  string(e.Schema),
-			SQL:       query,
-			ServerId:  ev.Header.ServerID,
-			Position:  ev.Header.LogPos,
-			Filename:  filename,
+			Timestamp:   timestamp,
+			EventType:   "QUERY",
+			Database:    string(e.Schema),
+			SQL:         query,
+			ServerId:    ev.Header.ServerID,
+			Position:    ev.Header.LogPos,
+			Filename:    filename,
+			EventSize:   ev.Header.EventSize,
+			ExecTime:    e.ExecutionTime,
+			ErrorCode:   e.ErrorCode,
+			Session:     parseStatusVars(e.StatusVars),
+			Transaction: transaction,
+
+			StartPosition: eventStartPosition(ev.Header),
 		}
 
 	case *replication.RowsEvent:
@@ -249,34 +374,173 @@ func (se *SQLExtractor) convertToSQLEvent(ev *replication.BinlogEvent, filename
 	}
 }
 
+// GTID 이벤트로 새 트랜잭션 시작
+func (se *SQLExtractor) startTransaction(id string) {
+	se.transaction = id
+	se.transactionBegun = false
+}
+
+func (se *SQLExtractor) startGTIDTransaction(header *replication.EventHeader, e *replication.GTIDEvent, filename string) {
+	se.startTransaction(gtidTransactionID(header, e, filename))
+	if header.EventType != replication.ANONYMOUS_GTID_EVENT {
+		se.gtids.add(e)
+	}
+	se.lags.add(e, se.transaction)
+}
+
+func (se *SQLExtractor) endTransaction() {
+	se.transaction = ""
+	se.transactionBegun = false
+	se.commitTime = time.Time{}
+	se.execTime = 0
+}
+
+// 이벤트 시각 (GTID 이벤트에 커밋 시각이 있으면 트랜잭션의 모든 이벤트에 사용, 없으면 헤더의 초 단위 시각)
+func (se *SQLExtractor) eventTime(ev *replication.BinlogEvent) time.Time {
+	if e, ok := ev.Event.(mysql.BinlogGTIDEvent); ok {
+		se.commitTime = gtidCommitTime(e)
+	}
+	if !se.commitTime.IsZero() {
+		return se.commitTime
+	}
+	return time.Unix(int64(ev.Header.Timestamp), 0)
+}
+
+// 파일을 읽기 시작할 위치 (--incremental로 이어 읽는 파일은 기록한 위치, 트랜잭션 경계이므로 table map이 빠지지 않음)
+func (se *SQLExtractor) readFrom(filename string) uint32 {
+	if se.resume != nil && se.resume.File == filename && se.resume.Position > 4 {
+		return se.resume.Position
+	}
+	return 4
+}
+
+// 시작 시간 이전 이벤트인지
+// 커밋 타임스탬프가 없는 이벤트는 초 단위로 잘린 시각이므로 그 1초의 일부라도 구간에 걸치면 포함
+func beforeWindow(eventTime time.Time, precise bool, start time.Time) bool {
+	if !precise {
+		return !eventTime.Add(time.Second).After(start)
+	}
+	return eventTime.Before(start)
+}
+
+// GTID 이벤트의 immediate_commit_timestamp (MySQL 8.0.1 미만이나 MariaDB면 zero)
+func gtidCommitTime(e mysql.BinlogGTIDEvent) time.Time {
+	switch e := e.(type) {
+	case *replication.GTIDEvent:
+		return e.ImmediateCommitTime()
+	case *replication.GtidTaggedLogEvent:
+		return e.ImmediateCommitTime()
+	default:
+		return time.Time{}
+	}
+}
+
+// 이벤트가 속한 트랜잭션 (알 수 없으면 이벤트 자신의 시작 위치)
+func (se *SQLExtractor) currentTransaction(header *replication.EventHeader, filename string) string {
+	if se.transaction != "" {
+		return se.transaction
+	}
+	return transactionPosition(header, filename)
+}
+
+// 트랜잭션 식별자 (GTID, 익명 GTID면 GTID 이벤트의 위치)
+func gtidTransactionID(header *replication.EventHeader, e mysql.BinlogGTIDEvent, filename string) string {
+	if header.EventType != replication.ANONYMOUS_GTID_EVENT {
+		if gtid, err := e.GTIDNext(); err == nil {
+			return gtid.String()
+		}
+	}
+	return transactionPosition(header, filename)
+}
+
+// 이벤트 시작 위치 (filename:position)
+func transactionPosition(header *replication.EventHeader, filename string) string {
+	return fmt.Sprintf("%s:%d", filename, eventStartPosition(header))
+}
+
+// 이벤트 시작 위치 (이전 이벤트의 end_log_pos)
+func eventStartPosition(header *replication.EventHeader) uint32 {
+	return header.LogPos - header.EventSize
+}
+
 // Row 이벤트를 SQLEvent로 변환
 func (se *SQLExtractor) handleRowsEvent(ev *replication.BinlogEvent, rowsEvent *replication.RowsEvent, timestamp time.Time, filename string) *config.SQLEvent {
 	var eventType string
-	var sql string
 
 	switch ev.Header.EventType {
 	case replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
 		eventType = "INSERT"
-		sql = se.formatInsertEvent(rowsEvent)
 	case replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
 		eventType = "UPDATE"
-		sql = se.formatUpdateEvent(rowsEvent)
+	case replication.PARTIAL_UPDATE_ROWS_EVENT:
+		// JSON 컬럼은 문서 전체 대신 바뀐 경로만 기록됨
+		eventType = "UPDATE"
+		decodePartialJSON(ev.RawData, rowsEvent.Rows)
 	case replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
 		eventType = "DELETE"
-		sql = se.formatDeleteEvent(rowsEvent)
 	default:
 		return nil
 	}
+	applyTableMetadata(rowsEvent)
+
+	event := &config.SQLEvent{
+		Timestamp:   timestamp,
+		EventType:   eventType,
+		Database:    string(rowsEvent.Table.Schema),
+		Table:       string(rowsEvent.Table.Table),
+		ServerId:    ev.Header.ServerID,
+		Position:    ev.Header.LogPos,
+		Filename:    filename,
+		OriginalSQL: se.rowsQuery,
+		EventSize:   ev.Header.EventSize,
+		ExecTime:    se.execTime,
+		RowCount:    len(rowsEvent.Rows),
+		Rows:        rowsEvent.Rows,
+		Transaction: se.currentTransaction(ev.Header, filename),
+
+		StartPosition: eventStartPosition(ev.Header),
+	}
+	if eventType == "UPDATE" {
+		event.RowCount = len(rowsEvent.Rows) / 2 // before/after 쌍
+	}
+	if names := tableMapColumnNames(rowsEvent.Table); names != nil {
+		event.Columns = names
+		event.ColumnsFromBinlog = true
+	} else {
+		event.Columns = se.columnNames(rowsEvent)
+	}
+	for _, skipped := range rowsEvent.SkippedColumns {
+		if len(skipped) > 0 {
+			// go-mysql은 빠진 컬럼을 nil로 채우므로 NULL과 구분하도록 위치를 함께 보관
+			event.SkippedColumns = rowsEvent.SkippedColumns
+			break
+		}
+	}
+	event.SQL = se.formatRowsSQL(event)
+
+	return event
+}
+
+// row 이벤트의 pseudo-SQL 생성 (Columns가 바뀌면 다시 호출하여 갱신)
+func (se *SQLExtractor) formatRowsSQL(event *config.SQLEvent) string {
+	switch event.EventType {
+	case "INSERT":
+		return se.formatInsertEvent(event)
+	case "UPDATE":
+		return se.formatUpdateEvent(event)
+	case "DELETE":
+		return se.formatDeleteEvent(event)
+	default:
+		return event.SQL
+	}
+}
 
-	return &config.SQLEvent{
-		Timestamp: timestamp,
-		EventType: eventType,
-		Database:  string(rowsEvent.Table.Schema),
-		SQL:       sql,
-		ServerId:  ev.Header.ServerID,
-		Position:  ev.Header.LogPos,
-		Filename:  filename,
+// 스키마를 포함한 테이블 이름
+func qualifiedTableName(event *config.SQLEvent) string {
+	if event.Database != "" {
+		return fmt.Sprintf("%s.%s", event.Database, event.Table)
 	}
+	return event.Table
 }
 
 // 스킵해야 할 쿼리인지 확인
@@ -310,23 +574,32 @@ func (se *SQLExtractor) skipQuery(query string) bool {
 }
 
 // INSERT 이벤트를 SQL로 포맷
-func (se *SQLExtractor) formatInsertEvent(rowsEvent *replication.RowsEvent) string {
-	tableName := string(rowsEvent.Table.Table)
-	schema := string(rowsEvent.Table.Schema)
-	rowCount := len(rowsEvent.Rows)
-
-	if schema != "" {
-		tableName = fmt.Sprintf("%s.%s", schema, tableName)
+func (se *SQLExtractor) formatInsertEvent(event *config.SQLEvent) string {
+	tableName := qualifiedTableName(event)
+	rowCount := len(event.Rows)
+
+	// 컬럼 이름을 알고 있으면 컬럼 목록 추가 (기록되지 않은 컬럼이 있으면 기록된 컬럼만)
+	labels := se.columnLabels(event)
+	if rowCount > 0 && imageMinimal(event, 0) {
+		var names []string
+		for _, i := range loggedColumns(event, 0) {
+			names = append(names, columnName(labels, i))
+		}
+		tableName = fmt.Sprintf("%s (%s)", tableName, strings.Join(names, ", "))
+	} else if labels != nil {
+		tableName = fmt.Sprintf("%s (%s)", tableName, strings.Join(labels, ", "))
 	}
 
 	// 첫 번째 행의 값들을 보여주기
 	var valueStr string
-	if rowCount > 0 && len(rowsEvent.Rows[0]) > 0 {
-		values := make([]string, len(rowsEvent.Rows[0]))
-		for i, val := range rowsEvent.Rows[0] {
-			values[i] = se.formatValue(val)
+	if rowCount > 0 && len(event.Rows[0]) > 0 {
+		values := make([]string, 0, len(event.Rows[0]))
+		for i, val := range event.Rows[0] {
+			if columnLogged(event, 0, i) {
+				values = append(values, se.formatValue(val))
+			}
 		}
-		valueStr = fmt.Sprintf("(%s)", strings.Join(values, ", "))
+		valueStr = fmt.Sprintf("(%s)", strings.Join(values, ", ")) + notLoggedComment(event, 0)
 
 		if rowCount > 1 {
 			valueStr += fmt.Sprintf(" /* and %d more rows */", rowCount-1)
@@ -339,27 +612,30 @@ func (se *SQLExtractor) formatInsertEvent(rowsEvent *replication.RowsEvent) stri
 }
 
 // UPDATE 이벤트를 SQL로 포맷
-func (se *SQLExtractor) formatUpdateEvent(rowsEvent *replication.RowsEvent) string {
-	tableName := string(rowsEvent.Table.Table)
-	schema := string(rowsEvent.Table.Schema)
-	rowCount := len(rowsEvent.Rows) / 2 // UPDATE는 before/after 쌍
-
-	if schema != "" {
-		tableName = fmt.Sprintf("%s.%s", schema, tableName)
-	}
+func (se *SQLExtractor) formatUpdateEvent(event *config.SQLEvent) string {
+	tableName := qualifiedTableName(event)
+	rowCount := len(event.Rows) / 2 // UPDATE는 before/after 쌍
 
 	// 첫 번째 업데이트의 before/after 값 보여주기
 	var updateInfo string
-	if rowCount > 0 && len(rowsEvent.Rows) >= 2 {
-		beforeRow := rowsEvent.Rows[0]
-		afterRow := rowsEvent.Rows[1]
+	if rowCount > 0 && len(event.Rows) >= 2 {
+		beforeRow := event.Rows[0]
+		afterRow := event.Rows[1]
+		labels := se.columnLabels(event)
 
-		// 변경된 컬럼들만 찾기
+		// 변경된 컬럼들만 찾기 (after 이미지에 없는 컬럼은 바뀌지 않음, before 이미지에 없으면 이전 값을 모름)
 		var changes []string
 		for i := 0; i < len(beforeRow) && i < len(afterRow); i++ {
-			if !se.valuesEqual(beforeRow[i], afterRow[i]) {
-				changes = append(changes, fmt.Sprintf("col_%d=%s (was %s)",
-					i+1, se.formatValue(afterRow[i]), se.formatValue(beforeRow[i])))
+			if !columnLogged(event, 1, i) {
+				continue
+			}
+			if update, ok := afterRow[i].(jsonPartialUpdate); ok {
+				changes = append(changes, fmt.Sprintf("%s=%s", columnName(labels, i), update.expression(columnName(event.Columns, i), se.formatValue)))
+			} else if !columnLogged(event, 0, i) {
+				changes = append(changes, fmt.Sprintf("%s=%s", columnName(labels, i), se.formatValue(afterRow[i])))
+			} else if !se.valuesEqual(beforeRow[i], afterRow[i]) {
+				changes = append(changes, fmt.Sprintf("%s=%s (was %s)",
+					columnName(labels, i), se.formatValue(afterRow[i]), se.formatValue(beforeRow[i])))
 			}
 		}
 
@@ -368,6 +644,7 @@ func (se *SQLExtractor) formatUpdateEvent(rowsEvent *replication.RowsEvent) stri
 		} else {
 			updateInfo = "/* no visible changes */"
 		}
+		updateInfo += notLoggedComment(event, 0)
 
 		if rowCount > 1 {
 			updateInfo += fmt.Sprintf(" /* and %d more rows */", rowCount-1)
@@ -380,22 +657,18 @@ func (se *SQLExtractor) formatUpdateEvent(rowsEvent *replication.RowsEvent) stri
 }
 
 // DELETE 이벤트를 SQL로 포맷
-func (se *SQLExtractor) formatDeleteEvent(rowsEvent *replication.RowsEvent) string {
-	tableName := string(rowsEvent.Table.Table)
-	schema := string(rowsEvent.Table.Schema)
-	rowCount := len(rowsEvent.Rows)
-
-	if schema != "" {
-		tableName = fmt.Sprintf("%s.%s", schema, tableName)
-	}
+func (se *SQLExtractor) formatDeleteEvent(event *config.SQLEvent) string {
+	tableName := qualifiedTableName(event)
+	rowCount := len(event.Rows)
 
 	// 첫 번째 삭제된 행의 값들 보여주기
 	var whereClause string
-	if rowCount > 0 && len(rowsEvent.Rows[0]) > 0 {
-		conditions := make([]string, 0, len(rowsEvent.Rows[0]))
-		for i, val := range rowsEvent.Rows[0] {
-			if val != nil { // NULL이 아닌 값들만 WHERE 조건으로 사용
-				conditions = append(conditions, fmt.Sprintf("col_%d=%s", i+1, se.formatValue(val)))
+	if rowCount > 0 && len(event.Rows[0]) > 0 {
+		labels := se.columnLabels(event)
+		conditions := make([]string, 0, len(event.Rows[0]))
+		for i, val := range event.Rows[0] {
+			if val != nil && columnLogged(event, 0, i) { // 기록된, NULL이 아닌 값들만 WHERE 조건으로 사용
+				conditions = append(conditions, fmt.Sprintf("%s=%s", columnName(labels, i), se.formatValue(val)))
 			}
 		}
 
@@ -407,6 +680,7 @@ func (se *SQLExtractor) formatDeleteEvent(rowsEvent *replication.RowsEvent) stri
 		} else {
 			whereClause = "/* all columns NULL */"
 		}
+		whereClause += notLoggedComment(event, 0)
 
 		if rowCount > 1 {
 			whereClause += fmt.Sprintf(" /* and %d more rows */", rowCount-1)
@@ -417,3 +691,39 @@ func (se *SQLExtractor) formatDeleteEvent(rowsEvent *replication.RowsEvent) stri
 
 	return fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, whereClause)
 }
+
+// 스키마 스냅샷에서 컬럼 이름 조회 (컬럼 수가 다르면 스키마가 바뀐 것이므로 사용하지 않음)
+func (se *SQLExtractor) columnNames(rowsEvent *replication.RowsEvent) []string {
+	ts := se.schema.Table(string(rowsEvent.Table.Schema), string(rowsEvent.Table.Table))
+	if ts == nil || len(ts.Columns) != int(rowsEvent.ColumnCount) {
+		return nil
+	}
+	return ts.ColumnNames()
+}
+
+// pseudo-SQL에 쓰는 컬럼 이름 (생성 컬럼과 INVISIBLE 컬럼은 주석으로 표시, 이름을 모르면 nil)
+func (se *SQLExtractor) columnLabels(event *config.SQLEvent) []string {
+	columns := se.snapshotColumns(event)
+	if columns == nil {
+		return event.Columns
+	}
+
+	labels := slices.Clone(event.Columns)
+	for i, col := range columns {
+		if col.Generated {
+			labels[i] += " /* generated */"
+		}
+		if col.Invisible {
+			labels[i] += " /* invisible */"
+		}
+	}
+	return labels
+}
+
+// 컬럼 이름 반환 (이름을 모르면 col_N)
+func columnName(names []string, i int) string {
+	if i < len(names) {
+		return names[i]
+	}
+	return fmt.Sprintf("col_%d", i+1)
+}
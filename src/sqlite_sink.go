@@ -0,0 +1,98 @@
+package src
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// SQLite 결과 파일 스키마 (--output-sqlite)
+// 시각은 고정 폭 UTC 문자열이라 문자열 비교로 구간을 고를 수 있고 SQLite 날짜 함수로도 읽을 수 있음
+const sqliteEventsSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_time     TEXT NOT NULL,
+	event_type     TEXT NOT NULL,
+	database_name  TEXT NOT NULL,
+	table_name     TEXT,
+	sql            TEXT NOT NULL,
+	original_sql   TEXT,
+	server_id      INTEGER NOT NULL,
+	filename       TEXT NOT NULL,
+	start_position INTEGER NOT NULL,
+	position       INTEGER NOT NULL,
+	event_size     INTEGER NOT NULL,
+	exec_time      INTEGER NOT NULL,
+	error_code     INTEGER NOT NULL,
+	row_count      INTEGER NOT NULL,
+	transaction_id TEXT,
+	captured_at    TEXT,
+	UNIQUE (filename, position)
+);
+CREATE INDEX IF NOT EXISTS events_time ON events (event_time);
+CREATE INDEX IF NOT EXISTS events_table ON events (database_name, table_name, event_time);
+CREATE INDEX IF NOT EXISTS events_type ON events (event_type, event_time);
+CREATE INDEX IF NOT EXISTS events_transaction ON events (transaction_id);`
+
+// SQLite 결과 파일 싱크 (--output-sqlite, --sink sqlite)
+// 같은 파일에 다시 저장하면 이미 있는 이벤트(파일/위치)는 건너뛰고 새 이벤트만 추가
+type sqliteSink struct {
+	db *sql.DB
+}
+
+func newSQLiteSink(ctx context.Context, cfg config.Config) (*sqliteSink, error) {
+	// 여러 클러스터 분석(--clusters)이 같은 파일에 저장할 수 있으므로 잠금은 잠시 기다림
+	db, err := sql.Open("sqlite", "file:"+cfg.SQLiteFile+"?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, sqliteEventsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("SQLite 파일 %s 초기화 실패: %v", cfg.SQLiteFile, err)
+	}
+	return &sqliteSink{db: db}, nil
+}
+
+// 한 트랜잭션으로 저장 (이미 저장된 이벤트는 건너뜀)
+func (s *sqliteSink) Write(ctx context.Context, events []config.SQLEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO events (event_time, event_type, database_name, table_name, sql, original_sql,
+		server_id, filename, start_position, position, event_size, exec_time, error_code, row_count, transaction_id, captured_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range events {
+		event := &events[i]
+		var capturedAt sql.NullString
+		if event.CapturedAt != nil {
+			capturedAt = sql.NullString{String: formatSQLiteTime(*event.CapturedAt), Valid: true}
+		}
+		if _, err := stmt.ExecContext(ctx,
+			formatSQLiteTime(event.Timestamp), event.EventType, event.Database, nullString(event.Table), event.SQL, nullString(event.OriginalSQL),
+			int64(event.ServerId), event.Filename, int64(event.StartPosition), int64(event.Position), int64(event.EventSize),
+			int64(event.ExecTime), int64(event.ErrorCode), event.RowCount, nullString(event.Transaction), capturedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteSink) Close() error {
+	return s.db.Close()
+}
+
+// 마이크로초까지 고정 폭 UTC (2024-01-15T10:12:03.123456Z)
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000Z")
+}
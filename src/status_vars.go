@@ -0,0 +1,193 @@
+package src
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// Query 이벤트 status vars 코드 (MySQL log_event.h, MariaDB 확장 포함)
+const (
+	qFlags2Code                   = 0
+	qSQLModeCode                  = 1
+	qCatalogCode                  = 2
+	qAutoIncrement                = 3
+	qCharsetCode                  = 4
+	qTimeZoneCode                 = 5
+	qCatalogNZCode                = 6
+	qLCTimeNamesCode              = 7
+	qCharsetDatabaseCode          = 8
+	qTableMapForUpdateCode        = 9
+	qMasterDataWrittenCode        = 10
+	qInvoker                      = 11
+	qUpdatedDBNames               = 12
+	qMicroseconds                 = 13
+	qCommitTS                     = 14
+	qCommitTS2                    = 15
+	qExplicitDefaultsForTimestamp = 16
+	qDDLLoggedWithXID             = 17
+	qDefaultCollationForUTF8MB4   = 18
+	qSQLRequirePrimaryKey         = 19
+	qDefaultTableEncryption       = 20
+	qMariadbHRNow                 = 128
+	qMariadbXID                   = 129
+)
+
+// 데이터베이스 이름 없이 기록된 Q_UPDATED_DB_NAMES (OVER_MAX_DBS_IN_EVENT_MTS)
+const overMaxDBsInEventMTS = 254
+
+// 고정 길이 status var의 크기
+var statusVarSizes = map[byte]int{
+	qFlags2Code:                   4,
+	qSQLModeCode:                  8,
+	qAutoIncrement:                4,
+	qCharsetCode:                  6,
+	qLCTimeNamesCode:              2,
+	qCharsetDatabaseCode:          2,
+	qTableMapForUpdateCode:        8,
+	qMasterDataWrittenCode:        4,
+	qMicroseconds:                 3,
+	qCommitTS:                     8,
+	qCommitTS2:                    8,
+	qExplicitDefaultsForTimestamp: 1,
+	qDDLLoggedWithXID:             8,
+	qDefaultCollationForUTF8MB4:   2,
+	qSQLRequirePrimaryKey:         1,
+	qDefaultTableEncryption:       1,
+	qMariadbHRNow:                 3,
+	qMariadbXID:                   8,
+}
+
+// Query 이벤트의 status vars에서 세션 상태 추출 (알 수 없는 코드를 만나면 그 앞까지만 사용)
+func parseStatusVars(data []byte) *config.SessionContext {
+	session := &config.SessionContext{}
+	found := false
+
+	for pos := 0; pos < len(data); {
+		code := data[pos]
+		pos++
+
+		if size, ok := statusVarSizes[code]; ok {
+			if pos+size > len(data) {
+				break
+			}
+			value := data[pos : pos+size]
+			switch code {
+			case qSQLModeCode:
+				sqlMode := binary.LittleEndian.Uint64(value)
+				session.SQLMode = &sqlMode
+				found = true
+			case qCharsetCode:
+				session.CharacterSetClient = binary.LittleEndian.Uint16(value)
+				session.CollationConnection = binary.LittleEndian.Uint16(value[2:])
+				session.CollationServer = binary.LittleEndian.Uint16(value[4:])
+				found = true
+			case qCharsetDatabaseCode:
+				session.CollationDatabase = binary.LittleEndian.Uint16(value)
+				found = true
+			}
+			pos += size
+			continue
+		}
+
+		// 가변 길이 status var
+		switch code {
+		case qCatalogCode, qCatalogNZCode, qTimeZoneCode:
+			if pos >= len(data) {
+				return statusVarsResult(session, found)
+			}
+			length := int(data[pos])
+			pos++
+			if pos+length > len(data) {
+				return statusVarsResult(session, found)
+			}
+			value := string(data[pos : pos+length])
+			pos += length
+			if code == qCatalogCode {
+				pos++ // 5.0 이전 형식은 끝에 NUL
+			}
+			if code == qTimeZoneCode {
+				session.TimeZone = value
+			} else {
+				session.Catalog = value
+			}
+			found = true
+		case qInvoker:
+			// user, host (정의자 권한으로 실행된 문장의 CURRENT_USER)
+			var parts [2]string
+			for i := range parts {
+				if pos >= len(data) {
+					return statusVarsResult(session, found)
+				}
+				length := int(data[pos])
+				pos++
+				if pos+length > len(data) {
+					return statusVarsResult(session, found)
+				}
+				parts[i] = string(data[pos : pos+length])
+				pos += length
+			}
+			session.InvokerUser, session.InvokerHost = parts[0], parts[1]
+			found = true
+		case qUpdatedDBNames:
+			if pos >= len(data) {
+				return statusVarsResult(session, found)
+			}
+			count := int(data[pos])
+			pos++
+			if count == overMaxDBsInEventMTS {
+				continue
+			}
+			for range count {
+				end := pos
+				for end < len(data) && data[end] != 0 {
+					end++
+				}
+				pos = end + 1
+			}
+		default:
+			return statusVarsResult(session, found)
+		}
+	}
+	return statusVarsResult(session, found)
+}
+
+func statusVarsResult(session *config.SessionContext, found bool) *config.SessionContext {
+	if !found {
+		return nil
+	}
+	return session
+}
+
+// 바뀐 세션 변수의 SET 구문 (mysqlbinlog와 같은 숫자 형식, 이번 이벤트에 없는 변수는 이전 값 유지)
+func (s *replayState) sessionStatements(session *config.SessionContext) []string {
+	if session == nil {
+		return nil
+	}
+
+	current := &s.session
+	var statements []string
+	if session.SQLMode != nil && (current.SQLMode == nil || *current.SQLMode != *session.SQLMode) {
+		statements = append(statements, fmt.Sprintf("SET @@session.sql_mode=%d", *session.SQLMode))
+		current.SQLMode = session.SQLMode
+	}
+	if session.CharacterSetClient != 0 && (session.CharacterSetClient != current.CharacterSetClient ||
+		session.CollationConnection != current.CollationConnection || session.CollationServer != current.CollationServer) {
+		statements = append(statements, fmt.Sprintf("SET @@session.character_set_client=%d,@@session.collation_connection=%d,@@session.collation_server=%d",
+			session.CharacterSetClient, session.CollationConnection, session.CollationServer))
+		current.CharacterSetClient = session.CharacterSetClient
+		current.CollationConnection = session.CollationConnection
+		current.CollationServer = session.CollationServer
+	}
+	if session.CollationDatabase != 0 && session.CollationDatabase != current.CollationDatabase {
+		statements = append(statements, fmt.Sprintf("SET @@session.collation_database=%d", session.CollationDatabase))
+		current.CollationDatabase = session.CollationDatabase
+	}
+	if session.TimeZone != "" && session.TimeZone != current.TimeZone {
+		statements = append(statements, fmt.Sprintf("SET @@session.time_zone='%s'", strings.ReplaceAll(session.TimeZone, "'", "''")))
+		current.TimeZone = session.TimeZone
+	}
+	return statements
+}
@@ -0,0 +1,226 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 실시간 스트림 설정
+const (
+	streamBufferSize   = 256              // 클라이언트별 대기 이벤트 수 (넘치면 연결 종료)
+	streamWriteTimeout = 10 * time.Second // 메시지 하나 전송 제한 시간
+)
+
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 16 * 1024,
+}
+
+// 접속 대상 하나의 실시간 이벤트 피드
+// 첫 구독자가 붙을 때 binary log 스트림을 열고, 마지막 구독자가 떠나면 닫음
+type liveFeed struct {
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	subs map[*streamSubscriber]struct{}
+}
+
+// /stream 클라이언트 하나
+type streamSubscriber struct {
+	filter *EventFilter
+	events chan *config.SQLEvent
+
+	once sync.Once
+	done chan struct{}
+	err  error // 피드 쪽에서 연결을 끊은 이유
+}
+
+func newStreamSubscriber(filter *EventFilter) *streamSubscriber {
+	return &streamSubscriber{
+		filter: filter,
+		events: make(chan *config.SQLEvent, streamBufferSize),
+		done:   make(chan struct{}),
+	}
+}
+
+func (sub *streamSubscriber) close(err error) {
+	sub.once.Do(func() {
+		sub.err = err
+		close(sub.done)
+	})
+}
+
+// 필터에 맞는 이벤트 전달 (버퍼가 가득 찬 느린 클라이언트는 연결 종료)
+func (sub *streamSubscriber) send(ev *config.SQLEvent) {
+	if !sub.filter.Match(ev) {
+		return
+	}
+	select {
+	case sub.events <- ev:
+	default:
+		sub.close(fmt.Errorf("클라이언트가 이벤트를 따라오지 못해 연결을 종료합니다"))
+	}
+}
+
+// 피드에 구독자 추가 (해당 대상의 피드가 없으면 시작)
+func (s *Server) subscribe(name string, cfg config.Config, sub *streamSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed, ok := s.feeds[name]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		feed = &liveFeed{cancel: cancel, subs: make(map[*streamSubscriber]struct{})}
+		s.feeds[name] = feed
+		go s.runFeed(ctx, name, cfg, feed)
+	}
+
+	feed.mu.Lock()
+	feed.subs[sub] = struct{}{}
+	feed.mu.Unlock()
+}
+
+// 피드에서 구독자 제거 (마지막 구독자면 피드 종료)
+func (s *Server) unsubscribe(name string, sub *streamSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed, ok := s.feeds[name]
+	if !ok {
+		return
+	}
+
+	feed.mu.Lock()
+	delete(feed.subs, sub)
+	empty := len(feed.subs) == 0
+	feed.mu.Unlock()
+
+	if empty {
+		feed.cancel()
+		delete(s.feeds, name)
+	}
+}
+
+// 현재 binary log 위치부터 이벤트를 읽어 구독자에게 전달
+func (s *Server) runFeed(ctx context.Context, name string, cfg config.Config, feed *liveFeed) {
+	err := s.followFeed(ctx, cfg, feed)
+	if err == nil {
+		err = fmt.Errorf("실시간 스트림이 종료되었습니다")
+	} else {
+		logrus.Warnf("실시간 스트림 오류 (대상: %q): %v", name, err)
+	}
+
+	// 새 구독자는 새 피드에 붙도록 먼저 제거
+	s.mu.Lock()
+	if s.feeds[name] == feed {
+		delete(s.feeds, name)
+	}
+	s.mu.Unlock()
+
+	feed.mu.Lock()
+	for sub := range feed.subs {
+		sub.close(err)
+	}
+	feed.mu.Unlock()
+}
+
+func (s *Server) followFeed(ctx context.Context, cfg config.Config, feed *liveFeed) error {
+	ba := &BinlogAnalyzer{Config: cfg}
+	pos, err := ba.prepareFollow()
+	if err != nil {
+		return err
+	}
+	defer ba.conn.Close()
+
+	logrus.Infof("실시간 스트림 시작: %s:%d", pos.Name, pos.Pos)
+	return ba.streamEvents(ctx, pos, func(ev *config.SQLEvent) error {
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		for sub := range feed.subs {
+			sub.send(ev)
+		}
+		return nil
+	}, nil)
+}
+
+// GET /stream: 새로 기록되는 SQL 이벤트를 WebSocket으로 전송
+// ?target=, ?exclude_table_regex=, ?where= (여러 번 지정 가능)으로 대상과 필터 지정
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	name := strings.ToLower(query.Get("target"))
+
+	cfg, err := s.targetConfig(name)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errUnknownTarget) {
+			status = http.StatusNotFound
+		}
+		writeJSONError(w, status, err)
+		return
+	}
+
+	cfg.ExcludeTableRegex = query.Get("exclude_table_regex")
+	cfg.Where = query["where"]
+	cfg.FilterExpr = query.Get("filter_expr")
+	filter, err := NewEventFilter(cfg)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade가 오류 응답을 이미 보냄
+		return
+	}
+	defer conn.Close()
+
+	sub := newStreamSubscriber(filter)
+	s.subscribe(name, cfg, sub)
+	defer s.unsubscribe(name, sub)
+
+	// 클라이언트가 보내는 메시지는 무시하고, 연결이 끊기면 종료
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				sub.close(nil)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case ev := <-sub.events:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(ev); err != nil {
+				return
+			}
+		case <-sub.done:
+			if sub.err != nil {
+				conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+				conn.WriteJSON(map[string]string{"error": sub.err.Error()})
+			}
+			closeStream(conn)
+			return
+		case <-r.Context().Done():
+			closeStream(conn)
+			return
+		}
+	}
+}
+
+func closeStream(conn *websocket.Conn) {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+}
@@ -0,0 +1,113 @@
+package src
+
+import (
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// binary collation (문자열이 아닌 바이트열)
+const binaryCollation = 63
+
+// table map 선택 메타데이터의 컬럼 이름 (binlog_row_metadata=FULL, 없으면 nil)
+// 이벤트 시점의 구성이므로 이후에 삭제되거나 바뀐 테이블도 정확함
+func tableMapColumnNames(table *replication.TableMapEvent) []string {
+	names := table.ColumnNameString()
+	if len(names) != int(table.ColumnCount) {
+		return nil
+	}
+	return names
+}
+
+// table map 메타데이터로 row 값 보정 (기록되지 않은 메타데이터는 건너뜀)
+// ENUM/SET은 번호 대신 이름, UNSIGNED 정수는 부호 없는 값, 문자열은 collation에 따라 문자열/바이트열
+func applyTableMetadata(rowsEvent *replication.RowsEvent) {
+	table := rowsEvent.Table
+	unsigned := table.UnsignedMap()
+	enums := table.EnumStrValueMap()
+	sets := table.SetStrValueMap()
+	collations := table.CollationMap()
+	if len(unsigned) == 0 && len(enums) == 0 && len(sets) == 0 && len(collations) == 0 {
+		return
+	}
+
+	for _, row := range rowsEvent.Rows {
+		for i, value := range row {
+			if value == nil || i >= len(table.ColumnType) {
+				continue
+			}
+			switch {
+			case unsigned[i]:
+				row[i] = unsignedValue(table.ColumnType[i], value)
+			case enums[i] != nil:
+				if index, ok := value.(int64); ok {
+					row[i] = enumValue(enums[i], index)
+				}
+			case sets[i] != nil:
+				if bits, ok := value.(int64); ok {
+					row[i] = setValue(sets[i], bits)
+				}
+			default:
+				if collation, ok := collations[i]; ok {
+					row[i] = collatedValue(collation, value)
+				}
+			}
+		}
+	}
+}
+
+// go-mysql은 정수를 부호 있는 값으로 읽으므로 UNSIGNED 컬럼은 같은 비트의 부호 없는 값으로 변환
+func unsignedValue(columnType byte, value interface{}) interface{} {
+	switch v := value.(type) {
+	case int8:
+		return uint8(v)
+	case int16:
+		return uint16(v)
+	case int32:
+		if columnType == mysql.MYSQL_TYPE_INT24 {
+			return uint32(v) & 0xffffff
+		}
+		return uint32(v)
+	case int64:
+		return uint64(v)
+	}
+	return value
+}
+
+// ENUM 번호 (1부터, 0은 잘못된 값으로 저장된 빈 문자열)
+func enumValue(values []string, index int64) interface{} {
+	if index == 0 {
+		return ""
+	}
+	if index < 0 || int(index) > len(values) {
+		return index
+	}
+	return values[index-1]
+}
+
+// SET 비트마다 멤버 이름 (MySQL처럼 정의 순서로 쉼표 연결)
+func setValue(members []string, bits int64) string {
+	var names []string
+	for i, member := range members {
+		if bits&(1<<i) != 0 {
+			names = append(names, member)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// binary collation이면 바이트열, 아니면 문자열 (TEXT는 []byte, BINARY/VARBINARY는 string으로 읽히므로)
+func collatedValue(collation uint64, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if collation == binaryCollation {
+			return []byte(v)
+		}
+	case []byte:
+		if collation != binaryCollation {
+			return string(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,101 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// --output-template 파일 읽기
+// 템플릿은 이벤트(config.SQLEvent)를 받아 실행되고 텍스트 형식의 이벤트 블록 대신 출력됨
+func loadOutputTemplate(path string, renderer *SQLExtractor) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("출력 템플릿 파일 읽기 실패: %v", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(renderer)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("출력 템플릿 파싱 실패: %v", err)
+	}
+	return tmpl, nil
+}
+
+// 템플릿에서 쓸 수 있는 함수 (text/template 기본 함수 외)
+func templateFuncs(renderer *SQLExtractor) template.FuncMap {
+	return template.FuncMap{
+		"primaryKey": renderer.formatPrimaryKeys,
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"trim":    strings.TrimSpace,
+		"join":    strings.Join,
+		"replace": strings.ReplaceAll,
+	}
+}
+
+func validateOutputTemplate(cfg config.Config) error {
+	if cfg.OutputTemplate == "" {
+		return nil
+	}
+	if cfg.Format != "" && cfg.Format != FormatText {
+		return fmt.Errorf("--output-template은 --format text에서만 사용할 수 있습니다")
+	}
+	if cfg.Replayable {
+		return fmt.Errorf("--output-template은 --replayable과 함께 사용할 수 없습니다")
+	}
+	tmpl, err := loadOutputTemplate(cfg.OutputTemplate, NewSQLExtractor(cfg, nil))
+	if err != nil {
+		return err
+	}
+	// 없는 필드 이름 같은 오류는 실행해야 알 수 있으므로 빈 이벤트로 미리 실행
+	if err := tmpl.Execute(io.Discard, &config.SQLEvent{}); err != nil {
+		return fmt.Errorf("출력 템플릿 실행 실패: %v", err)
+	}
+	return nil
+}
+
+// 템플릿 출력기 (--output-template)
+// 이벤트마다 템플릿을 실행하고 출력이 줄바꿈으로 끝나지 않으면 붙임
+type templateWriter struct {
+	output io.Writer
+	tmpl   *template.Template
+	buf    bytes.Buffer
+}
+
+func newTemplateWriter(output io.Writer, path string, renderer *SQLExtractor) *templateWriter {
+	tmpl, err := loadOutputTemplate(path, renderer)
+	if err != nil {
+		// 검증 이후 파일이 바뀐 경우
+		logrus.Errorf("%v", err)
+	}
+	return &templateWriter{output: output, tmpl: tmpl}
+}
+
+func (w *templateWriter) writeEvent(event *config.SQLEvent) {
+	if w.tmpl == nil {
+		return
+	}
+	w.buf.Reset()
+	if err := w.tmpl.Execute(&w.buf, event); err != nil {
+		logrus.Warnf("출력 템플릿 실행 실패 (%s:%d): %v", event.Filename, event.StartPosition, err)
+		return
+	}
+	if w.buf.Len() > 0 && !bytes.HasSuffix(w.buf.Bytes(), []byte("\n")) {
+		w.buf.WriteByte('\n')
+	}
+	w.output.Write(w.buf.Bytes())
+}
+
+func (w *templateWriter) finish() {}
@@ -0,0 +1,49 @@
+package src
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// 진행 막대 폭 (터미널이 좁으면 줄여서 설명과 함께 한 줄에 들어가도록 함)
+const (
+	progressBarWidth    = 50
+	progressBarMinWidth = 10
+	progressLineReserve = 60 // 설명, 진행률, 개수 표시에 남겨 둘 칸 수
+)
+
+// 출력 대상 터미널 정보
+type terminalInfo struct {
+	terminal bool // 파일이나 파이프로 출력을 받는 중이 아님
+	ansi     bool // ANSI 이스케이프 코드 사용 가능 (Windows 콘솔은 가상 터미널 모드를 켤 수 있을 때)
+	width    int  // 알 수 없으면 0
+}
+
+// 터미널 정보 확인 (Windows 콘솔이면 ANSI 처리를 켬)
+func detectTerminal(w io.Writer) terminalInfo {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return terminalInfo{}
+	}
+
+	info := terminalInfo{terminal: true, ansi: enableVirtualTerminal(f)}
+	if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+		info.width = width
+	}
+	return info
+}
+
+// 색상 출력 여부 (ANSI를 쓸 수 있는 터미널이고 NO_COLOR가 없을 때)
+func (t terminalInfo) colors() bool {
+	return t.ansi && os.Getenv("NO_COLOR") == ""
+}
+
+// 진행 막대 폭
+func (t terminalInfo) progressBarWidth() int {
+	if t.width == 0 {
+		return progressBarWidth
+	}
+	return min(max(t.width-progressLineReserve, progressBarMinWidth), progressBarWidth)
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package src
+
+import "os"
+
+// Windows 외의 터미널은 ANSI 이스케이프 코드를 처리함
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package src
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// 콘솔의 가상 터미널 모드를 켜서 ANSI 이스케이프 코드를 처리하도록 함 (Windows 10 1511 이상)
+func enableVirtualTerminal(f *os.File) bool {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
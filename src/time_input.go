@@ -0,0 +1,88 @@
+package src
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// 시간대 데이터가 없는 컨테이너에서도 Asia/Seoul 같은 이름을 쓸 수 있도록 내장
+	_ "time/tzdata"
+)
+
+// 시간대가 들어 있는 형식 (소수점 이하 초는 초 뒤에 붙이면 모든 형식에서 허용)
+var zonedTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05 Z0700",
+}
+
+// 시간대가 없는 형식 (UTC 또는 뒤에 붙인 시간대 이름으로 해석)
+var localTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// 시간 입력 형식 안내 (형식 오류 메시지에 포함)
+const timeInputFormats = `사용할 수 있는 형식:
+  2024-01-15 10:00:00[.123456]     UTC
+  2024-01-15                       UTC 자정
+  2024-01-15T10:00:00+09:00        RFC 3339 (Z 또는 시차)
+  2024-01-15 10:00:00 +09:00       시차
+  2024-01-15 10:00:00 Asia/Seoul   시간대 이름
+  1705312800[.5]                   Unix epoch 초`
+
+// ParseTime --start-time/--end-time 같은 시간 입력 해석 (시간대가 없으면 UTC)
+func ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if t, ok := parseEpochSeconds(value); ok {
+		return t, nil
+	}
+	for _, layout := range zonedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	// 마지막 단어가 시간대 이름이면 그 시간대의 시각
+	location := time.UTC
+	if i := strings.LastIndex(value, " "); i > 0 {
+		if zone := value[i+1:]; strings.Contains(zone, "/") || strings.EqualFold(zone, "UTC") {
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				return time.Time{}, fmt.Errorf(T("알 수 없는 시간대입니다: %s"), zone)
+			}
+			location, value = loc, value[:i]
+		}
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q\n%s", value, T(timeInputFormats))
+}
+
+// 숫자만 있으면 Unix epoch 초 (소수점 이하 허용)
+func parseEpochSeconds(value string) (time.Time, bool) {
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" || strings.Trim(whole, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var nanos int64
+	if fraction != "" {
+		digits := (fraction + "000000000")[:9]
+		nanos, _ = strconv.ParseInt(digits, 10, 64)
+	}
+	return time.Unix(seconds, nanos).UTC(), true
+}
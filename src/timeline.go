@@ -0,0 +1,130 @@
+package src
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mysqlbinlogo/config"
+)
+
+// 시계열 구간 수 상한 (구간 폭이 너무 작으면 파일이 지나치게 커짐)
+const maxTimelineBuckets = 1000000
+
+// 시계열의 한 구간 (--timeline)
+type timelineBucket struct {
+	Time         time.Time `json:"time"` // 구간 시작 시각 (UTC)
+	Statements   int       `json:"statements"`
+	Transactions int       `json:"transactions"`
+	Rows         int       `json:"rows"`
+	QPS          float64   `json:"statements_per_sec"`
+	TPS          float64   `json:"transactions_per_sec"`
+}
+
+func validateTimeline(cfg config.Config) error {
+	if cfg.Timeline == "" {
+		return nil
+	}
+	if cfg.TimelineInterval <= 0 {
+		return fmt.Errorf("--timeline-interval은 0보다 커야 합니다")
+	}
+	if cfg.Follow {
+		return fmt.Errorf("--timeline은 --follow와 함께 사용할 수 없습니다")
+	}
+	if buckets := cfg.EndTime.Sub(cfg.StartTime) / cfg.TimelineInterval; buckets > maxTimelineBuckets {
+		return fmt.Errorf("--timeline 구간이 너무 많습니다 (%d개, 최대 %d개): --timeline-interval을 늘리세요", buckets, maxTimelineBuckets)
+	}
+	return nil
+}
+
+// 분석 구간 전체를 interval 단위로 나눠 구간마다 문장, 트랜잭션, 행 수 집계 (이벤트가 없는 구간도 0으로 포함)
+func buildTimeline(events []config.SQLEvent, start, end time.Time, interval time.Duration) []timelineBucket {
+	count := int((end.Sub(start) + interval - 1) / interval)
+	buckets := make([]timelineBucket, max(count, 1))
+	for i := range buckets {
+		buckets[i].Time = start.Add(interval * time.Duration(i)).UTC()
+	}
+
+	// 트랜잭션은 첫 이벤트가 속한 구간에서 한 번만 셈
+	seen := make(map[string]bool)
+	for i := range events {
+		event := &events[i]
+		// 초 단위 시각의 이벤트는 구간 시작보다 조금 이를 수 있으므로 첫 구간에 포함
+		index := max(int(event.Timestamp.Sub(start)/interval), 0)
+		if index >= len(buckets) {
+			continue
+		}
+		bucket := &buckets[index]
+		bucket.Statements++
+		bucket.Rows += event.RowCount
+
+		transaction := event.Transaction
+		if transaction == "" {
+			transaction = eventKey(event)
+		}
+		if !seen[transaction] {
+			seen[transaction] = true
+			bucket.Transactions++
+		}
+	}
+
+	seconds := interval.Seconds()
+	for i := range buckets {
+		buckets[i].QPS = float64(buckets[i].Statements) / seconds
+		buckets[i].TPS = float64(buckets[i].Transactions) / seconds
+	}
+	return buckets
+}
+
+// 시계열 파일 저장 (확장자가 .json이면 JSON 배열, 그 밖에는 CSV)
+func (ba *BinlogAnalyzer) writeTimeline(events []config.SQLEvent) error {
+	if ba.Config.Timeline == "" {
+		return nil
+	}
+
+	buckets := buildTimeline(events, ba.Config.StartTime, ba.Config.EndTime, ba.Config.TimelineInterval)
+	file, err := os.Create(ba.Config.Timeline)
+	if err != nil {
+		return fmt.Errorf("시계열 파일 생성 실패: %v", err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(ba.Config.Timeline), ".json") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(buckets)
+	} else {
+		err = writeTimelineCSV(file, buckets)
+	}
+	if err != nil {
+		return fmt.Errorf("시계열 파일 기록 실패: %v", err)
+	}
+
+	logrus.Infof(T("Timeline saved to %s (%d buckets of %s)"), ba.Config.Timeline, len(buckets), ba.Config.TimelineInterval)
+	return nil
+}
+
+func writeTimelineCSV(w io.Writer, buckets []timelineBucket) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "statements", "transactions", "rows", "statements_per_sec", "transactions_per_sec"})
+	for _, bucket := range buckets {
+		writer.Write([]string{
+			bucket.Time.Format(time.RFC3339Nano),
+			strconv.Itoa(bucket.Statements),
+			strconv.Itoa(bucket.Transactions),
+			strconv.Itoa(bucket.Rows),
+			strconv.FormatFloat(bucket.QPS, 'f', -1, 64),
+			strconv.FormatFloat(bucket.TPS, 'f', -1, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,21 @@
+package src
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// 웹 UI 정적 파일 (바이너리에 포함)
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// 웹 UI 핸들러 (/ 에서 index.html 제공)
+func uiHandler() http.Handler {
+	files, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(files)
+}
@@ -0,0 +1,241 @@
+"use strict";
+
+// 서버 모드 웹 UI: 분석 작업 제출, 진행 상황 표시, 결과 검색
+
+const pollInterval = 2000;
+const eventPageSize = 500;
+
+const form = document.getElementById("analysis-form");
+const targetSelect = document.getElementById("target");
+const jobsBody = document.querySelector("#jobs tbody");
+const eventsBody = document.querySelector("#events tbody");
+const searchInput = document.getElementById("search");
+const tokenInput = document.getElementById("token");
+
+let selectedJob = null;
+let searchTimer = null;
+
+// API 토큰은 브라우저에 보관하고 모든 요청에 Authorization 헤더로 전달
+tokenInput.value = localStorage.getItem("mysqlbinlogo-token") || "";
+tokenInput.addEventListener("change", () => {
+  localStorage.setItem("mysqlbinlogo-token", tokenInput.value);
+  loadTargets();
+  refreshJobs();
+});
+
+async function request(path, options) {
+  options = options || {};
+  if (tokenInput.value) {
+    options.headers = { Authorization: "Bearer " + tokenInput.value };
+  }
+  const response = await fetch(path, options);
+  if (response.status === 204) {
+    return null;
+  }
+  const body = await response.json();
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+  return body;
+}
+
+function element(tag, text, className) {
+  const el = document.createElement(tag);
+  if (text !== undefined) {
+    el.textContent = text;
+  }
+  if (className) {
+    el.className = className;
+  }
+  return el;
+}
+
+// datetime-local 값 (YYYY-MM-DDTHH:MM[:SS]) → YYYY-MM-DD HH:MM:SS
+function formatTime(value) {
+  const [date, time] = value.split("T");
+  return date + " " + (time.length === 5 ? time + ":00" : time);
+}
+
+function formatTimestamp(value) {
+  return value ? value.replace("T", " ").replace(/(\.\d+)?Z$/, "") : "";
+}
+
+async function loadTargets() {
+  let targets;
+  try {
+    targets = await request("targets");
+  } catch (err) {
+    document.getElementById("form-error").textContent = err.message;
+    return;
+  }
+  document.getElementById("form-error").textContent = "";
+  targetSelect.replaceChildren();
+  for (const target of targets) {
+    const label = (target.name || "default") + " (" + target.user + "@" + target.host + ":" + target.port + ")";
+    const option = element("option", label);
+    option.value = target.name;
+    targetSelect.append(option);
+  }
+  checkReady();
+}
+
+async function checkReady() {
+  const badge = document.getElementById("server-status");
+  const query = targetSelect.value ? "?target=" + encodeURIComponent(targetSelect.value) : "";
+  try {
+    await request("readyz" + query);
+    badge.textContent = "ready";
+    badge.className = "badge status-succeeded";
+  } catch (err) {
+    badge.textContent = "not ready";
+    badge.title = err.message;
+    badge.className = "badge status-failed";
+  }
+}
+
+form.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const data = new FormData(form);
+  const errorText = document.getElementById("form-error");
+  errorText.textContent = "";
+
+  const body = {
+    target: data.get("target") || "",
+    start_time: formatTime(data.get("start_time")),
+    end_time: formatTime(data.get("end_time")),
+    backend: data.get("backend"),
+    exclude_table_regex: data.get("exclude_table_regex"),
+    where: data.get("where").split("\n").map((line) => line.trim()).filter((line) => line !== ""),
+    replayable: data.get("replayable") === "on",
+  };
+
+  try {
+    await request("analyses", { method: "POST", body: JSON.stringify(body) });
+    refreshJobs();
+  } catch (err) {
+    errorText.textContent = err.message;
+  }
+});
+
+function progressCell(job) {
+  const cell = element("td");
+  const progress = job.progress;
+  if (!progress || job.status !== "running") {
+    if (progress && job.status === "succeeded") {
+      cell.textContent = progress.events + " events";
+    }
+    return cell;
+  }
+
+  const bar = element("progress");
+  bar.max = progress.bytes_total || 1;
+  bar.value = progress.bytes;
+  cell.append(bar, " " + progress.stage + ", " + progress.files_done + "/" + progress.files_total + " files");
+  if (progress.eta_seconds !== null) {
+    cell.append(", ETA " + Math.ceil(progress.eta_seconds) + "s");
+  }
+  return cell;
+}
+
+function actionCell(job) {
+  const cell = element("td");
+  if (job.status === "succeeded") {
+    const view = element("button", "View");
+    view.addEventListener("click", () => showResult(job.id));
+    cell.append(view, " ");
+  }
+
+  const finished = job.status === "succeeded" || job.status === "failed" || job.status === "canceled";
+  const remove = element("button", finished ? "Delete" : "Cancel", "secondary");
+  remove.addEventListener("click", async () => {
+    await request("analyses/" + job.id, { method: "DELETE" });
+    if (finished && selectedJob === job.id) {
+      document.getElementById("result").hidden = true;
+      selectedJob = null;
+    }
+    refreshJobs();
+  });
+  cell.append(remove);
+  return cell;
+}
+
+async function refreshJobs() {
+  let jobs;
+  try {
+    jobs = await request("analyses");
+  } catch (err) {
+    return;
+  }
+
+  jobsBody.replaceChildren();
+  for (const job of jobs) {
+    const row = element("tr", undefined, job.id === selectedJob ? "selected" : "");
+    const status = element("td");
+    status.append(element("span", job.status, "badge status-" + job.status));
+    if (job.error) {
+      status.title = job.error;
+      status.append(element("div", job.error, "error"));
+    }
+    row.append(
+      element("td", formatTimestamp(job.created_at)),
+      element("td", job.request.target || "default"),
+      element("td", job.request.start_time + " ~ " + job.request.end_time),
+      status,
+      progressCell(job),
+      actionCell(job),
+    );
+    jobsBody.append(row);
+  }
+}
+
+async function showResult(id) {
+  selectedJob = id;
+  document.getElementById("result").hidden = false;
+  document.getElementById("result-id").textContent = id;
+  // 다운로드 링크에는 헤더를 붙일 수 없으므로 쿼리 파라미터로 토큰 전달
+  const token = tokenInput.value ? "?access_token=" + encodeURIComponent(tokenInput.value) : "";
+  document.getElementById("download").href = "analyses/" + id + "/result" + token;
+  searchInput.value = "";
+  refreshJobs();
+  loadEvents();
+}
+
+async function loadEvents() {
+  if (!selectedJob) {
+    return;
+  }
+  const query = "?limit=" + eventPageSize + "&q=" + encodeURIComponent(searchInput.value);
+  const result = await request("analyses/" + selectedJob + "/events" + query);
+
+  const count = document.getElementById("result-count");
+  count.textContent = result.total > result.events.length
+    ? result.events.length + " of " + result.total + " events (download for all)"
+    : result.total + " events";
+
+  eventsBody.replaceChildren();
+  for (const ev of result.events) {
+    const row = element("tr");
+    row.append(
+      element("td", formatTimestamp(ev.timestamp)),
+      element("td", ev.event_type),
+      element("td", ev.database),
+      element("td", ev.table || ""),
+      element("td", ev.row_count || ""),
+      element("td", ev.filename),
+      element("td", ev.position),
+      element("td", ev.sql, "sql"),
+    );
+    eventsBody.append(row);
+  }
+}
+
+searchInput.addEventListener("input", () => {
+  clearTimeout(searchTimer);
+  searchTimer = setTimeout(loadEvents, 300);
+});
+
+targetSelect.addEventListener("change", checkReady);
+
+loadTargets();
+refreshJobs();
+setInterval(refreshJobs, pollInterval);
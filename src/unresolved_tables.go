@@ -0,0 +1,88 @@
+package src
+
+import (
+	"fmt"
+	"io"
+
+	"mysqlbinlogo/config"
+)
+
+// 컬럼 이름을 알 수 없어 col_N으로 출력한 row 이벤트 (테이블별 집계)
+type unresolvedTable struct {
+	name   string
+	reason string
+	events int
+	first  *config.SQLEvent
+	last   *config.SQLEvent
+}
+
+// 컬럼 이름을 알 수 없는 row 이벤트인지
+func columnsUnresolved(event *config.SQLEvent) bool {
+	return event.EventType != "QUERY" && event.Columns == nil && len(event.Rows) > 0
+}
+
+// 컬럼 이름을 알 수 없는 이유 (조회를 새로 하지 않고 스냅샷에 남은 결과로 판단)
+func unresolvedReason(schema *SchemaSnapshot, event *config.SQLEvent) string {
+	ts, ok := schema.cached(event.Database, event.Table)
+	switch {
+	case !ok:
+		return T("테이블 정보를 조회하지 않음")
+	case ts == nil:
+		return T("information_schema에 테이블이 없음 (삭제되었거나 권한 없음)")
+	case len(ts.Columns) != len(event.Rows[0]):
+		return fmt.Sprintf(T("컬럼 수 불일치 (이벤트 %d개, 현재 테이블 %d개, 구간 안의 DDL로도 복원 불가)"), len(event.Rows[0]), len(ts.Columns))
+	default:
+		return T("컬럼 구성을 알 수 없음")
+	}
+}
+
+// 컬럼 이름을 알 수 없는 row 이벤트를 테이블별로 집계 (처음 나온 순서)
+func (ba *BinlogAnalyzer) collectUnresolvedTables(events []config.SQLEvent) {
+	index := make(map[string]*unresolvedTable)
+	for i := range events {
+		event := &events[i]
+		if !columnsUnresolved(event) {
+			continue
+		}
+
+		name := qualifiedTableName(event)
+		table := index[name]
+		if table == nil {
+			table = &unresolvedTable{name: name, reason: unresolvedReason(ba.schema, event), first: event}
+			index[name] = table
+			ba.unresolved = append(ba.unresolved, table)
+		}
+		table.events++
+		table.last = event
+	}
+}
+
+// 결과 헤더에 컬럼 이름 없이 출력한 테이블 기록
+func (ba *BinlogAnalyzer) writeUnresolvedHeader(output io.Writer) {
+	if len(ba.unresolved) == 0 {
+		return
+	}
+
+	fmt.Fprintln(output, T("# Unresolved Table Metadata (row events shown with col_N):"))
+	for _, table := range ba.unresolved {
+		fmt.Fprintf(output, T("#   %s: %d row events, %s:%d ~ %s:%d")+"\n", table.name, table.events,
+			table.first.Filename, table.first.StartPosition, table.last.Filename, table.last.StartPosition)
+	}
+}
+
+// 요약에 컬럼 이름 없이 출력한 테이블 기록
+func (ba *BinlogAnalyzer) writeUnresolvedSummary(w io.Writer) {
+	if len(ba.unresolved) == 0 {
+		return
+	}
+
+	total := 0
+	for _, table := range ba.unresolved {
+		total += table.events
+	}
+	fmt.Fprintf(w, T(">> 테이블 메타데이터 없음: row 이벤트 %d개를 컬럼 이름 없이 col_N으로 출력했습니다\n"), total)
+	for _, table := range ba.unresolved {
+		fmt.Fprintf(w, T("   %s: %d개 (%s:%d ~ %s:%d) - %s\n"), table.name, table.events,
+			table.first.Filename, table.first.StartPosition, table.last.Filename, table.last.StartPosition, table.reason)
+	}
+}
@@ -0,0 +1,118 @@
+package src
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"mysqlbinlogo/config"
+)
+
+// 요약에 나열하는 충돌 묶음 수와 묶음마다 표시하는 트랜잭션 수
+const (
+	updateConflictLimit        = 20
+	updateConflictTransactions = 5
+)
+
+// 같은 행의 갱신 하나
+type rowUpdate struct {
+	at          time.Time
+	transaction string
+}
+
+// 같은 행을 서로 다른 트랜잭션이 --conflict-window 간격 안에 연달아 갱신한 묶음
+type updateConflict struct {
+	table, key   string
+	updates      int
+	transactions []string // 처음 갱신한 순서
+	first, last  time.Time
+}
+
+// 같은 PK를 서로 다른 트랜잭션이 window 안에 잇달아 갱신한 묶음 출력 (lost update, 재시도 폭주 후보, --conflict-window)
+// 갱신 사이 간격이 모두 window 이하인 연속 갱신을 한 묶음으로 보고, 트랜잭션이 둘 이상인 묶음만 보고
+func writeUpdateConflicts(w io.Writer, events []config.SQLEvent, renderer *SQLExtractor, window time.Duration) {
+	if window <= 0 {
+		return
+	}
+
+	type rowKey struct{ table, key string }
+	updates := make(map[rowKey][]rowUpdate)
+	forEachChangedRow(events, renderer, func(event *config.SQLEvent, table, key string) {
+		if event.EventType == "UPDATE" {
+			row := rowKey{table, key}
+			updates[row] = append(updates[row], rowUpdate{at: event.Timestamp, transaction: eventTransaction(event)})
+		}
+	})
+
+	var conflicts []*updateConflict
+	for row, list := range updates {
+		// 이벤트는 시간순으로 정렬되어 있으므로 list도 시간순
+		start := 0
+		for i := 1; i <= len(list); i++ {
+			if i < len(list) && list[i].at.Sub(list[i-1].at) <= window {
+				continue
+			}
+			if conflict := newUpdateConflict(row.table, row.key, list[start:i]); conflict != nil {
+				conflicts = append(conflicts, conflict)
+			}
+			start = i
+		}
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Fprintf(w, T(">> 충돌 가능성이 있는 갱신: %s 안에 같은 행을 갱신한 서로 다른 트랜잭션이 없습니다\n"), window)
+		return
+	}
+
+	slices.SortFunc(conflicts, func(a, b *updateConflict) int {
+		return cmp.Or(
+			cmp.Compare(len(b.transactions), len(a.transactions)),
+			cmp.Compare(b.updates, a.updates),
+			a.first.Compare(b.first),
+			cmp.Compare(a.table, b.table),
+			cmp.Compare(a.key, b.key),
+		)
+	})
+
+	fmt.Fprintf(w, T(">> 충돌 가능성이 있는 갱신 (%s 안에 같은 행을 갱신한 서로 다른 트랜잭션, %d건):\n"), window, len(conflicts))
+	for _, conflict := range conflicts[:min(len(conflicts), updateConflictLimit)] {
+		fmt.Fprintf(w, T("   %s %s  트랜잭션 %d개가 %s 동안 %d회 갱신 (%s ~ %s)\n"), conflict.table, conflict.key,
+			len(conflict.transactions), conflict.last.Sub(conflict.first), conflict.updates,
+			conflict.first.UTC().Format("2006-01-02 15:04:05.999999"), conflict.last.UTC().Format("2006-01-02 15:04:05.999999"))
+		shown := conflict.transactions[:min(len(conflict.transactions), updateConflictTransactions)]
+		line := "      " + strings.Join(shown, ", ")
+		if len(conflict.transactions) > len(shown) {
+			line += fmt.Sprintf(T(" 외 %d개"), len(conflict.transactions)-len(shown))
+		}
+		fmt.Fprintln(w, line)
+	}
+	if len(conflicts) > updateConflictLimit {
+		fmt.Fprintf(w, T("   외 %d건\n"), len(conflicts)-updateConflictLimit)
+	}
+}
+
+// 연속 갱신 묶음 (트랜잭션이 하나뿐이면 nil)
+func newUpdateConflict(table, key string, updates []rowUpdate) *updateConflict {
+	var transactions []string
+	seen := make(map[string]bool)
+	for _, update := range updates {
+		if !seen[update.transaction] {
+			seen[update.transaction] = true
+			transactions = append(transactions, update.transaction)
+		}
+	}
+	if len(transactions) < 2 {
+		return nil
+	}
+	return &updateConflict{
+		table:        table,
+		key:          key,
+		updates:      len(updates),
+		transactions: transactions,
+		first:        updates[0].at,
+		last:         updates[len(updates)-1].at,
+	}
+}
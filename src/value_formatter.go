@@ -1,10 +1,13 @@
 package src
 
 import (
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // 값을 SQL 문자열로 포맷
@@ -78,3 +81,73 @@ func (se *SQLExtractor) valuesEqual(a, b interface{}) bool {
 
 	return reflect.DeepEqual(a, b)
 }
+
+// 값을 재실행 가능한 SQL 리터럴로 변환 (잘라내지 않음)
+func (se *SQLExtractor) formatLiteral(val interface{}) string {
+	if val == nil {
+		return "NULL"
+	}
+
+	switch v := val.(type) {
+	case string:
+		return quoteString(v)
+
+	case []byte:
+		if utf8.Valid(v) {
+			return quoteString(string(v))
+		}
+		// 바이너리 데이터는 16진수 리터럴로 출력
+		if len(v) == 0 {
+			return "''"
+		}
+		return "0x" + hex.EncodeToString(v)
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+
+	case time.Time:
+		return fmt.Sprintf("'%s'", v.Format("2006-01-02 15:04:05.999999"))
+
+	default:
+		return quoteString(fmt.Sprintf("%v", v))
+	}
+}
+
+// 문자열을 MySQL 문자열 리터럴로 이스케이프
+func quoteString(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			sb.WriteString(`\0`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\'':
+			sb.WriteString(`\'`)
+		case 0x1a:
+			sb.WriteString(`\Z`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
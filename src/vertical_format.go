@@ -0,0 +1,67 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"mysqlbinlogo/config"
+)
+
+// 필드 이름 폭 (가장 긴 "Original SQL"에 맞춰 오른쪽 정렬)
+const verticalLabelWidth = 12
+
+// mysql 클라이언트의 \G와 같은 세로 출력기 (이벤트마다 "필드: 값" 블록, 긴 문장을 읽기 쉽게)
+type verticalWriter struct {
+	output   io.Writer
+	renderer *SQLExtractor
+	warnRows int // --warn-rows
+	count    int
+}
+
+func (w *verticalWriter) writeEvent(event *config.SQLEvent) {
+	w.count++
+	fmt.Fprintf(w.output, "*************************** %d. event ***************************\n", w.count)
+
+	w.field("Timestamp", event.Timestamp.Format("2006-01-02 15:04:05.999999"))
+	w.field("Server ID", fmt.Sprint(event.ServerId))
+	w.field("File", event.Filename)
+	w.field("Position", fmt.Sprint(event.StartPosition))
+	w.field("End Position", fmt.Sprint(event.Position))
+	w.field("Event Size", fmt.Sprintf("%d bytes", event.EventSize))
+	w.field("Exec Time", fmt.Sprintf("%ds", event.ExecTime))
+	if event.ErrorCode != 0 {
+		w.field("Error Code", describeErrorCode(event.ErrorCode))
+	}
+	if event.Transaction != "" {
+		w.field("Transaction", event.Transaction)
+	}
+	w.field("Database", event.Database)
+	if event.Table != "" {
+		w.field("Table", event.Table)
+	}
+	w.field("Type", event.EventType)
+
+	if event.EventType != "QUERY" {
+		w.field("Rows", fmt.Sprint(event.RowCount))
+		if exceedsWarnRows(event, w.warnRows) {
+			w.field("Warning", fmt.Sprintf("more than --warn-rows %d", w.warnRows))
+		}
+		if columnsUnresolved(event) {
+			w.field("Columns", "unknown, shown as col_N")
+		}
+		if pk := w.renderer.formatPrimaryKeys(event); pk != "" {
+			w.field("PK", pk)
+		}
+		if event.OriginalSQL != "" {
+			w.field("Original SQL", strings.TrimSpace(event.OriginalSQL))
+		}
+	}
+	w.field("SQL", event.SQL)
+}
+
+func (w *verticalWriter) field(label, value string) {
+	fmt.Fprintf(w.output, "%*s: %s\n", verticalLabelWidth, label, value)
+}
+
+func (w *verticalWriter) finish() {}
@@ -0,0 +1,47 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"mysqlbinlogo/config"
+)
+
+// 요약에 나열하는 행 수 경고 이벤트 수
+const warnRowsListLimit = 10
+
+// 변경 행 수가 --warn-rows를 넘는 row 이벤트인지 (UPDATE는 before/after 쌍을 한 행으로 셈)
+func exceedsWarnRows(event *config.SQLEvent, warnRows int) bool {
+	return warnRows > 0 && event.EventType != "QUERY" && event.RowCount > warnRows
+}
+
+// --warn-rows를 넘은 row 이벤트를 행 수가 많은 순서로 요약 출력
+func writeRowWarnings(w io.Writer, events []config.SQLEvent, warnRows int) {
+	if warnRows <= 0 {
+		return
+	}
+
+	var large []*config.SQLEvent
+	for i := range events {
+		if exceedsWarnRows(&events[i], warnRows) {
+			large = append(large, &events[i])
+		}
+	}
+	if len(large) == 0 {
+		fmt.Fprintf(w, T(">> 행 수 경고: --warn-rows %d를 넘은 row 이벤트가 없습니다\n"), warnRows)
+		return
+	}
+
+	sort.SliceStable(large, func(i, j int) bool {
+		return large[i].RowCount > large[j].RowCount
+	})
+	fmt.Fprintf(w, T(">> 행 수 경고: --warn-rows %d를 넘은 row 이벤트 %d개\n"), warnRows, len(large))
+	for _, event := range large[:min(len(large), warnRowsListLimit)] {
+		fmt.Fprintf(w, T("   %s %s %d행 (%s:%d, %s)\n"), event.EventType, qualifiedTableName(event), event.RowCount,
+			event.Filename, event.StartPosition, event.Timestamp.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if len(large) > warnRowsListLimit {
+		fmt.Fprintf(w, T("   외 %d개 이벤트\n"), len(large)-warnRowsListLimit)
+	}
+}